@@ -0,0 +1,151 @@
+// go-bank-app/accountnumber/accountnumber.go
+
+// Package accountnumber builds and validates customer account numbers.
+//
+// An account number is made of a branch prefix, a zero-padded sequence number and a check
+// digit block computed over the two, e.g. with prefix "001", 8 sequence digits and mod-97:
+//
+//	001 00000042 20
+//
+// The check digits catch single-digit typos and most transpositions, so a mistyped number
+// can be rejected before it is ever looked up.
+package accountnumber
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Algorithm selects how the check digits are computed.
+type Algorithm string
+
+const (
+	// Mod97 appends two check digits per ISO 7064 MOD 97-10 (the scheme used by IBAN).
+	Mod97 Algorithm = "mod97"
+	// Luhn appends a single Luhn (mod 10) check digit.
+	Luhn Algorithm = "luhn"
+)
+
+// Accepted account number lengths, matching the accounts.account_number column.
+const (
+	MinLength = 10
+	MaxLength = 20
+)
+
+var (
+	// ErrInvalidFormat is returned for numbers that are not 10-20 decimal digits.
+	ErrInvalidFormat = errors.New("account number must be 10-20 digits")
+	// ErrInvalidCheckDigit is returned when the check digits do not match the rest of the number.
+	ErrInvalidCheckDigit = errors.New("account number check digit is invalid")
+)
+
+// checkLen is the number of check digits the algorithm appends.
+func (a Algorithm) checkLen() int {
+	if a == Luhn {
+		return 1
+	}
+	return 2
+}
+
+// checkDigits computes the check digits for payload, which must consist of decimal digits only.
+func (a Algorithm) checkDigits(payload string) string {
+	if a == Luhn {
+		return string(rune('0' + luhnDigit(payload)))
+	}
+	return fmt.Sprintf("%02d", 98-mod97(payload+"00"))
+}
+
+// Scheme describes how new account numbers are laid out.
+type Scheme struct {
+	BranchPrefix   string    // Branch code, digits only
+	SequenceDigits int       // Width of the zero-padded sequence number
+	Algorithm      Algorithm // Check digit algorithm
+}
+
+// Length is the total length of numbers generated by the scheme.
+func (s Scheme) Length() int {
+	return len(s.BranchPrefix) + s.SequenceDigits + s.Algorithm.checkLen()
+}
+
+// Check reports whether the scheme itself can produce valid numbers.
+func (s Scheme) Check() error {
+	if !isDigits(s.BranchPrefix) {
+		return fmt.Errorf("branch prefix %q must be digits only", s.BranchPrefix)
+	}
+	if s.Algorithm != Mod97 && s.Algorithm != Luhn {
+		return fmt.Errorf("unknown account number check algorithm %q", s.Algorithm)
+	}
+	if s.SequenceDigits < 1 || s.Length() < MinLength || s.Length() > MaxLength {
+		return fmt.Errorf("account number scheme yields %d digits, want %d-%d", s.Length(), MinLength, MaxLength)
+	}
+	return nil
+}
+
+// Format builds the account number for sequence number seq.
+func (s Scheme) Format(seq int64) (string, error) {
+	if err := s.Check(); err != nil {
+		return "", err
+	}
+	body := fmt.Sprintf("%0*d", s.SequenceDigits, seq)
+	if seq < 1 || len(body) > s.SequenceDigits {
+		return "", fmt.Errorf("sequence %d does not fit in %d digits for branch %s", seq, s.SequenceDigits, s.BranchPrefix)
+	}
+	payload := s.BranchPrefix + body
+	return payload + s.Algorithm.checkDigits(payload), nil
+}
+
+// Validate checks the format and check digits of number. The branch prefix and sequence width are
+// not enforced, and the check digits may be those of any supported algorithm, so numbers issued
+// under an earlier prefix or algorithm stay valid.
+func (s Scheme) Validate(number string) error {
+	if len(number) < MinLength || len(number) > MaxLength || !isDigits(number) {
+		return ErrInvalidFormat
+	}
+	for _, a := range []Algorithm{s.Algorithm, Mod97, Luhn} {
+		split := len(number) - a.checkLen()
+		if a.checkDigits(number[:split]) == number[split:] {
+			return nil
+		}
+	}
+	return ErrInvalidCheckDigit
+}
+
+// isDigits reports whether s is a non-empty string of ASCII decimal digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// mod97 computes digits mod 97 one digit at a time, so numbers of any length work without big ints.
+func mod97(digits string) int {
+	r := 0
+	for i := 0; i < len(digits); i++ {
+		r = (r*10 + int(digits[i]-'0')) % 97
+	}
+	return r
+}
+
+// luhnDigit computes the Luhn check digit to append to payload.
+func luhnDigit(payload string) int {
+	sum := 0
+	double := true // The rightmost payload digit is doubled once the check digit is appended
+	for i := len(payload) - 1; i >= 0; i-- {
+		d := int(payload[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,102 @@
+package accountnumber
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		scheme Scheme
+		seq    int64
+		want   string
+	}{
+		{Scheme{BranchPrefix: "001", SequenceDigits: 8, Algorithm: Mod97}, 1, "0010000000146"},
+		{Scheme{BranchPrefix: "001", SequenceDigits: 8, Algorithm: Mod97}, 2, "0010000000243"},
+		{Scheme{BranchPrefix: "001", SequenceDigits: 8, Algorithm: Mod97}, 42, "0010000004220"},
+		// The textbook Luhn example: 7992739871 gets check digit 3
+		{Scheme{BranchPrefix: "799", SequenceDigits: 7, Algorithm: Luhn}, 2739871, "79927398713"},
+		{Scheme{BranchPrefix: "001", SequenceDigits: 8, Algorithm: Luhn}, 1, "001000000016"},
+	}
+	for _, tt := range tests {
+		got, err := tt.scheme.Format(tt.seq)
+		if err != nil {
+			t.Fatalf("%+v Format(%d): %v", tt.scheme, tt.seq, err)
+		}
+		if got != tt.want {
+			t.Errorf("%+v Format(%d) = %s, want %s", tt.scheme, tt.seq, got, tt.want)
+		}
+		if err := tt.scheme.Validate(got); err != nil {
+			t.Errorf("Validate(%s) of a formatted number: %v", got, err)
+		}
+	}
+}
+
+func TestFormatOutOfRange(t *testing.T) {
+	s := Scheme{BranchPrefix: "001", SequenceDigits: 8, Algorithm: Mod97}
+	for _, seq := range []int64{0, -1, 100000000} {
+		if got, err := s.Format(seq); err == nil {
+			t.Errorf("Format(%d) = %s, want error", seq, got)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		scheme Scheme
+		ok     bool
+	}{
+		{Scheme{BranchPrefix: "001", SequenceDigits: 8, Algorithm: Mod97}, true},
+		{Scheme{BranchPrefix: "001", SequenceDigits: 6, Algorithm: Luhn}, true},    // 10 digits, the minimum
+		{Scheme{BranchPrefix: "001", SequenceDigits: 5, Algorithm: Luhn}, false},   // 9 digits
+		{Scheme{BranchPrefix: "001", SequenceDigits: 16, Algorithm: Mod97}, false}, // 21 digits
+		{Scheme{BranchPrefix: "0A1", SequenceDigits: 8, Algorithm: Mod97}, false},
+		{Scheme{BranchPrefix: "001", SequenceDigits: 8, Algorithm: "crc"}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.scheme.Check(); (err == nil) != tt.ok {
+			t.Errorf("%+v Check() = %v, want ok=%v", tt.scheme, err, tt.ok)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	mod97 := Scheme{BranchPrefix: "001", SequenceDigits: 8, Algorithm: Mod97}
+	tests := []struct {
+		number string
+		want   error
+	}{
+		{"0010000000146", nil},
+		{"0020000000194", nil}, // Other branch prefix
+		{"79927398713", nil},   // Luhn check digit, accepted under a mod-97 scheme
+		{"0010000000147", ErrInvalidCheckDigit},
+		{"0010000000416", ErrInvalidCheckDigit}, // Last two digits swapped
+		{"0100000000146", ErrInvalidCheckDigit}, // Payload digits swapped
+		{"001000000", ErrInvalidFormat},         // Too short
+		{"001000000014600000000", ErrInvalidFormat},
+		{"00100000001A6", ErrInvalidFormat},
+		{"", ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		if err := mod97.Validate(tt.number); !errors.Is(err, tt.want) {
+			t.Errorf("Validate(%q) = %v, want %v", tt.number, err, tt.want)
+		}
+	}
+}
+
+func TestMod97CatchesSingleDigitTypos(t *testing.T) {
+	s := Scheme{BranchPrefix: "001", SequenceDigits: 8, Algorithm: Mod97}
+	number, _ := s.Format(123456)
+	for i := 0; i < len(number); i++ {
+		for d := byte('0'); d <= '9'; d++ {
+			if d == number[i] {
+				continue
+			}
+			typo := number[:i] + string(d) + number[i+1:]
+			split := len(typo) - 2
+			if Mod97.checkDigits(typo[:split]) == typo[split:] {
+				t.Errorf("mod-97 accepted typo %s of %s", typo, number)
+			}
+		}
+	}
+}
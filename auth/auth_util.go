@@ -2,20 +2,39 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"go-bank-app/config"
+	"go-bank-app/models"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Define custom claims
+// Define custom claims. RegisteredClaims.ID diisi dengan jti unik per token
+// sehingga access token bisa dicabut (revoke) sebelum kadaluarsa.
 type Claims struct {
-	UserID int `json:"user_id"`
+	UserID      int      `json:"user_id"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	Language    string   `json:"lang,omitempty"` // Preferensi bahasa user; kosong berarti ikut Accept-Language
 	jwt.RegisteredClaims
 }
 
+// HasPermission melaporkan apakah token memberi izin tertentu.
+func (c *Claims) HasPermission(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
 // HashPassword mengenkripsi password menggunakan bcrypt.
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -31,25 +50,82 @@ func CheckPasswordHash(password, hash string) bool {
 	return err == nil
 }
 
-// GenerateJWTToken membuat JWT baru untuk user yang diberikan.
-func GenerateJWTToken(userID int) (string, error) {
-	// Waktu kadaluarsa token (misal: 24 jam dari sekarang)
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateJWTToken membuat access token JWT baru untuk user yang diberikan.
+// Izin diturunkan dari role saat token diterbitkan, sehingga perubahan role
+// berlaku paling lambat saat access token berikutnya diterbitkan (refresh).
+// Claims dikembalikan agar pemanggil tahu jti dan waktu kadaluarsanya.
+func GenerateJWTToken(userID int, role, language string) (string, *Claims, error) {
+	// Waktu kadaluarsa token (singkat, lihat config.AppCfg.Auth.AccessTokenTTL)
+	now := time.Now()
+	expirationTime := now.Add(config.AppCfg.Auth.AccessTokenTTL)
+
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", nil, fmt.Errorf("gagal membuat jti: %w", err)
+	}
 
 	claims := &Claims{
-		UserID: userID,
+		UserID:      userID,
+		Role:        role,
+		Permissions: models.PermissionsForRole(role),
+		Language:    language,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(config.JWTSecretKey)
+	tokenString, err := token.SignedString(config.JWTSecretKey())
+	if err != nil {
+		return "", nil, fmt.Errorf("gagal menandatangani token: %w", err)
+	}
+
+	return tokenString, claims, nil
+}
+
+// ParseJWTToken memverifikasi tanda tangan dan masa berlaku token, lalu mengembalikan claims-nya.
+func ParseJWTToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// Memastikan metode penandatanganan cocok
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return config.JWTSecretKey(), nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("gagal menandatangani token: %w", err)
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("token tidak valid")
 	}
+	return claims, nil
+}
+
+// GenerateRefreshToken membuat refresh token acak (opaque, bukan JWT).
+// Yang disimpan di database hanya hash-nya, lihat HashToken.
+func GenerateRefreshToken() (string, error) {
+	return randomToken(32)
+}
 
-	return tokenString, nil
+// NewTokenFamilyID membuat ID keluarga token untuk satu sesi login.
+func NewTokenFamilyID() (string, error) {
+	return randomToken(16)
+}
+
+// HashToken menghasilkan hash SHA-256 (hex) dari sebuah token untuk disimpan di database.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
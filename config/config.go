@@ -1,16 +1,416 @@
 // go-bank-app/config/config.go
 package config
 
-import "os"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
 
-// JWTSecretKey adalah kunci rahasia untuk menandatangani JWT.
-// DI LINGKUNGAN PRODUKSI, INI HARUS DIBAWA DARI ENVIRONMENT VARIABLE ATAU SISTEM KONFIGURASI YANG AMAN!
-var JWTSecretKey = []byte(os.Getenv("JWT_SECRET_KEY"))
+	"go-bank-app/accountnumber"
+	"go-bank-app/dialect"
+	"go-bank-app/i18n"
+	"go-bank-app/interest"
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
 
-func init() {
-	if len(JWTSecretKey) == 0 {
-		// Default jika env var tidak diset. Ubah ini di produksi!
-		JWTSecretKey = []byte("supersecretjwtreallystrongkey")
-		// log.Println("WARNING: JWT_SECRET_KEY environment variable not set. Using default key. DO NOT USE IN PRODUCTION!")
+// Profil menentukan seberapa ketat konfigurasi diperiksa saat startup.
+const (
+	ProfileDevelopment = "development"
+	ProfileProduction  = "production"
+)
+
+// DefaultJWTSecret hanya layak untuk development. Profil production menolak berjalan dengan kunci ini.
+const DefaultJWTSecret = "supersecretjwtreallystrongkey"
+
+// AppConfig adalah seluruh konfigurasi aplikasi. Nilainya dibaca LoadConfig dari file YAML/TOML,
+// environment variable dan flag command line (lihat load.go dan config.example.yaml), lalu
+// diperiksa oleh Validate.
+type AppConfig struct {
+	Profile         string
+	Server          ServerConfig
+	Database        DatabaseConfig
+	Auth            AuthConfig
+	DefaultLanguage string // Bahasa respons error jika user tidak punya preferensi dan Accept-Language tidak cocok
+	AccountNumber   AccountNumberConfig
+	FX              FXConfig
+	FeeScheduleFile string // File JSON aturan biaya (lihat fee_schedule.example.json); kosong = tanpa biaya
+	StandingOrder   StandingOrderConfig
+	Approval        ApprovalConfig
+	Hold            HoldConfig
+	Idempotency     IdempotencyConfig
+	Limits          map[models.AccountType]*LimitConfig
+	Interest        InterestConfig
+	Overdraft       OverdraftConfig
+
+	// Hasil parse, diisi oleh Validate
+	dialect            dialect.Dialect
+	language           i18n.Language
+	approvalThresholds map[money.Currency]money.Money
+	limitDefaults      map[models.AccountType]LimitDefaults
+	interestPlans      map[models.AccountType]interest.Plan
+	overdraftFees      map[money.Currency]money.Money
+}
+
+// ServerConfig mengatur HTTP server.
+type ServerConfig struct {
+	Port int
+}
+
+// DatabaseConfig mengatur koneksi dan pool database. Driver memilih database yang dipakai (mysql,
+// postgres atau sqlite); jika DSN kosong, dipakai DSN bawaan driver tersebut. Jika AutoMigrate aktif,
+// InitDB menjalankan migrasi yang belum diterapkan sebelum aplikasi mulai melayani request.
+type DatabaseConfig struct {
+	Driver          string
+	DSN             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	AutoMigrate     bool
+}
+
+// AuthConfig mengatur penandatanganan JWT dan masa berlaku token. Access token sengaja dibuat
+// singkat; sesi diperpanjang lewat refresh token yang dirotasi setiap kali dipakai.
+type AuthConfig struct {
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// AccountNumberConfig adalah skema nomor akun yang dibuat server: kode cabang + nomor urut + check
+// digit (mod97 atau luhn).
+type AccountNumberConfig struct {
+	BranchPrefix   string
+	SequenceDigits int
+	CheckDigit     string
+}
+
+// FXConfig mengatur konversi valas pada transfer lintas mata uang. Jika RatesFile diisi, kurs dibaca
+// dari file JSON tersebut (bisa dipakai offline); jika kosong, kurs dibaca dari tabel fx_rates.
+// SpreadBps adalah margin bank dari kurs tengah dalam basis poin (50 = 0,5%).
+type FXConfig struct {
+	RatesFile string
+	SpreadBps int
+}
+
+// StandingOrderConfig: seberapa sering scheduler mencari order yang jatuh tempo, dan jeda antar
+// percobaan ulang untuk order dengan kebijakan "retry" saat saldo tidak cukup.
+type StandingOrderConfig struct {
+	PollInterval  time.Duration
+	RetryInterval time.Duration
+}
+
+// ApprovalConfig mengatur maker-checker: transfer dari akun yang punya approver dan nominalnya di
+// atas batas mata uangnya harus disetujui user kedua dalam TTL. Thresholds ditulis per mata uang,
+// mis. "IDR=100000000,USD=10000"; mata uang tanpa batas tidak memerlukan persetujuan.
+type ApprovalConfig struct {
+	Thresholds    string
+	TTL           time.Duration
+	SweepInterval time.Duration
+}
+
+// HoldConfig: hold otorisasi tanpa expires_at kadaluarsa setelah AuthorizationTTL; scheduler
+// melepas hold yang kadaluarsa setiap ExpirySweepInterval.
+type HoldConfig struct {
+	AuthorizationTTL    time.Duration
+	ExpirySweepInterval time.Duration
+}
+
+// IdempotencyConfig: hasil request dengan Idempotency-Key disimpan selama TTL lalu dihapus
+// scheduler setiap CleanupInterval. Kunci in_progress yang dipegang lebih lama dari LockTimeout
+// dianggap ditinggalkan request yang mati, dan retry dengan body yang sama boleh mengambil alih.
+type IdempotencyConfig struct {
+	TTL             time.Duration
+	LockTimeout     time.Duration
+	CleanupInterval time.Duration
+}
+
+// LimitConfig adalah batas debit default satu jenis akun. Nominal ditulis per mata uang seperti
+// ApprovalConfig.Thresholds; mata uang yang tidak tercantum tidak dibatasi. DailyTransfers = 0
+// berarti jumlah transfer harian tidak dibatasi. Setiap akun dapat meng-override batas ini lewat
+// PUT /accounts/:id/limits.
+type LimitConfig struct {
+	PerTransaction string
+	DailyDebit     string
+	MonthlyDebit   string
+	DailyTransfers int
+}
+
+// InterestConfig berisi rencana bunga awal per jenis akun. Rencana disalin ke akun saat dibuat dan
+// dapat diubah per akun lewat PUT /accounts/:id/interest-plan. Job bunga mencatat akrual harian untuk
+// hari yang sudah lewat setiap AccrualInterval dan mengkapitalisasinya di akhir bulan.
+type InterestConfig struct {
+	Plans           map[models.AccountType]*InterestPlanConfig
+	AccrualInterval time.Duration
+}
+
+// InterestPlanConfig adalah bunga tahunan dalam persen dan konvensi hitungan hari (ACT/365,
+// ACT/360 atau 30/360).
+type InterestPlanConfig struct {
+	Rate     string
+	DayCount string
+}
+
+// OverdraftConfig mengatur fasilitas cerukan untuk akun checking. InterestRate adalah bunga debit
+// tahunan default dalam persen atas saldo negatif, dihitung harian dan dikapitalisasi bulanan bersama
+// bunga simpanan. Fees adalah biaya per mata uang ("IDR=50000,USD=5") yang dikenakan saat saldo akun
+// pertama kali menjadi negatif; mata uang yang tidak tercantum tidak dikenai biaya.
+type OverdraftConfig struct {
+	InterestRate string
+	Fees         string
+}
+
+// Default mengembalikan konfigurasi bawaan, cocok untuk development di mesin lokal.
+func Default() *AppConfig {
+	return &AppConfig{
+		Profile: ProfileDevelopment,
+		Server:  ServerConfig{Port: 8080},
+		Database: DatabaseConfig{
+			Driver:          dialect.MySQL,
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+		},
+		Auth: AuthConfig{
+			JWTSecret:       DefaultJWTSecret,
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 7 * 24 * time.Hour,
+		},
+		DefaultLanguage: string(i18n.English),
+		AccountNumber:   AccountNumberConfig{BranchPrefix: "001", SequenceDigits: 8, CheckDigit: string(accountnumber.Mod97)},
+		FX:              FXConfig{SpreadBps: 50},
+		StandingOrder:   StandingOrderConfig{PollInterval: time.Minute, RetryInterval: time.Hour},
+		Approval: ApprovalConfig{
+			Thresholds:    "IDR=100000000,USD=10000,EUR=10000,SGD=10000",
+			TTL:           24 * time.Hour,
+			SweepInterval: time.Minute,
+		},
+		Hold:        HoldConfig{AuthorizationTTL: 7 * 24 * time.Hour, ExpirySweepInterval: time.Minute},
+		Idempotency: IdempotencyConfig{TTL: 24 * time.Hour, LockTimeout: 5 * time.Minute, CleanupInterval: time.Hour},
+		Limits: map[models.AccountType]*LimitConfig{
+			models.AccountTypeChecking: {
+				PerTransaction: "IDR=50000000,USD=5000,EUR=5000,SGD=7000",
+				DailyDebit:     "IDR=100000000,USD=10000,EUR=10000,SGD=14000",
+				MonthlyDebit:   "IDR=1000000000,USD=100000,EUR=100000,SGD=140000",
+				DailyTransfers: 50,
+			},
+			models.AccountTypeSavings: {
+				PerTransaction: "IDR=25000000,USD=2500,EUR=2500,SGD=3500",
+				DailyDebit:     "IDR=50000000,USD=5000,EUR=5000,SGD=7000",
+				MonthlyDebit:   "IDR=200000000,USD=20000,EUR=20000,SGD=28000",
+				DailyTransfers: 10,
+			},
+		},
+		Interest: InterestConfig{
+			Plans: map[models.AccountType]*InterestPlanConfig{
+				models.AccountTypeChecking: {Rate: "0", DayCount: string(interest.ACT365)},
+				models.AccountTypeSavings:  {Rate: "2.5", DayCount: string(interest.ACT365)},
+			},
+			AccrualInterval: time.Hour,
+		},
+		Overdraft: OverdraftConfig{InterestRate: "18", Fees: "IDR=50000,USD=5,EUR=5,SGD=7"},
+	}
+}
+
+// AppCfg adalah konfigurasi yang sedang dipakai. Sebelum LoadConfig dipanggil isinya Default().
+var AppCfg = mustDefault()
+
+func mustDefault() *AppConfig {
+	cfg := Default()
+	if err := cfg.Validate(); err != nil {
+		panic(fmt.Sprintf("invalid default configuration: %v", err))
+	}
+	return cfg
+}
+
+// Validate memeriksa seluruh nilai dan mengisi hasil parse yang dipakai fungsi-fungsi di bawah.
+// Semua kesalahan dilaporkan sekaligus agar konfigurasi bisa diperbaiki dalam sekali jalan.
+func (c *AppConfig) Validate() error {
+	var errs []error
+	fail := func(key string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	switch c.Profile {
+	case ProfileDevelopment:
+	case ProfileProduction:
+		if c.Auth.JWTSecret == DefaultJWTSecret {
+			fail("auth.jwt_secret", "the built-in default secret must not be used in the production profile")
+		}
+	default:
+		fail("profile", "%q: want %s or %s", c.Profile, ProfileDevelopment, ProfileProduction)
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		fail("server.port", "%d: must be between 1 and 65535", c.Server.Port)
+	}
+	d, err := dialect.Get(c.Database.Driver)
+	if err != nil {
+		fail("database.driver", "%v", err)
+	} else if c.Database.DSN == "" {
+		c.Database.DSN = d.DefaultDSN()
+	}
+	c.dialect = d
+	if c.Database.MaxOpenConns < 1 {
+		fail("database.max_open_conns", "%d: must be at least 1", c.Database.MaxOpenConns)
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		fail("database.max_idle_conns", "%d: must be between 0 and database.max_open_conns", c.Database.MaxIdleConns)
+	}
+	if c.Auth.JWTSecret == "" {
+		fail("auth.jwt_secret", "must not be empty")
+	}
+
+	lang, err := i18n.Parse(c.DefaultLanguage)
+	if err != nil {
+		fail("default_language", "%v", err)
+	}
+	c.language = lang
+
+	if c.FX.SpreadBps < 0 || c.FX.SpreadBps >= 10000 {
+		fail("fx.spread_bps", "%d: must be between 0 and 9999", c.FX.SpreadBps)
+	}
+	if err := c.AccountNumberScheme().Check(); err != nil {
+		fail("account_number", "%v", err)
+	}
+	if c.approvalThresholds, err = parseThresholds(c.Approval.Thresholds); err != nil {
+		fail("approval.thresholds", "%v", err)
+	}
+
+	c.limitDefaults = map[models.AccountType]LimitDefaults{}
+	for accountType, l := range c.Limits {
+		var d LimitDefaults
+		for _, f := range []struct {
+			name, value string
+			into        *map[money.Currency]money.Money
+		}{
+			{"per_transaction", l.PerTransaction, &d.PerTransaction},
+			{"daily_debit", l.DailyDebit, &d.DailyDebit},
+			{"monthly_debit", l.MonthlyDebit, &d.MonthlyDebit},
+		} {
+			if *f.into, err = parseThresholds(f.value); err != nil {
+				fail("limits."+string(accountType)+"."+f.name, "%v", err)
+			}
+		}
+		if l.DailyTransfers < 0 {
+			fail("limits."+string(accountType)+".daily_transfers", "%d: must not be negative", l.DailyTransfers)
+		}
+		d.DailyTransfers = l.DailyTransfers
+		c.limitDefaults[accountType] = d
+	}
+
+	if c.overdraftFees, err = parseThresholds(c.Overdraft.Fees); err != nil {
+		fail("overdraft.fees", "%v", err)
+	}
+	if _, err := interest.ParsePlan(c.Overdraft.InterestRate, string(interest.ACT365)); err != nil {
+		fail("overdraft.interest_rate", "%v", err)
+	}
+
+	c.interestPlans = map[models.AccountType]interest.Plan{}
+	for accountType, p := range c.Interest.Plans {
+		plan, err := interest.ParsePlan(p.Rate, p.DayCount)
+		if err != nil {
+			fail("interest."+string(accountType), "%v", err)
+		}
+		c.interestPlans[accountType] = plan
+	}
+
+	return errors.Join(errs...)
+}
+
+// JWTSecretKey mengembalikan kunci untuk menandatangani dan memverifikasi JWT.
+func JWTSecretKey() []byte {
+	return []byte(AppCfg.Auth.JWTSecret)
+}
+
+// Dialect mengembalikan dialect SQL untuk Database.Driver.
+func Dialect() dialect.Dialect {
+	return AppCfg.dialect
+}
+
+// DefaultLanguage mengembalikan bahasa respons bawaan (lihat AppConfig.DefaultLanguage).
+func DefaultLanguage() i18n.Language {
+	return AppCfg.language
+}
+
+// FXSpread mengembalikan FX.SpreadBps sebagai pecahan, mis. 50 -> 0.005.
+func FXSpread() *big.Rat {
+	return big.NewRat(int64(AppCfg.FX.SpreadBps), 10000)
+}
+
+// OverdraftFee mengembalikan biaya masuk cerukan untuk mata uang c (nol jika tidak ada).
+func OverdraftFee(c money.Currency) money.Money {
+	if fee, ok := AppCfg.overdraftFees[c]; ok {
+		return fee
+	}
+	return money.Zero(c)
+}
+
+// DefaultInterestPlan mengembalikan rencana bunga awal untuk jenis akun t (tanpa bunga untuk jenis tak dikenal).
+func DefaultInterestPlan(t models.AccountType) interest.Plan {
+	plan, ok := AppCfg.interestPlans[t]
+	if !ok {
+		return interest.Plan{DayCount: interest.ACT365}
+	}
+	return plan
+}
+
+// LimitDefaults adalah batas default satu jenis akun, hasil parse LimitConfig.
+type LimitDefaults struct {
+	PerTransaction map[money.Currency]money.Money
+	DailyDebit     map[money.Currency]money.Money
+	MonthlyDebit   map[money.Currency]money.Money
+	DailyTransfers int // 0 = tidak dibatasi
+}
+
+// AccountLimitDefaults mengembalikan batas default untuk jenis akun t (kosong untuk jenis tak dikenal).
+func AccountLimitDefaults(t models.AccountType) LimitDefaults {
+	return AppCfg.limitDefaults[t]
+}
+
+// TransferApprovalThreshold mengembalikan batas persetujuan untuk mata uang c, atau false jika tidak ada.
+func TransferApprovalThreshold(c money.Currency) (money.Money, bool) {
+	threshold, ok := AppCfg.approvalThresholds[c]
+	return threshold, ok
+}
+
+// parseThresholds membaca daftar "KODE=nominal" yang dipisah koma.
+func parseThresholds(s string) (map[money.Currency]money.Money, error) {
+	thresholds := map[money.Currency]money.Money{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		code, amount, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: want CURRENCY=amount", pair)
+		}
+		currency, err := money.ParseCurrency(strings.TrimSpace(code))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pair, err)
+		}
+		threshold, err := money.Parse(strings.TrimSpace(amount), currency)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pair, err)
+		}
+		thresholds[currency] = threshold
+	}
+	return thresholds, nil
+}
+
+// AccountNumberScheme mengembalikan skema nomor akun sesuai konfigurasi aktif.
+func AccountNumberScheme() accountnumber.Scheme {
+	return AppCfg.AccountNumberScheme()
+}
+
+// AccountNumberScheme mengembalikan skema nomor akun sesuai c.AccountNumber.
+func (c *AppConfig) AccountNumberScheme() accountnumber.Scheme {
+	return accountnumber.Scheme{
+		BranchPrefix:   c.AccountNumber.BranchPrefix,
+		SequenceDigits: c.AccountNumber.SequenceDigits,
+		Algorithm:      accountnumber.Algorithm(strings.ToLower(c.AccountNumber.CheckDigit)),
 	}
 }
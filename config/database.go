@@ -2,25 +2,46 @@
 package config
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
-	"time"
 
-	_ "github.com/go-sql-driver/mysql" // MySQL driver
+	"go-bank-app/migrations"
 )
 
 // DB is a global database connection instance accessible from other packages.
 var DB *sql.DB
 
-const DSN = "root:@tcp(127.0.0.1:3306)/bank_app_db?parseTime=true"
-
-// InitDB initializes the database connection.
+// InitDB initializes the database connection from AppCfg.Database and, if auto_migrate is enabled,
+// applies pending schema migrations.
 func InitDB() {
+	OpenDB()
+
+	if !AppCfg.Database.AutoMigrate {
+		return
+	}
+	migrator, err := migrations.New(DB, Dialect())
+	if err != nil {
+		log.Fatalf("Error loading migrations: %v", err)
+	}
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		log.Fatalf("Error applying migrations: %v", err)
+	}
+	for _, m := range applied {
+		log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+	}
+}
+
+// OpenDB connects to the database without touching the schema, as the migrate subcommand needs.
+func OpenDB() {
 	var err error
+	dbCfg := AppCfg.Database
 
-	// Open the database connection using the MySQL driver and DSN.
-	DB, err = sql.Open("mysql", DSN)
+	// Open the database connection using the configured driver and DSN. The dialect package
+	// registers the drivers.
+	DB, err = sql.Open(Dialect().DriverName(), dbCfg.DSN)
 	if err != nil {
 		log.Fatalf("Error opening database connection: %v", err)
 	}
@@ -28,13 +49,13 @@ func InitDB() {
 	// Check if the database connection is alive.
 	err = DB.Ping()
 	if err != nil {
-		log.Fatalf("Error pinging database: Make sure the MySQL server is running and the DSN is correct. Error: %v", err)
+		log.Fatalf("Error pinging database: Make sure the %s server is running and the DSN is correct. Error: %v", dbCfg.Driver, err)
 	}
 
-	fmt.Println("Successfully connected to the MySQL database!")
+	fmt.Printf("Successfully connected to the %s database!\n", Dialect().Name())
 
 	// Set connection pool settings (optional but recommended for performance)
-	DB.SetMaxOpenConns(10)                 // Maximum number of open connections to the database
-	DB.SetMaxIdleConns(5)                  // Maximum number of idle connections in the pool
-	DB.SetConnMaxLifetime(5 * time.Minute) // Maximum amount of time a connection may be reused
+	DB.SetMaxOpenConns(dbCfg.MaxOpenConns)       // Maximum number of open connections to the database
+	DB.SetMaxIdleConns(dbCfg.MaxIdleConns)       // Maximum number of idle connections in the pool
+	DB.SetConnMaxLifetime(dbCfg.ConnMaxLifetime) // Maximum amount of time a connection may be reused
 }
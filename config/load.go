@@ -0,0 +1,329 @@
+// go-bank-app/config/load.go
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"go-bank-app/models"
+)
+
+// Sumber konfigurasi, dari yang paling lemah: nilai Default, file config, environment variable,
+// lalu flag command line. Nilai dari sumber yang lebih kuat menimpa yang lebih lemah.
+//
+// File config dipilih lewat flag -config atau CONFIG_FILE; formatnya ditentukan dari ekstensi
+// (.yaml, .yml atau .toml). Kunci file berbentuk bertingkat ("database: {dsn: ...}"), flag memakai
+// kunci yang sama dengan titik dan tanda hubung (-database.dsn, -auth.access-token-ttl).
+
+// setting menghubungkan satu field AppConfig ke kunci file, environment variable dan flag-nya.
+type setting struct {
+	key  string                         // kunci di file config, mis. "database.max_open_conns"
+	env  string                         // environment variable, mis. "DB_MAX_OPEN_CONNS"
+	bind func(c *AppConfig) interface{} // pointer ke field: *string, *int, *bool atau *time.Duration
+}
+
+// flagName mengubah kunci file menjadi nama flag, mis. "auth.access_token_ttl" -> "auth.access-token-ttl".
+func (s setting) flagName() string {
+	return strings.ReplaceAll(s.key, "_", "-")
+}
+
+// set mem-parse value sesuai tipe field dan menyimpannya di c.
+func (s setting) set(c *AppConfig, value string) error {
+	switch field := s.bind(c).(type) {
+	case *string:
+		*field = value
+	case *int:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		*field = n
+	case *bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		*field = b
+	case *time.Duration:
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%q is not a positive duration such as 30s or 1h", value)
+		}
+		*field = d
+	default:
+		panic(fmt.Sprintf("config: unsupported type %T for %s", field, s.key))
+	}
+	return nil
+}
+
+// settings berisi semua field yang dapat diatur. Nama environment variable lama dipertahankan.
+var settings = buildSettings()
+
+func buildSettings() []setting {
+	s := []setting{
+		{"profile", "APP_PROFILE", func(c *AppConfig) interface{} { return &c.Profile }},
+		{"server.port", "SERVER_PORT", func(c *AppConfig) interface{} { return &c.Server.Port }},
+		{"database.driver", "DB_DRIVER", func(c *AppConfig) interface{} { return &c.Database.Driver }},
+		{"database.dsn", "DB_DSN", func(c *AppConfig) interface{} { return &c.Database.DSN }},
+		{"database.max_open_conns", "DB_MAX_OPEN_CONNS", func(c *AppConfig) interface{} { return &c.Database.MaxOpenConns }},
+		{"database.max_idle_conns", "DB_MAX_IDLE_CONNS", func(c *AppConfig) interface{} { return &c.Database.MaxIdleConns }},
+		{"database.conn_max_lifetime", "DB_CONN_MAX_LIFETIME", func(c *AppConfig) interface{} { return &c.Database.ConnMaxLifetime }},
+		{"database.auto_migrate", "DB_AUTO_MIGRATE", func(c *AppConfig) interface{} { return &c.Database.AutoMigrate }},
+		{"auth.jwt_secret", "JWT_SECRET_KEY", func(c *AppConfig) interface{} { return &c.Auth.JWTSecret }},
+		{"auth.access_token_ttl", "ACCESS_TOKEN_TTL", func(c *AppConfig) interface{} { return &c.Auth.AccessTokenTTL }},
+		{"auth.refresh_token_ttl", "REFRESH_TOKEN_TTL", func(c *AppConfig) interface{} { return &c.Auth.RefreshTokenTTL }},
+		{"default_language", "DEFAULT_LANGUAGE", func(c *AppConfig) interface{} { return &c.DefaultLanguage }},
+		{"account_number.branch_prefix", "ACCOUNT_BRANCH_PREFIX", func(c *AppConfig) interface{} { return &c.AccountNumber.BranchPrefix }},
+		{"account_number.sequence_digits", "ACCOUNT_SEQUENCE_DIGITS", func(c *AppConfig) interface{} { return &c.AccountNumber.SequenceDigits }},
+		{"account_number.check_digit", "ACCOUNT_CHECK_DIGIT", func(c *AppConfig) interface{} { return &c.AccountNumber.CheckDigit }},
+		{"fx.rates_file", "FX_RATES_FILE", func(c *AppConfig) interface{} { return &c.FX.RatesFile }},
+		{"fx.spread_bps", "FX_SPREAD_BPS", func(c *AppConfig) interface{} { return &c.FX.SpreadBps }},
+		{"fee_schedule_file", "FEE_SCHEDULE_FILE", func(c *AppConfig) interface{} { return &c.FeeScheduleFile }},
+		{"standing_order.poll_interval", "STANDING_ORDER_POLL_INTERVAL", func(c *AppConfig) interface{} { return &c.StandingOrder.PollInterval }},
+		{"standing_order.retry_interval", "STANDING_ORDER_RETRY_INTERVAL", func(c *AppConfig) interface{} { return &c.StandingOrder.RetryInterval }},
+		{"approval.thresholds", "TRANSFER_APPROVAL_THRESHOLDS", func(c *AppConfig) interface{} { return &c.Approval.Thresholds }},
+		{"approval.ttl", "TRANSFER_APPROVAL_TTL", func(c *AppConfig) interface{} { return &c.Approval.TTL }},
+		{"approval.sweep_interval", "TRANSFER_APPROVAL_SWEEP_INTERVAL", func(c *AppConfig) interface{} { return &c.Approval.SweepInterval }},
+		{"hold.authorization_ttl", "HOLD_AUTHORIZATION_TTL", func(c *AppConfig) interface{} { return &c.Hold.AuthorizationTTL }},
+		{"hold.expiry_sweep_interval", "HOLD_EXPIRY_SWEEP_INTERVAL", func(c *AppConfig) interface{} { return &c.Hold.ExpirySweepInterval }},
+		{"idempotency.ttl", "IDEMPOTENCY_TTL", func(c *AppConfig) interface{} { return &c.Idempotency.TTL }},
+		{"idempotency.lock_timeout", "IDEMPOTENCY_LOCK_TIMEOUT", func(c *AppConfig) interface{} { return &c.Idempotency.LockTimeout }},
+		{"idempotency.cleanup_interval", "IDEMPOTENCY_CLEANUP_INTERVAL", func(c *AppConfig) interface{} { return &c.Idempotency.CleanupInterval }},
+		{"interest.accrual_interval", "INTEREST_ACCRUAL_INTERVAL", func(c *AppConfig) interface{} { return &c.Interest.AccrualInterval }},
+		{"overdraft.interest_rate", "OVERDRAFT_INTEREST_RATE", func(c *AppConfig) interface{} { return &c.Overdraft.InterestRate }},
+		{"overdraft.fees", "OVERDRAFT_FEES", func(c *AppConfig) interface{} { return &c.Overdraft.Fees }},
+	}
+
+	for _, t := range []models.AccountType{models.AccountTypeChecking, models.AccountTypeSavings} {
+		key, env := string(t), strings.ToUpper(string(t))
+		s = append(s,
+			setting{"limits." + key + ".per_transaction", "LIMITS_" + env + "_PER_TRANSACTION", func(c *AppConfig) interface{} { return &c.Limits[t].PerTransaction }},
+			setting{"limits." + key + ".daily_debit", "LIMITS_" + env + "_DAILY_DEBIT", func(c *AppConfig) interface{} { return &c.Limits[t].DailyDebit }},
+			setting{"limits." + key + ".monthly_debit", "LIMITS_" + env + "_MONTHLY_DEBIT", func(c *AppConfig) interface{} { return &c.Limits[t].MonthlyDebit }},
+			setting{"limits." + key + ".daily_transfers", "LIMITS_" + env + "_DAILY_TRANSFERS", func(c *AppConfig) interface{} { return &c.Limits[t].DailyTransfers }},
+			setting{"interest." + key + ".rate", "INTEREST_" + env + "_RATE", func(c *AppConfig) interface{} { return &c.Interest.Plans[t].Rate }},
+			setting{"interest." + key + ".day_count", "INTEREST_" + env + "_DAY_COUNT", func(c *AppConfig) interface{} { return &c.Interest.Plans[t].DayCount }},
+		)
+	}
+	return s
+}
+
+// lookupSetting mencari setting berdasarkan kunci file.
+func lookupSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+var (
+	configFile string            // dari flag -config
+	flagValues map[string]string // kunci setting -> nilai, hanya untuk flag yang diberikan
+)
+
+// BindFlags mendaftarkan -config dan satu flag per setting pada fs. Panggil sebelum fs.Parse, lalu
+// LoadConfig setelahnya.
+func BindFlags(fs *flag.FlagSet) {
+	flagValues = map[string]string{}
+	fs.StringVar(&configFile, "config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	for _, s := range settings {
+		key := s.key
+		fs.Func(s.flagName(), "overrides "+key+" (env "+s.env+")", func(v string) error {
+			flagValues[key] = v
+			return nil
+		})
+	}
+}
+
+// LoadConfig membaca konfigurasi dari semua sumber, memvalidasinya dan memasangnya sebagai AppCfg.
+func LoadConfig() error {
+	cfg := Default()
+
+	path := configFile
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		values, err := readConfigFile(path)
+		if err != nil {
+			return err
+		}
+		for key, value := range values {
+			s, ok := lookupSetting(key)
+			if !ok {
+				return fmt.Errorf("%s: unknown setting %q", path, key)
+			}
+			if err := s.set(cfg, value); err != nil {
+				return fmt.Errorf("%s: %s: %w", path, key, err)
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if v, ok := os.LookupEnv(s.env); ok && v != "" {
+			if err := s.set(cfg, v); err != nil {
+				return fmt.Errorf("%s: %w", s.env, err)
+			}
+		}
+	}
+
+	for key, v := range flagValues {
+		s, _ := lookupSetting(key)
+		if err := s.set(cfg, v); err != nil {
+			return fmt.Errorf("-%s: %w", s.flagName(), err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	if cfg.Auth.JWTSecret == DefaultJWTSecret {
+		log.Println("WARNING: auth.jwt_secret (JWT_SECRET_KEY) not set. Using the built-in default key. DO NOT USE IN PRODUCTION!")
+	}
+	AppCfg = cfg
+	return nil
+}
+
+// readConfigFile membaca file YAML atau TOML menjadi pasangan kunci bertitik -> nilai.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	tree := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		tree, err = yamlTree(data)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, fmt.Errorf("%s: unsupported config file format, want .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	if err := flatten("", tree, values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+// yamlTree membaca dokumen YAML dengan setiap nilai skalar sebagai teks aslinya. Nominal seperti
+// 12345678901234567.89 tidak boleh lewat float64, yang hanya menyimpan sekitar 15 digit.
+func yamlTree(data []byte) (map[string]interface{}, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return map[string]interface{}{}, nil // File kosong
+	}
+	root := yamlValue(doc.Content[0])
+	tree, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("top level must be a mapping of settings")
+	}
+	return tree, nil
+}
+
+// yamlValue mengubah node YAML menjadi map, list, nil atau teks skalar apa adanya.
+func yamlValue(n *yaml.Node) interface{} {
+	switch n.Kind {
+	case yaml.AliasNode:
+		return yamlValue(n.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			m[n.Content[i].Value] = yamlValue(n.Content[i+1])
+		}
+		return m
+	case yaml.SequenceNode:
+		list := make([]interface{}, len(n.Content))
+		for i, item := range n.Content {
+			list[i] = yamlValue(item)
+		}
+		return list
+	}
+	if n.Tag == "!!null" {
+		return nil
+	}
+	return n.Value
+}
+
+// flatten meratakan tree menjadi kunci bertitik. Nominal per mata uang boleh ditulis sebagai map
+// ({IDR: 50000, USD: 5}); map seperti itu digabung menjadi "IDR=50000,USD=5".
+func flatten(prefix string, tree map[string]interface{}, into map[string]string) error {
+	for k, v := range tree {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if _, isSetting := lookupSetting(key); isSetting {
+				joined, err := joinAmounts(key, v)
+				if err != nil {
+					return err
+				}
+				into[key] = joined
+				continue
+			}
+			if err := flatten(key, v, into); err != nil {
+				return err
+			}
+		case []interface{}:
+			return errors.New(key + ": lists are not supported")
+		case nil:
+			into[key] = ""
+		default:
+			value, err := scalarText(key, v)
+			if err != nil {
+				return err
+			}
+			into[key] = value
+		}
+	}
+	return nil
+}
+
+// joinAmounts menulis map mata uang -> nominal dalam format "KODE=nominal" yang diurutkan.
+func joinAmounts(key string, m map[string]interface{}) (string, error) {
+	pairs := make([]string, 0, len(m))
+	for code, v := range m {
+		amount, err := scalarText(key+"."+code, v)
+		if err != nil {
+			return "", err
+		}
+		pairs = append(pairs, code+"="+amount)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ","), nil
+}
+
+// scalarText menulis nilai skalar sebagai teks. Parser TOML sudah mengubah bilangan pecahan menjadi
+// float64 sebelum kita melihatnya, sehingga nilai seperti itu ditolak: tulis sebagai string.
+func scalarText(key string, v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case float32, float64:
+		return "", fmt.Errorf("%s: write the decimal %v as a quoted string so it is not rounded through float64", key, v)
+	}
+	return fmt.Sprint(v), nil
+}
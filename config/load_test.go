@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadConfigFileYAMLKeepsScalarText(t *testing.T) {
+	path := writeConfig(t, "app.yaml", `
+server:
+  port: 9090
+account_number:
+  branch_prefix: 001
+approval:
+  thresholds: {IDR: 12345678901234567.89, USD: 2500.50, JPY: 1e6}
+overdraft:
+  interest_rate: 18.125
+  fees: ~
+fx:
+  rates_file: "rates.json"
+`)
+	got, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"server.port":                  "9090",
+		"account_number.branch_prefix": "001",
+		// float64 would have turned the IDR amount into 12345678901234568 and USD into 2500.5
+		"approval.thresholds":     "IDR=12345678901234567.89,JPY=1e6,USD=2500.50",
+		"overdraft.interest_rate": "18.125",
+		"overdraft.fees":          "",
+		"fx.rates_file":           "rates.json",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d settings %v, want %d", len(got), got, len(want))
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %q, want %q", key, got[key], value)
+		}
+	}
+}
+
+func TestReadConfigFileYAMLAnchors(t *testing.T) {
+	path := writeConfig(t, "app.yml", `
+amounts: &amounts {IDR: 1000000.25}
+limits:
+  checking:
+    per_transaction: *amounts
+`)
+	got, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := got["limits.checking.per_transaction"]; v != "IDR=1000000.25" {
+		t.Errorf("aliased amounts = %q, want IDR=1000000.25", v)
+	}
+}
+
+func TestReadConfigFileTOML(t *testing.T) {
+	path := writeConfig(t, "app.toml", `
+[server]
+port = 9090
+
+[approval]
+thresholds = { IDR = 100000000, USD = "2500.50" }
+`)
+	got, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := got["approval.thresholds"]; v != "IDR=100000000,USD=2500.50" {
+		t.Errorf("approval.thresholds = %q", v)
+	}
+	if v := got["server.port"]; v != "9090" {
+		t.Errorf("server.port = %q", v)
+	}
+}
+
+func TestReadConfigFileRejects(t *testing.T) {
+	tests := []struct {
+		name, file, content, wantErr string
+	}{
+		{"TOML float amount", "app.toml", "[approval]\nthresholds = { USD = 2500.50 }\n", "quoted string"},
+		{"TOML float setting", "app.toml", "[overdraft]\ninterest_rate = 18.5\n", "quoted string"},
+		{"list", "app.yaml", "approval:\n  thresholds: [1, 2]\n", "lists are not supported"},
+		{"not a mapping", "app.yaml", "- port\n", "mapping"},
+		{"unknown format", "app.json", "{}", "unsupported config file format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readConfigFile(writeConfig(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadConfigFileEmptyYAML(t *testing.T) {
+	got, err := readConfigFile(writeConfig(t, "app.yaml", "# nothing set\n"))
+	if err != nil || len(got) != 0 {
+		t.Errorf("got %v, %v; want no settings", got, err)
+	}
+}
+
+func TestExampleConfigIsValid(t *testing.T) {
+	values, err := readConfigFile(filepath.Join("..", "config.example.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Default()
+	for key, value := range values {
+		s, ok := lookupSetting(key)
+		if !ok {
+			t.Fatalf("unknown setting %q", key)
+		}
+		if err := s.set(cfg, value); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,140 @@
+// go-bank-app/dialect/dialect.go
+
+// Package dialect hides the differences between the SQL databases the app can run on: MySQL (the
+// production default), PostgreSQL and SQLite (for local development and tests). Repositories write
+// queries with MySQL-style ? placeholders and let the dialect rewrite them and fill in the parts
+// whose syntax differs.
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of the supported dialects, as used in the database.driver setting.
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+)
+
+// Querier is satisfied by *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Dialect describes one SQL database.
+type Dialect interface {
+	// Name is one of MySQL, Postgres or SQLite.
+	Name() string
+	// DriverName is the database/sql driver to open connections with.
+	DriverName() string
+	// DefaultDSN is used when no DSN is configured.
+	DefaultDSN() string
+
+	// Rebind rewrites the ? placeholders of query into the dialect's own style.
+	Rebind(query string) string
+	// InsertID runs an INSERT (written with ? placeholders) and returns the generated id column,
+	// via RETURNING or LastInsertId depending on the database.
+	InsertID(q Querier, query string, args ...interface{}) (int64, error)
+	// ForUpdate is the clause appended to a SELECT to lock the rows it reads until the transaction
+	// ends. It is empty for SQLite, which locks the whole database for writing instead.
+	ForUpdate() string
+	// LikeEscape is the clause that makes a backslash escape LIKE wildcards, appended after the pattern.
+	LikeEscape() string
+	// OnConflict is the clause appended to an INSERT ... VALUES that, when the row collides with an
+	// existing one on the unique key keys, sets the given columns to the values being inserted
+	// instead. With no columns an insert colliding on any unique key is skipped and keys only needs
+	// to name one key column.
+	OnConflict(keys []string, update ...string) string
+	// NowPlus is an expression for the database's current time plus a number of microseconds given
+	// by one ? placeholder. Negative offsets go back in time.
+	NowPlus() string
+	// Now is the database's current time, comparable with values written through NowPlus.
+	Now() string
+
+	// IsDuplicateKey reports whether err is a unique or primary key violation.
+	IsDuplicateKey(err error) bool
+	// IsForeignKeyViolation reports whether err is an insert or update referencing a missing row.
+	IsForeignKeyViolation(err error) bool
+	// IsRetryable reports whether the transaction that returned err lost a deadlock, timed out
+	// waiting for a lock or failed serialization, and may simply be run again.
+	IsRetryable(err error) bool
+
+	// TransactionalDDL reports whether schema changes can be rolled back as part of a transaction.
+	TransactionalDDL() bool
+	// Lock takes a named lock held by conn until Unlock, waiting at most timeout for another session
+	// to release it.
+	Lock(ctx context.Context, conn *sql.Conn, name string, timeout time.Duration) error
+	// Unlock releases a lock taken by Lock.
+	Unlock(ctx context.Context, conn *sql.Conn, name string) error
+	// TableExists reports whether the database (or, for PostgreSQL, the current schema) has a table
+	// called name.
+	TableExists(ctx context.Context, q Querier, name string) (bool, error)
+}
+
+// Get returns the dialect with the given name.
+func Get(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case MySQL:
+		return mysqlDialect{}, nil
+	case Postgres, "postgresql":
+		return postgresDialect{}, nil
+	case SQLite, "sqlite3":
+		return sqliteDialect{}, nil
+	}
+	return nil, fmt.Errorf("unsupported database driver %q, want %s, %s or %s", name, MySQL, Postgres, SQLite)
+}
+
+// rebindNumbered replaces each ? outside string literals and quoted identifiers with prefix
+// followed by its 1-based position, e.g. $1, $2.
+func rebindNumbered(query, prefix string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '?':
+			n++
+			b.WriteString(prefix)
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// onConflictExcluded renders the standard ON CONFLICT clause PostgreSQL and SQLite share.
+func onConflictExcluded(keys []string, update []string) string {
+	if len(update) == 0 {
+		return " ON CONFLICT DO NOTHING"
+	}
+	set := make([]string, len(update))
+	for i, column := range update {
+		set[i] = column + " = excluded." + column
+	}
+	return " ON CONFLICT (" + strings.Join(keys, ", ") + ") DO UPDATE SET " + strings.Join(set, ", ")
+}
+
+// lastInsertID runs query with Exec and returns the driver's LastInsertId.
+func lastInsertID(q Querier, query string, args ...interface{}) (int64, error) {
+	result, err := q.ExecContext(context.Background(), query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
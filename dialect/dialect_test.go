@@ -0,0 +1,126 @@
+package dialect
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		in      string
+		want    string
+	}{
+		{"postgres numbers placeholders", Postgres,
+			"SELECT id FROM accounts WHERE user_id = ? AND status <> ? LIMIT ?",
+			"SELECT id FROM accounts WHERE user_id = $1 AND status <> $2 LIMIT $3"},
+		{"postgres skips string literals", Postgres,
+			"SELECT '?' AS q, name FROM t WHERE a = ? AND b LIKE 'x?y'",
+			"SELECT '?' AS q, name FROM t WHERE a = $1 AND b LIKE 'x?y'"},
+		{"postgres skips escaped quotes", Postgres,
+			"SELECT 'it''s ?' FROM t WHERE a = ?",
+			"SELECT 'it''s ?' FROM t WHERE a = $1"},
+		{"postgres skips quoted identifiers", Postgres,
+			`SELECT "odd?name" FROM t WHERE a = ?`,
+			`SELECT "odd?name" FROM t WHERE a = $1`},
+		{"postgres numbers past nine", Postgres,
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)"},
+		{"postgres without placeholders", Postgres, "SELECT 1", "SELECT 1"},
+		{"mysql keeps question marks", MySQL, "SELECT ? FROM t WHERE a = ?", "SELECT ? FROM t WHERE a = ?"},
+		{"sqlite keeps question marks", SQLite, "SELECT ? FROM t WHERE a = ?", "SELECT ? FROM t WHERE a = ?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Get(tt.dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := d.Rebind(tt.in); got != tt.want {
+				t.Errorf("Rebind(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebindNowPlus(t *testing.T) {
+	// The placeholder inside the interval expression takes its place in the numbering
+	d, _ := Get(Postgres)
+	got := d.Rebind("UPDATE leases SET expires_at = " + d.NowPlus() + " WHERE name = ?")
+	want := "UPDATE leases SET expires_at = (clock_timestamp() + CAST($1 AS BIGINT) * INTERVAL '1 microsecond') WHERE name = $2"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestOnConflict(t *testing.T) {
+	tests := []struct {
+		dialect string
+		keys    []string
+		update  []string
+		want    string
+	}{
+		{MySQL, []string{"name"}, nil, " ON DUPLICATE KEY UPDATE name = name"},
+		{MySQL, []string{"account_id"}, []string{"a", "b"}, " ON DUPLICATE KEY UPDATE a = VALUES(a), b = VALUES(b)"},
+		{Postgres, []string{"name"}, nil, " ON CONFLICT DO NOTHING"},
+		{Postgres, []string{"account_id"}, []string{"a", "b"}, " ON CONFLICT (account_id) DO UPDATE SET a = excluded.a, b = excluded.b"},
+		{SQLite, []string{"code", "currency"}, []string{"a"}, " ON CONFLICT (code, currency) DO UPDATE SET a = excluded.a"},
+	}
+	for _, tt := range tests {
+		d, _ := Get(tt.dialect)
+		if got := d.OnConflict(tt.keys, tt.update...); got != tt.want {
+			t.Errorf("%s OnConflict(%v, %v) = %q, want %q", tt.dialect, tt.keys, tt.update, got, tt.want)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	for name, want := range map[string]string{"MySQL": MySQL, "postgresql": Postgres, "sqlite3": SQLite} {
+		d, err := Get(name)
+		if err != nil || d.Name() != want {
+			t.Errorf("Get(%q) = %v, %v; want %s", name, d, err, want)
+		}
+	}
+	if _, err := Get("oracle"); err == nil {
+		t.Error("Get(oracle) succeeded, want error")
+	}
+}
+
+func TestUTCTimeArgs(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: time.Date(2024, 1, 2, 10, 4, 5, 999, jakarta)},
+		{Ordinal: 2, Value: int64(7)},
+	}
+	got := utcTimeArgs(args)
+	if got[0].Value != "2024-01-02 03:04:05" {
+		t.Errorf("time arg = %v, want 2024-01-02 03:04:05", got[0].Value)
+	}
+	if got[1].Value != int64(7) {
+		t.Errorf("non-time arg changed to %v", got[1].Value)
+	}
+}
+
+func TestDecimalText(t *testing.T) {
+	// Variables, not constants: Go folds constant expressions exactly
+	thousand, fee, tenth, fifth := 1000.0, 8.68, 0.1, 0.2
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{thousand - fee, "991.32"},
+		{tenth + fifth, "0.3"},
+		{12345678901.23, "12345678901.23"},
+		{-0.05, "-0.05"},
+		{0.000123, "0.000123"},
+		{1e15, "1000000000000000"},
+		{0, "0"},
+	}
+	for _, tt := range tests {
+		if got := decimalText(tt.in); got != tt.want {
+			t.Errorf("decimalText(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
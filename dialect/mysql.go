@@ -0,0 +1,105 @@
+// go-bank-app/dialect/mysql.go
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL error numbers the dialect translates.
+const (
+	mysqlErrLockWaitTimeout = 1205 // ER_LOCK_WAIT_TIMEOUT
+	mysqlErrDeadlock        = 1213 // ER_LOCK_DEADLOCK
+	mysqlErrDuplicateEntry  = 1062 // ER_DUP_ENTRY
+	mysqlErrNoReferencedRow = 1452 // ER_NO_REFERENCED_ROW_2: foreign key points at a missing row
+)
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string       { return MySQL }
+func (mysqlDialect) DriverName() string { return "mysql" }
+func (mysqlDialect) DefaultDSN() string {
+	return "root:@tcp(127.0.0.1:3306)/bank_app_db?parseTime=true"
+}
+
+// Rebind keeps ? placeholders, which are MySQL's own.
+func (mysqlDialect) Rebind(query string) string {
+	return query
+}
+
+func (mysqlDialect) InsertID(q Querier, query string, args ...interface{}) (int64, error) {
+	return lastInsertID(q, query, args...)
+}
+
+func (mysqlDialect) ForUpdate() string  { return " FOR UPDATE" }
+func (mysqlDialect) LikeEscape() string { return "" } // Backslash is MySQL's default LIKE escape
+
+// OnConflict uses ON DUPLICATE KEY UPDATE, which MySQL applies to a collision on any unique key;
+// keys only names the column a skipped insert assigns to itself.
+func (mysqlDialect) OnConflict(keys []string, update ...string) string {
+	if len(update) == 0 {
+		return " ON DUPLICATE KEY UPDATE " + keys[0] + " = " + keys[0]
+	}
+	set := make([]string, len(update))
+	for i, column := range update {
+		set[i] = column + " = VALUES(" + column + ")"
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(set, ", ")
+}
+
+func (mysqlDialect) NowPlus() string { return "DATE_ADD(NOW(6), INTERVAL ? MICROSECOND)" }
+func (mysqlDialect) Now() string     { return "NOW(6)" }
+
+func (mysqlDialect) IsDuplicateKey(err error) bool {
+	return mysqlErrorNumber(err) == mysqlErrDuplicateEntry
+}
+
+func (mysqlDialect) IsForeignKeyViolation(err error) bool {
+	return mysqlErrorNumber(err) == mysqlErrNoReferencedRow
+}
+
+func (mysqlDialect) IsRetryable(err error) bool {
+	n := mysqlErrorNumber(err)
+	return n == mysqlErrDeadlock || n == mysqlErrLockWaitTimeout
+}
+
+// TransactionalDDL is false: MySQL commits implicitly before and after every DDL statement.
+func (mysqlDialect) TransactionalDDL() bool { return false }
+
+// Lock uses a named lock. Named locks are server-wide, so the current database name is prepended.
+func (mysqlDialect) Lock(ctx context.Context, conn *sql.Conn, name string, timeout time.Duration) error {
+	var got sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(CONCAT(DATABASE(), '/', ?), ?)", name, int(timeout.Seconds())).Scan(&got); err != nil {
+		return err
+	}
+	if !got.Valid || got.Int64 != 1 {
+		return fmt.Errorf("timed out after %s waiting for lock %q", timeout, name)
+	}
+	return nil
+}
+
+func (mysqlDialect) Unlock(ctx context.Context, conn *sql.Conn, name string) error {
+	_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(CONCAT(DATABASE(), '/', ?))", name)
+	return err
+}
+
+func (mysqlDialect) TableExists(ctx context.Context, q Querier, name string) (bool, error) {
+	var n int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", name).Scan(&n)
+	return n > 0, err
+}
+
+// mysqlErrorNumber returns the MySQL error number of err, or 0 if it is not a MySQL error.
+func mysqlErrorNumber(err error) uint16 {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number
+	}
+	return 0
+}
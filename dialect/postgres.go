@@ -0,0 +1,118 @@
+// go-bank-app/dialect/postgres.go
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers the "pgx" database/sql driver
+)
+
+// PostgreSQL SQLSTATE codes the dialect translates.
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgLockNotAvailable     = "55P03"
+)
+
+// pgLockPollInterval is how often Lock retries pg_try_advisory_lock while another session holds it.
+const pgLockPollInterval = 250 * time.Millisecond
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string       { return Postgres }
+func (postgresDialect) DriverName() string { return "pgx" }
+func (postgresDialect) DefaultDSN() string {
+	return "postgres://postgres@127.0.0.1:5432/bank_app_db?sslmode=disable"
+}
+
+func (postgresDialect) Rebind(query string) string {
+	return rebindNumbered(query, "$")
+}
+
+// InsertID appends RETURNING id, since the pgx driver does not implement LastInsertId.
+func (d postgresDialect) InsertID(q Querier, query string, args ...interface{}) (int64, error) {
+	var id int64
+	err := q.QueryRowContext(context.Background(), d.Rebind(query)+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (postgresDialect) ForUpdate() string  { return " FOR UPDATE" }
+func (postgresDialect) LikeEscape() string { return "" } // Backslash is PostgreSQL's default LIKE escape
+
+func (postgresDialect) OnConflict(keys []string, update ...string) string {
+	return onConflictExcluded(keys, update)
+}
+
+// NowPlus and Now use clock_timestamp, since CURRENT_TIMESTAMP stays at the start of the transaction.
+func (postgresDialect) NowPlus() string {
+	return "(clock_timestamp() + CAST(? AS BIGINT) * INTERVAL '1 microsecond')"
+}
+func (postgresDialect) Now() string { return "clock_timestamp()" }
+
+func (postgresDialect) IsDuplicateKey(err error) bool {
+	return pgErrorCode(err) == pgUniqueViolation
+}
+
+func (postgresDialect) IsForeignKeyViolation(err error) bool {
+	return pgErrorCode(err) == pgForeignKeyViolation
+}
+
+func (postgresDialect) IsRetryable(err error) bool {
+	switch pgErrorCode(err) {
+	case pgSerializationFailure, pgDeadlockDetected, pgLockNotAvailable:
+		return true
+	}
+	return false
+}
+
+func (postgresDialect) TransactionalDDL() bool { return true }
+
+// Lock uses a session-level advisory lock keyed by a hash of name. pg_advisory_lock would wait
+// forever, so pg_try_advisory_lock is polled until timeout instead.
+func (postgresDialect) Lock(ctx context.Context, conn *sql.Conn, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var got bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", name).Scan(&got); err != nil {
+			return err
+		}
+		if got {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for lock %q", timeout, name)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pgLockPollInterval):
+		}
+	}
+}
+
+func (postgresDialect) Unlock(ctx context.Context, conn *sql.Conn, name string) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", name)
+	return err
+}
+
+func (postgresDialect) TableExists(ctx context.Context, q Querier, name string) (bool, error) {
+	var n int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1", name).Scan(&n)
+	return n > 0, err
+}
+
+// pgErrorCode returns the SQLSTATE of err, or "" if it is not a PostgreSQL error.
+func pgErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+// go-bank-app/dialect/sqlite.go
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"modernc.org/sqlite" // Pure Go, no cgo; wrapped in sqlite_driver.go
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string       { return SQLite }
+func (sqliteDialect) DriverName() string { return sqliteDriverName }
+
+// DefaultDSN keeps the database in bank_app.db in the working directory. Foreign keys are off in
+// SQLite unless enabled per connection; busy_timeout makes writers wait for each other instead of
+// failing, and immediate transactions take the write lock up front so two read-then-write
+// transactions cannot deadlock.
+func (sqliteDialect) DefaultDSN() string {
+	return "file:bank_app.db?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate"
+}
+
+// Rebind keeps ? placeholders, which SQLite understands.
+func (sqliteDialect) Rebind(query string) string {
+	return query
+}
+
+func (sqliteDialect) InsertID(q Querier, query string, args ...interface{}) (int64, error) {
+	return lastInsertID(q, query, args...)
+}
+
+// ForUpdate is empty: SQLite has no row locks, and with _txlock=immediate every transaction already
+// holds the database write lock from its first statement.
+func (sqliteDialect) ForUpdate() string  { return "" }
+func (sqliteDialect) LikeEscape() string { return ` ESCAPE '\'` }
+
+func (sqliteDialect) OnConflict(keys []string, update ...string) string {
+	return onConflictExcluded(keys, update)
+}
+
+// NowPlus and Now render UTC text with milliseconds. SQLite has no date type and compares these
+// values as text, so they must only be compared with each other (the lease columns).
+func (sqliteDialect) NowPlus() string {
+	return "strftime('%Y-%m-%d %H:%M:%f', 'now', printf('%+.6f seconds', ? / 1000000.0))"
+}
+func (sqliteDialect) Now() string { return "strftime('%Y-%m-%d %H:%M:%f', 'now')" }
+
+func (sqliteDialect) IsDuplicateKey(err error) bool {
+	code := sqliteErrorCode(err)
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+func (sqliteDialect) IsForeignKeyViolation(err error) bool {
+	return sqliteErrorCode(err) == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
+
+func (sqliteDialect) IsRetryable(err error) bool {
+	// Extended codes such as SQLITE_BUSY_SNAPSHOT keep the primary code in the low byte
+	code := sqliteErrorCode(err) & 0xff
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
+
+func (sqliteDialect) TransactionalDDL() bool { return true }
+
+// Lock is a no-op: SQLite lets only one connection write at a time and a migration runs in one
+// transaction, so two migrators cannot interleave. If both picked the same pending version, the
+// second fails to record it and rolls back without changing the schema.
+func (sqliteDialect) Lock(ctx context.Context, conn *sql.Conn, name string, timeout time.Duration) error {
+	return nil
+}
+
+func (sqliteDialect) Unlock(ctx context.Context, conn *sql.Conn, name string) error {
+	return nil
+}
+
+func (sqliteDialect) TableExists(ctx context.Context, q Querier, name string) (bool, error) {
+	var n int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&n)
+	return n > 0, err
+}
+
+// sqliteErrorCode returns the extended result code of err, or 0 if it is not an SQLite error.
+func sqliteErrorCode(err error) int {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()
+	}
+	return 0
+}
@@ -0,0 +1,128 @@
+// go-bank-app/dialect/sqlite_driver.go
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteDriverName is modernc's driver adjusted for the two places where SQLite's loose typing
+// shows through:
+//
+//   - time.Time arguments are bound as UTC text in the layout CURRENT_TIMESTAMP writes. SQLite
+//     compares timestamps as text, so a column default and a Go time must be spelled the same way;
+//     the driver's own layout adds fractions and a zone offset, which makes "2024-01-02 03:04:05"
+//     sort before the same instant passed from Go.
+//   - REAL results are returned as decimal text with 15 significant digits. SQLite keeps DECIMAL
+//     columns as binary floats, so 1000 - 8.68 reads back as 991.3199999999999; 15 digits is what
+//     a float64 holds exactly, which drops the residue and leaves a string money.Parse accepts.
+const sqliteDriverName = "sqlite-bank"
+
+// sqliteTimeLayout matches CURRENT_TIMESTAMP. Like MySQL TIMESTAMP columns it keeps whole seconds.
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+func init() {
+	sql.Register(sqliteDriverName, sqliteBankDriver{&sqlite.Driver{}})
+}
+
+type sqliteBankDriver struct{ driver.Driver }
+
+func (d sqliteBankDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return sqliteBankConn{c.(sqliteConn)}, nil
+}
+
+// sqliteConn lists the optional interfaces of modernc's connection that database/sql uses, so the
+// wrapper keeps them.
+type sqliteConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+type sqliteBankConn struct{ sqliteConn }
+
+func (c sqliteBankConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.sqliteConn.ExecContext(ctx, query, utcTimeArgs(args))
+}
+
+func (c sqliteBankConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.sqliteConn.QueryContext(ctx, query, utcTimeArgs(args))
+	if err != nil {
+		return nil, err
+	}
+	return decimalRows{rows}, nil
+}
+
+func (c sqliteBankConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	s, err := c.sqliteConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return sqliteBankStmt{s.(sqliteStmt)}, nil
+}
+
+type sqliteStmt interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+}
+
+type sqliteBankStmt struct{ sqliteStmt }
+
+func (s sqliteBankStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.sqliteStmt.ExecContext(ctx, utcTimeArgs(args))
+}
+
+func (s sqliteBankStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := s.sqliteStmt.QueryContext(ctx, utcTimeArgs(args))
+	if err != nil {
+		return nil, err
+	}
+	return decimalRows{rows}, nil
+}
+
+// decimalRows turns every float64 column value into decimal text, see sqliteDriverName.
+type decimalRows struct{ driver.Rows }
+
+func (r decimalRows) Next(dest []driver.Value) error {
+	if err := r.Rows.Next(dest); err != nil {
+		return err
+	}
+	for i, v := range dest {
+		if f, ok := v.(float64); ok {
+			dest[i] = decimalText(f)
+		}
+	}
+	return nil
+}
+
+// decimalText formats f in plain notation with at most 15 significant digits.
+func decimalText(f float64) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'g', 15, 64), 64)
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// utcTimeArgs replaces every time.Time in args with its sqliteTimeLayout text. database/sql builds
+// a fresh slice for each call, so it is safe to change in place.
+func utcTimeArgs(args []driver.NamedValue) []driver.NamedValue {
+	for i, arg := range args {
+		if t, ok := arg.Value.(time.Time); ok {
+			args[i].Value = t.UTC().Format(sqliteTimeLayout)
+		}
+	}
+	return args
+}
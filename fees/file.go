@@ -0,0 +1,122 @@
+// go-bank-app/fees/file.go
+package fees
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"go-bank-app/money"
+)
+
+// scheduleFile is the on-disk format read by LoadSchedule. Amounts are decimals in the rule's
+// currency and percentages are percent (0.1 = 0.1%):
+//
+//	{
+//	  "rules": [
+//	    {"operation": "withdraw", "account_type": "savings", "currency": "IDR", "kind": "flat", "amount": "5000"},
+//	    {"operation": "transfer", "currency": "IDR", "kind": "percentage", "percent": "0.1", "min": "2500", "max": "25000"},
+//	    {"operation": "transfer", "account_type": "checking", "currency": "USD", "kind": "tiered",
+//	     "tiers": [{"up_to": "1000", "amount": "0"}, {"up_to": "10000", "amount": "5"}, {"percent": "0.05"}]}
+//	  ]
+//	}
+//
+// A rule without account_type applies to every account type that has no rule of its own.
+type scheduleFile struct {
+	Rules []struct {
+		Operation   string `json:"operation"`
+		AccountType string `json:"account_type"`
+		Currency    string `json:"currency"`
+		Kind        string `json:"kind"`
+		Amount      string `json:"amount"`
+		Percent     string `json:"percent"`
+		Min         string `json:"min"`
+		Max         string `json:"max"`
+		Tiers       []struct {
+			UpTo    string `json:"up_to"`
+			Amount  string `json:"amount"`
+			Percent string `json:"percent"`
+		} `json:"tiers"`
+	} `json:"rules"`
+}
+
+// LoadSchedule reads a fee schedule from the JSON file at path.
+func LoadSchedule(path string) (*Schedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fee schedule: %w", err)
+	}
+	var file scheduleFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse fee schedule %s: %w", path, err)
+	}
+
+	rules := make([]Rule, 0, len(file.Rules))
+	for i, fr := range file.Rules {
+		currency, err := money.ParseCurrency(fr.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("fee rule %d in %s: %w", i+1, path, err)
+		}
+		r := Rule{Operation: fr.Operation, AccountType: fr.AccountType, Currency: currency, Kind: fr.Kind}
+		if r.Amount, err = parseAmount(fr.Amount, currency); err != nil {
+			return nil, fmt.Errorf("fee rule %d in %s: amount: %w", i+1, path, err)
+		}
+		if r.Rate, err = parsePercent(fr.Percent); err != nil {
+			return nil, fmt.Errorf("fee rule %d in %s: percent: %w", i+1, path, err)
+		}
+		if r.Min, err = parseOptionalAmount(fr.Min, currency); err != nil {
+			return nil, fmt.Errorf("fee rule %d in %s: min: %w", i+1, path, err)
+		}
+		if r.Max, err = parseOptionalAmount(fr.Max, currency); err != nil {
+			return nil, fmt.Errorf("fee rule %d in %s: max: %w", i+1, path, err)
+		}
+		for j, ft := range fr.Tiers {
+			var t Tier
+			if t.UpTo, err = parseOptionalAmount(ft.UpTo, currency); err != nil {
+				return nil, fmt.Errorf("fee rule %d tier %d in %s: up_to: %w", i+1, j+1, path, err)
+			}
+			if t.Amount, err = parseAmount(ft.Amount, currency); err != nil {
+				return nil, fmt.Errorf("fee rule %d tier %d in %s: amount: %w", i+1, j+1, path, err)
+			}
+			if t.Rate, err = parsePercent(ft.Percent); err != nil {
+				return nil, fmt.Errorf("fee rule %d tier %d in %s: percent: %w", i+1, j+1, path, err)
+			}
+			r.Tiers = append(r.Tiers, t)
+		}
+		rules = append(rules, r)
+	}
+	return NewSchedule(rules)
+}
+
+// parseAmount parses s in currency; empty means zero.
+func parseAmount(s string, currency money.Currency) (money.Money, error) {
+	if s == "" {
+		return money.Zero(currency), nil
+	}
+	return money.Parse(s, currency)
+}
+
+// parseOptionalAmount parses s in currency; empty means not set.
+func parseOptionalAmount(s string, currency money.Currency) (*money.Money, error) {
+	if s == "" {
+		return nil, nil
+	}
+	m, err := money.Parse(s, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// parsePercent parses a percentage such as "0.1" into the fraction 0.001; empty means not set.
+func parsePercent(s string) (*big.Rat, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid percentage %q", s)
+	}
+	return v.Quo(v, big.NewRat(100, 1)), nil
+}
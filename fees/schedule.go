@@ -0,0 +1,169 @@
+// go-bank-app/fees/schedule.go
+
+// Package fees computes the fee charged on an operation from a fee schedule keyed by operation,
+// account type and currency.
+package fees
+
+import (
+	"fmt"
+	"math/big"
+
+	"go-bank-app/money"
+)
+
+// Operations that can carry a fee.
+const (
+	OpWithdraw = "withdraw"
+	OpTransfer = "transfer"
+)
+
+// Kinds of fee rule.
+const (
+	KindFlat       = "flat"       // A fixed amount
+	KindPercentage = "percentage" // A fraction of the operation amount
+	KindTiered     = "tiered"     // Flat and/or percentage, chosen by the band the amount falls in
+)
+
+// Rule is the fee for one operation on one account type in one currency. Min and Max, when
+// set, bound the computed fee (typically a percentage with a floor and a cap).
+type Rule struct {
+	Operation   string
+	AccountType string // Empty: every account type without a more specific rule
+	Currency    money.Currency
+	Kind        string
+	Amount      money.Money // flat
+	Rate        *big.Rat    // percentage, as a fraction (0.001 = 0.1%)
+	Tiers       []Tier      // tiered, ordered by UpTo
+	Min, Max    *money.Money
+}
+
+// Tier is one amount band of a tiered rule. The fee of an amount in the band is
+// Amount + amount*Rate; the band ends at UpTo inclusive, or is unbounded if UpTo is nil.
+type Tier struct {
+	UpTo   *money.Money
+	Amount money.Money
+	Rate   *big.Rat // May be nil
+}
+
+// Schedule is a set of fee rules. A nil or empty schedule charges nothing. Schedules are
+// read-only after loading and safe for concurrent use.
+type Schedule struct {
+	rules map[ruleKey]*Rule
+}
+
+type ruleKey struct {
+	operation   string
+	accountType string
+	currency    money.Currency
+}
+
+// NewSchedule validates rules and builds a schedule from them.
+func NewSchedule(rules []Rule) (*Schedule, error) {
+	s := &Schedule{rules: make(map[ruleKey]*Rule, len(rules))}
+	for i := range rules {
+		r := &rules[i]
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("fee rule %d (%s/%s/%s): %w", i+1, r.Operation, r.accountTypeName(), r.Currency, err)
+		}
+		key := ruleKey{r.Operation, r.AccountType, r.Currency}
+		if _, dup := s.rules[key]; dup {
+			return nil, fmt.Errorf("fee rule %d: duplicate rule for %s/%s/%s", i+1, r.Operation, r.accountTypeName(), r.Currency)
+		}
+		s.rules[key] = r
+	}
+	return s, nil
+}
+
+// Fee returns the fee for an operation of amount on an account of the given type, in the
+// amount's currency. Without a matching rule the fee is zero.
+func (s *Schedule) Fee(operation, accountType string, amount money.Money) money.Money {
+	rule := s.rule(operation, accountType, amount.Currency())
+	if rule == nil {
+		return money.Zero(amount.Currency())
+	}
+	return rule.fee(amount)
+}
+
+// rule finds the rule for an account type, falling back to the rule for every account type.
+func (s *Schedule) rule(operation, accountType string, currency money.Currency) *Rule {
+	if s == nil {
+		return nil
+	}
+	if r, ok := s.rules[ruleKey{operation, accountType, currency}]; ok {
+		return r
+	}
+	return s.rules[ruleKey{operation, "", currency}]
+}
+
+func (r *Rule) fee(amount money.Money) money.Money {
+	var fee money.Money
+	switch r.Kind {
+	case KindFlat:
+		fee = r.Amount
+	case KindPercentage:
+		fee = amount.MulRat(r.Rate, money.RoundHalfUp)
+	case KindTiered:
+		tier := r.Tiers[len(r.Tiers)-1]
+		for _, t := range r.Tiers {
+			if t.UpTo == nil || !amount.GreaterThan(*t.UpTo) {
+				tier = t
+				break
+			}
+		}
+		fee = tier.Amount
+		if tier.Rate != nil {
+			fee = fee.Add(amount.MulRat(tier.Rate, money.RoundHalfUp))
+		}
+	}
+	if r.Min != nil && fee.LessThan(*r.Min) {
+		fee = *r.Min
+	}
+	if r.Max != nil && fee.GreaterThan(*r.Max) {
+		fee = *r.Max
+	}
+	return fee
+}
+
+func (r *Rule) validate() error {
+	if r.Operation != OpWithdraw && r.Operation != OpTransfer {
+		return fmt.Errorf("unknown operation %q", r.Operation)
+	}
+	switch r.Kind {
+	case KindFlat:
+		if r.Amount.IsNegative() {
+			return fmt.Errorf("flat fee must not be negative")
+		}
+	case KindPercentage:
+		if r.Rate == nil || r.Rate.Sign() < 0 {
+			return fmt.Errorf("percentage fee needs a non-negative rate")
+		}
+	case KindTiered:
+		if len(r.Tiers) == 0 {
+			return fmt.Errorf("tiered fee needs at least one tier")
+		}
+		for i, t := range r.Tiers {
+			if t.Amount.IsNegative() || (t.Rate != nil && t.Rate.Sign() < 0) {
+				return fmt.Errorf("tier %d: fee must not be negative", i+1)
+			}
+			if t.UpTo == nil && i != len(r.Tiers)-1 {
+				return fmt.Errorf("tier %d: only the last tier may be unbounded", i+1)
+			}
+			if i > 0 && t.UpTo != nil && !t.UpTo.GreaterThan(*r.Tiers[i-1].UpTo) {
+				return fmt.Errorf("tier %d: tiers must be in increasing up_to order", i+1)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown fee kind %q", r.Kind)
+	}
+	if r.Min != nil && r.Max != nil && r.Min.GreaterThan(*r.Max) {
+		return fmt.Errorf("min fee is above max fee")
+	}
+	return nil
+}
+
+func (r *Rule) accountTypeName() string {
+	if r.AccountType == "" {
+		return "*"
+	}
+	return r.AccountType
+}
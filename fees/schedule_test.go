@@ -0,0 +1,135 @@
+package fees
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-bank-app/money"
+)
+
+func idr(s string) money.Money { return money.MustParse(s, money.IDR) }
+func usd(s string) money.Money { return money.MustParse(s, money.USD) }
+
+func ptr(m money.Money) *money.Money { return &m }
+
+// TestExampleSchedule runs the shipped example through the boundaries of each of its rules.
+func TestExampleSchedule(t *testing.T) {
+	s, err := LoadSchedule(filepath.Join("..", "fee_schedule.example.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		operation   string
+		accountType string
+		amount      money.Money
+		want        money.Money
+	}{
+		{"flat savings withdrawal", OpWithdraw, "savings", idr("100000"), idr("5000")},
+		{"flat checking withdrawal", OpWithdraw, "checking", idr("100000"), idr("2500")},
+		{"no rule for this account type", OpWithdraw, "deposit", idr("100000"), idr("0")},
+		{"no rule for this currency", OpWithdraw, "savings", usd("100"), usd("0")},
+
+		{"percentage below the floor", OpTransfer, "checking", idr("1000000"), idr("2500")},
+		{"percentage between floor and cap", OpTransfer, "checking", idr("10000000"), idr("10000")},
+		{"percentage rounds half up", OpTransfer, "checking", idr("12345678.55"), idr("12345.68")},
+		{"percentage above the cap", OpTransfer, "checking", idr("100000000"), idr("25000")},
+
+		{"first tier", OpTransfer, "savings", idr("500000"), idr("0")},
+		{"first tier upper bound is inclusive", OpTransfer, "savings", idr("1000000"), idr("0")},
+		{"just into the second tier", OpTransfer, "savings", idr("1000000.01"), idr("6500")},
+		{"second tier upper bound", OpTransfer, "savings", idr("100000000"), idr("6500")},
+		{"unbounded tier adds its percentage", OpTransfer, "savings", idr("200000000"), idr("26500")},
+
+		{"currency-wide rule, floor", OpTransfer, "savings", usd("100"), usd("1")},
+		{"currency-wide rule", OpTransfer, "checking", usd("1234.57"), usd("3.09")},
+		{"currency-wide rule, cap", OpTransfer, "checking", usd("10000"), usd("20")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Fee(tt.operation, tt.accountType, tt.amount); got.Cmp(tt.want) != 0 {
+				t.Errorf("Fee(%s, %s, %s) = %s, want %s", tt.operation, tt.accountType, tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecificRuleWinsOverDefault(t *testing.T) {
+	s, err := NewSchedule([]Rule{
+		{Operation: OpWithdraw, Currency: money.IDR, Kind: KindFlat, Amount: idr("7500")},
+		{Operation: OpWithdraw, AccountType: "savings", Currency: money.IDR, Kind: KindFlat, Amount: idr("5000")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Fee(OpWithdraw, "savings", idr("1")); got.Cmp(idr("5000")) != 0 {
+		t.Errorf("savings fee = %s, want 5000.00", got)
+	}
+	if got := s.Fee(OpWithdraw, "checking", idr("1")); got.Cmp(idr("7500")) != 0 {
+		t.Errorf("checking fee = %s, want the default 7500.00", got)
+	}
+}
+
+func TestNilScheduleChargesNothing(t *testing.T) {
+	var s *Schedule
+	if got := s.Fee(OpTransfer, "checking", usd("50")); !got.IsZero() || got.Currency() != money.USD {
+		t.Errorf("Fee on a nil schedule = %s %s, want 0 USD", got, got.Currency())
+	}
+}
+
+func TestNewScheduleRejects(t *testing.T) {
+	rate := big.NewRat(1, 1000) // 0.1%
+	tests := map[string]Rule{
+		"unknown operation":         {Operation: "deposit", Currency: money.IDR, Kind: KindFlat},
+		"unknown kind":              {Operation: OpWithdraw, Currency: money.IDR, Kind: "sliding"},
+		"negative flat fee":         {Operation: OpWithdraw, Currency: money.IDR, Kind: KindFlat, Amount: idr("-1")},
+		"percentage without a rate": {Operation: OpTransfer, Currency: money.IDR, Kind: KindPercentage},
+		"negative rate":             {Operation: OpTransfer, Currency: money.IDR, Kind: KindPercentage, Rate: big.NewRat(-1, 100)},
+		"min above max": {Operation: OpTransfer, Currency: money.IDR, Kind: KindPercentage, Rate: rate,
+			Min: ptr(idr("5000")), Max: ptr(idr("2500"))},
+		"no tiers": {Operation: OpTransfer, Currency: money.IDR, Kind: KindTiered},
+		"unbounded tier before the last": {Operation: OpTransfer, Currency: money.IDR, Kind: KindTiered,
+			Tiers: []Tier{{Amount: idr("0")}, {UpTo: ptr(idr("1000")), Amount: idr("5")}}},
+		"tiers out of order": {Operation: OpTransfer, Currency: money.IDR, Kind: KindTiered,
+			Tiers: []Tier{{UpTo: ptr(idr("1000")), Amount: idr("0")}, {UpTo: ptr(idr("1000")), Amount: idr("5")}}},
+		"negative tier fee": {Operation: OpTransfer, Currency: money.IDR, Kind: KindTiered,
+			Tiers: []Tier{{Amount: idr("0"), Rate: big.NewRat(-1, 100)}}},
+	}
+	for name, rule := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewSchedule([]Rule{rule}); err == nil {
+				t.Errorf("NewSchedule accepted %+v", rule)
+			}
+		})
+	}
+
+	t.Run("duplicate rule", func(t *testing.T) {
+		rule := Rule{Operation: OpWithdraw, Currency: money.IDR, Kind: KindFlat, Amount: idr("5000")}
+		if _, err := NewSchedule([]Rule{rule, rule}); err == nil {
+			t.Error("NewSchedule accepted two rules for the same operation, account type and currency")
+		}
+	})
+}
+
+func TestLoadScheduleRejects(t *testing.T) {
+	for name, content := range map[string]string{
+		"malformed json":       `{"rules": [`,
+		"unsupported currency": `{"rules": [{"operation": "withdraw", "currency": "XYZ", "kind": "flat", "amount": "1"}]}`,
+		"too precise amount":   `{"rules": [{"operation": "withdraw", "currency": "IDR", "kind": "flat", "amount": "1.005"}]}`,
+		"bad percentage":       `{"rules": [{"operation": "transfer", "currency": "IDR", "kind": "percentage", "percent": "ten"}]}`,
+		"bad tier bound": `{"rules": [{"operation": "transfer", "currency": "IDR", "kind": "tiered",
+			"tiers": [{"up_to": "lots", "amount": "0"}]}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fees.json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadSchedule(path); err == nil {
+				t.Errorf("LoadSchedule accepted %s", content)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// go-bank-app/fx/file_provider.go
+package fx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-bank-app/money"
+)
+
+// rateFile is the on-disk format read by NewFileRateProvider:
+//
+//	{
+//	  "source": "treasury-2026-10-16",
+//	  "as_of": "2026-10-16T08:00:00Z",
+//	  "rates": {"USD/IDR": "15500", "EUR/IDR": "16850.5"}
+//	}
+//
+// Only one direction of each pair needs to be listed; the inverse is derived.
+type rateFile struct {
+	Source string            `json:"source"`
+	AsOf   time.Time         `json:"as_of"`
+	Rates  map[string]string `json:"rates"`
+}
+
+// fileRateProvider serves rates loaded once from a JSON file, so conversions work offline.
+type fileRateProvider struct {
+	rates map[[2]money.Currency]*Rate
+}
+
+// NewFileRateProvider loads rates from the JSON file at path.
+func NewFileRateProvider(path string) (RateProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rate file: %w", err)
+	}
+	var file rateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rate file %s: %w", path, err)
+	}
+	if file.Source == "" {
+		file.Source = path
+	}
+
+	p := &fileRateProvider{rates: make(map[[2]money.Currency]*Rate)}
+	for pair, value := range file.Rates {
+		codes := strings.SplitN(pair, "/", 2)
+		if len(codes) != 2 {
+			return nil, fmt.Errorf("invalid currency pair %q in %s, want BASE/QUOTE", pair, path)
+		}
+		base, err := money.ParseCurrency(codes[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency pair %q in %s: %w", pair, path, err)
+		}
+		quote, err := money.ParseCurrency(codes[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency pair %q in %s: %w", pair, path, err)
+		}
+		v, err := ParseRate(value)
+		if err != nil {
+			return nil, fmt.Errorf("rate for %s in %s: %w", pair, path, err)
+		}
+		p.rates[[2]money.Currency{base, quote}] = &Rate{Base: base, Quote: quote, Value: v, Source: file.Source, AsOf: file.AsOf}
+	}
+	return p, nil
+}
+
+func (p *fileRateProvider) GetRate(base, quote money.Currency) (*Rate, error) {
+	if r, ok := p.rates[[2]money.Currency{base, quote}]; ok {
+		return r, nil
+	}
+	if r, ok := p.rates[[2]money.Currency{quote, base}]; ok {
+		return r.Invert(), nil
+	}
+	return nil, fmt.Errorf("%s/%s: %w", base, quote, ErrRateNotFound)
+}
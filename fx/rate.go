@@ -0,0 +1,106 @@
+// go-bank-app/fx/rate.go
+
+// Package fx provides exchange rates and converts amounts between currencies.
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go-bank-app/money"
+)
+
+// ErrRateNotFound is returned when a provider has no rate for the requested currency pair.
+var ErrRateNotFound = errors.New("exchange rate not available")
+
+// rateDecimals is the number of fractional digits kept when a rate is stored or displayed.
+const rateDecimals = 10
+
+// Rate is the mid-market price of one unit of Base expressed in Quote, e.g. USD/IDR = 15500.
+type Rate struct {
+	Base   money.Currency
+	Quote  money.Currency
+	Value  *big.Rat
+	Source string    // Where the rate came from (file name, feed name, ...)
+	AsOf   time.Time // When the rate was published
+}
+
+// Invert returns the Quote/Base rate.
+func (r *Rate) Invert() *Rate {
+	return &Rate{
+		Base:   r.Quote,
+		Quote:  r.Base,
+		Value:  new(big.Rat).Inv(r.Value),
+		Source: r.Source,
+		AsOf:   r.AsOf,
+	}
+}
+
+// String formats the rate value as a fixed-point decimal.
+func (r *Rate) String() string {
+	return FormatRate(r.Value)
+}
+
+// FormatRate formats a rate with the precision used for storage.
+func FormatRate(v *big.Rat) string {
+	return v.FloatString(rateDecimals)
+}
+
+// ParseRate parses a positive decimal rate such as "15500" or "0.0000645".
+func ParseRate(s string) (*big.Rat, error) {
+	v, ok := new(big.Rat).SetString(s)
+	if !ok || v.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %q", s)
+	}
+	return v, nil
+}
+
+// RateProvider supplies exchange rates. Implementations must be safe for concurrent use.
+type RateProvider interface {
+	// GetRate returns the current base/quote rate, or an error wrapping ErrRateNotFound.
+	GetRate(base, quote money.Currency) (*Rate, error)
+}
+
+// Conversion is the result of converting an amount into another currency at a customer rate.
+type Conversion struct {
+	Rate        *Rate       // Mid-market rate used
+	AppliedRate *big.Rat    // Rate given to the customer: mid rate less the spread
+	Source      money.Money // Amount debited, in the source currency
+	Gross       money.Money // Source converted at the mid rate, in the destination currency
+	Destination money.Money // Amount credited, in the destination currency
+	Spread      money.Money // Gross - Destination: kept by the bank as fee income
+}
+
+// Convert converts amount into currency to using p. spread is the fraction of the mid rate kept by
+// the bank (e.g. 0.005 for 50 bps). The credited amount is rounded down so rounding never favors
+// the customer at the bank's expense; the remainder is part of the spread.
+func Convert(p RateProvider, amount money.Money, to money.Currency, spread *big.Rat) (*Conversion, error) {
+	rate, err := p.GetRate(amount.Currency(), to)
+	if err != nil {
+		return nil, err
+	}
+
+	grossRat := new(big.Rat).Mul(amount.Rat(), rate.Value)
+	gross, err := money.FromRat(grossRat, to, money.RoundHalfEven)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s %s to %s: %w", amount, amount.Currency(), to, err)
+	}
+
+	applied := new(big.Rat).Sub(big.NewRat(1, 1), spread)
+	applied.Mul(applied, rate.Value)
+	destination, err := money.FromRat(new(big.Rat).Mul(amount.Rat(), applied), to, money.RoundDown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s %s to %s: %w", amount, amount.Currency(), to, err)
+	}
+
+	return &Conversion{
+		Rate:        rate,
+		AppliedRate: applied,
+		Source:      amount,
+		Gross:       gross,
+		Destination: destination,
+		Spread:      gross.Sub(destination),
+	}, nil
+}
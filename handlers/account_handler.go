@@ -2,12 +2,9 @@
 package handlers
 
 import (
-	"database/sql"
-	"log"
 	"net/http"
-	"strconv"
-	"strings"
 
+	"go-bank-app/middleware"
 	"go-bank-app/models"
 	"go-bank-app/services"
 
@@ -29,30 +26,24 @@ func NewAccountHandler(accountService services.AccountService) *AccountHandler {
 func (h *AccountHandler) CreateAccount(c *gin.Context) {
 	loggedInUserID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		unauthorized(c)
 		return
 	}
 
 	var req models.CreateAccountRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// Authorization: Only allow users to create accounts for themselves
 	if req.UserID != loggedInUserID.(int) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to create account for another user"})
+		forbidden(c, "Unauthorized to create account for another user")
 		return
 	}
 
 	newAccount, err := h.AccountService.CreateAccount(&req)
 	if err != nil {
-		log.Printf("Error creating account via service: %v", err)
-		if strings.Contains(err.Error(), "Duplicate entry") && strings.Contains(err.Error(), "account_number") {
-			c.JSON(http.StatusConflict, gin.H{"error": "Account number already exists."})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create account."})
-		}
+		middleware.Fail(c, err)
 		return
 	}
 
@@ -62,33 +53,25 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 // GetAccountByID handles GET /accounts/:id
 // It retrieves account details by account ID, only if the logged-in user owns the account
 func (h *AccountHandler) GetAccountByID(c *gin.Context) {
-	idParam := c.Param("id")
-	accountID, err := strconv.Atoi(idParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account ID format"})
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
 		return
 	}
 
-	loggedInUserID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+	if _, exists := c.Get("userID"); !exists {
+		unauthorized(c)
 		return
 	}
 
 	account, err := h.AccountService.GetAccountByID(accountID)
 	if err != nil {
-		if err == sql.ErrNoRows || strings.Contains(err.Error(), "akun tidak ditemukan") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
-		} else {
-			log.Printf("Error getting account by ID via service: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve account"})
-		}
+		middleware.Fail(c, err)
 		return
 	}
 
-	// Authorization: Only allow users to access their own accounts
-	if account.UserID != loggedInUserID.(int) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to access this account"})
+	// Authorization: Only allow users to access their own accounts (admins/operators may view any account)
+	if !canAccessAccount(c, account, models.PermAccountsReadAny) {
+		forbidden(c, "Unauthorized to access this account")
 		return
 	}
 
@@ -98,45 +81,36 @@ func (h *AccountHandler) GetAccountByID(c *gin.Context) {
 // Deposit handles POST /accounts/:id/deposit
 // It deposits an amount to the account, only if the user is the account owner
 func (h *AccountHandler) Deposit(c *gin.Context) {
-	idParam := c.Param("id")
-	accountID, err := strconv.Atoi(idParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account ID format"})
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
 		return
 	}
 
 	loggedInUserID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		unauthorized(c)
 		return
 	}
 
 	// Check account ownership before processing deposit
 	account, err := h.AccountService.GetAccountByID(accountID)
 	if err != nil {
-		if err == sql.ErrNoRows || strings.Contains(err.Error(), "akun tidak ditemukan") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
-		} else {
-			log.Printf("Error checking account ownership for deposit: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify account ownership"})
-		}
+		middleware.Fail(c, err)
 		return
 	}
 	if account.UserID != loggedInUserID.(int) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to deposit to this account"})
+		forbidden(c, "Unauthorized to deposit to this account")
 		return
 	}
 
 	var req models.DepositWithdrawRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	updatedAccount, err := h.AccountService.Deposit(accountID, req.Amount)
 	if err != nil {
-		log.Printf("Error during deposit via service: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process deposit"})
+		middleware.Fail(c, err)
 		return
 	}
 
@@ -146,51 +120,195 @@ func (h *AccountHandler) Deposit(c *gin.Context) {
 // Withdraw handles POST /accounts/:id/withdraw
 // It withdraws an amount from the account, only if the user is the account owner and has sufficient funds
 func (h *AccountHandler) Withdraw(c *gin.Context) {
-	idParam := c.Param("id")
-	accountID, err := strconv.Atoi(idParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account ID format"})
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
 		return
 	}
 
 	loggedInUserID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		unauthorized(c)
 		return
 	}
 
 	// Check account ownership before processing withdrawal
 	account, err := h.AccountService.GetAccountByID(accountID)
 	if err != nil {
-		if err == sql.ErrNoRows || strings.Contains(err.Error(), "akun tidak ditemukan") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
-		} else {
-			log.Printf("Error checking account ownership for withdrawal: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify account ownership"})
-		}
+		middleware.Fail(c, err)
 		return
 	}
 	if account.UserID != loggedInUserID.(int) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to withdraw from this account"})
+		forbidden(c, "Unauthorized to withdraw from this account")
 		return
 	}
 
 	var req models.DepositWithdrawRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	updatedAccount, err := h.AccountService.Withdraw(accountID, req.Amount)
 	if err != nil {
-		log.Printf("Error during withdrawal via service: %v", err)
-		if strings.Contains(err.Error(), "saldo tidak cukup") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process withdrawal"})
-		}
+		middleware.Fail(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Withdrawal successful", "account": updatedAccount})
 }
+
+// FreezeAccount handles POST /accounts/:id/freeze (operator/admin)
+// Akun yang dibekukan masih menerima dana masuk, tetapi menolak semua dana keluar.
+func (h *AccountHandler) FreezeAccount(c *gin.Context) {
+	h.changeAccountStatus(c, models.AccountFrozen)
+}
+
+// UnfreezeAccount handles POST /accounts/:id/unfreeze (operator/admin)
+// Mengaktifkan kembali akun yang dibekukan atau dormant.
+func (h *AccountHandler) UnfreezeAccount(c *gin.Context) {
+	h.changeAccountStatus(c, models.AccountActive)
+}
+
+// MarkAccountDormant handles POST /accounts/:id/dormant (operator/admin)
+func (h *AccountHandler) MarkAccountDormant(c *gin.Context) {
+	h.changeAccountStatus(c, models.AccountDormant)
+}
+
+// changeAccountStatus adalah dasar freeze, unfreeze dan dormant. Izin diperiksa di routes.
+func (h *AccountHandler) changeAccountStatus(c *gin.Context, to models.AccountStatus) {
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
+		return
+	}
+
+	var req models.AccountStatusRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	middleware.MarkPrivilegedAccess(c, models.PermAccountsManage)
+	account, err := h.AccountService.ChangeAccountStatus(accountID, to, req.Reason, c.GetInt("userID"))
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// CloseAccount handles POST /accounts/:id/close
+// Pemilik akun boleh menutup akunnya sendiri kecuali akun sedang dibekukan; operator/admin boleh
+// menutup akun mana pun. Saldo yang tersisa wajib dipindahkan ke sweep_to_account_number.
+func (h *AccountHandler) CloseAccount(c *gin.Context) {
+	account, ok := h.loadOwnedAccount(c, models.PermAccountsManage)
+	if !ok {
+		return
+	}
+	// Pemilik tidak boleh menutup akun yang dibekukan bank untuk menghindari pembekuan
+	if account.Status == models.AccountFrozen && !middleware.HasPermission(c, models.PermAccountsManage) {
+		forbidden(c, "Frozen accounts can only be closed by the bank")
+		return
+	}
+
+	var req models.CloseAccountRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Saldo hanya boleh dipindahkan ke akun milik pemilik yang sama, kecuali ditutup oleh operator/admin
+	if req.SweepToAccountNumber != "" && !middleware.HasPermission(c, models.PermAccountsManage) {
+		target, err := h.AccountService.GetAccountByNumber(req.SweepToAccountNumber)
+		if err != nil {
+			middleware.Fail(c, err)
+			return
+		}
+		if target.UserID != account.UserID {
+			forbidden(c, "Balance can only be swept to another account you own")
+			return
+		}
+	}
+
+	closedAccount, err := h.AccountService.CloseAccount(account.ID, &req, c.GetInt("userID"))
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account closed", "account": closedAccount})
+}
+
+// GetStatusHistory handles GET /accounts/:id/status-history
+func (h *AccountHandler) GetStatusHistory(c *gin.Context) {
+	account, ok := h.loadOwnedAccount(c, models.PermAccountsReadAny)
+	if !ok {
+		return
+	}
+
+	history, err := h.AccountService.GetStatusHistory(account.ID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetApprovers handles GET /accounts/:id/approvers
+func (h *AccountHandler) GetApprovers(c *gin.Context) {
+	account, ok := h.loadOwnedAccount(c, models.PermAccountsReadAny)
+	if !ok {
+		return
+	}
+
+	approvers, err := h.AccountService.GetApprovers(account.ID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, approvers)
+}
+
+// AddApprover handles POST /accounts/:id/approvers
+// Pemilik akun (atau operator/admin) menunjuk user lain sebagai checker untuk transfer besar.
+func (h *AccountHandler) AddApprover(c *gin.Context) {
+	account, ok := h.loadOwnedAccount(c, models.PermAccountsManage)
+	if !ok {
+		return
+	}
+
+	var req models.AddApproverRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	approvers, err := h.AccountService.AddApprover(account.ID, req.UserID, c.GetInt("userID"))
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, approvers)
+}
+
+// RemoveApprover handles DELETE /accounts/:id/approvers/:userId
+func (h *AccountHandler) RemoveApprover(c *gin.Context) {
+	account, ok := h.loadOwnedAccount(c, models.PermAccountsManage)
+	if !ok {
+		return
+	}
+	userID, ok := pathID(c, "userId", "user")
+	if !ok {
+		return
+	}
+
+	approvers, err := h.AccountService.RemoveApprover(account.ID, userID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, approvers)
+}
+
+// loadOwnedAccount membaca akun :id dan memastikan user yang login adalah pemiliknya atau punya
+// permission. Jika gagal, error sudah dicatat untuk ErrorMiddleware.
+func (h *AccountHandler) loadOwnedAccount(c *gin.Context, permission string) (*models.Account, bool) {
+	return loadAccessibleAccount(c, h.AccountService, permission, "Unauthorized to access this account")
+}
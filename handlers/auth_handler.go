@@ -2,8 +2,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"go-bank-app/middleware"
 	"go-bank-app/models"
 	"go-bank-app/services" // Import service
 
@@ -12,29 +14,25 @@ import (
 
 // AuthHandler struct untuk dependensi service
 type AuthHandler struct {
-	UserService services.UserService
+	UserService  services.UserService
+	TokenService services.TokenService
 }
 
 // NewAuthHandler membuat instance baru dari AuthHandler
-func NewAuthHandler(userService services.UserService) *AuthHandler {
-	return &AuthHandler{UserService: userService}
+func NewAuthHandler(userService services.UserService, tokenService services.TokenService) *AuthHandler {
+	return &AuthHandler{UserService: userService, TokenService: tokenService}
 }
 
 // RegisterUser handles POST /auth/register
 func (h *AuthHandler) RegisterUser(c *gin.Context) { // Perhatikan receiver 'h *AuthHandler'
 	var req models.CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	newUser, err := h.UserService.RegisterUser(&req)
 	if err != nil {
-		if err.Error() == "email sudah terdaftar" { // Contoh penanganan error spesifik dari service
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
+		middleware.Fail(c, err)
 		return
 	}
 
@@ -48,20 +46,63 @@ func (h *AuthHandler) LoginUser(c *gin.Context) { // Perhatikan receiver 'h *Aut
 		Password string `json:"password" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	tokens, user, err := h.UserService.LoginUser(req.Email, req.Password)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"token_type":    tokens.TokenType,
+		"expires_in":    tokens.ExpiresIn,
+		"user_id":       user.ID,
+	})
+}
+
+// RefreshToken handles POST /auth/refresh
+// Menukar refresh token dengan pasangan token baru; refresh token lama langsung tidak berlaku
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	token, user, err := h.UserService.LoginUser(req.Email, req.Password)
+	tokens, err := h.TokenService.Refresh(req.RefreshToken)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout handles POST /auth/logout
+// Mencabut access token yang sedang dipakai dan (jika dikirim) seluruh keluarga refresh token-nya
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req models.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if !bindJSON(c, &req) {
+			return
+		}
+	}
+
+	err := h.TokenService.Logout(c.GetInt("userID"), c.GetString("tokenID"), c.GetTime("tokenExpiresAt"), req.RefreshToken)
 	if err != nil {
-		if err.Error() == "kredensial tidak valid" { // Contoh penanganan error spesifik dari service
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		// Refresh token yang salah saat logout adalah kesalahan input, bukan sesi yang tidak sah
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			badRequest(c, "invalid_refresh_token", "Invalid or expired refresh token", "")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process login"})
+		middleware.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Login successful", "token": token, "user_id": user.ID})
+	c.JSON(http.StatusOK, gin.H{"message": "Logout successful"})
 }
@@ -0,0 +1,48 @@
+// go-bank-app/handlers/authorization.go
+package handlers
+
+import (
+	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// canAccessAccount mengizinkan pemilik akun, atau user lain yang memiliki izin tertentu
+// (admin/operator). Akses lewat izin ditandai agar dicatat oleh AuditMiddleware.
+func canAccessAccount(c *gin.Context, account *models.Account, permission string) bool {
+	return isSelfOrPermitted(c, account.UserID, permission)
+}
+
+// isSelfOrPermitted adalah dasar canAccessAccount untuk resource yang dimiliki ownerUserID.
+func isSelfOrPermitted(c *gin.Context, ownerUserID int, permission string) bool {
+	if ownerUserID == c.GetInt("userID") {
+		return true
+	}
+	if middleware.HasPermission(c, permission) {
+		middleware.MarkPrivilegedAccess(c, permission)
+		return true
+	}
+	return false
+}
+
+// loadAccessibleAccount membaca akun :id dan memastikan canAccessAccount; jika tidak, deniedMessage
+// dijawab 403. Jika gagal, error sudah dicatat dan handler cukup return.
+func loadAccessibleAccount(c *gin.Context, accountService services.AccountService, permission, deniedMessage string) (*models.Account, bool) {
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
+		return nil, false
+	}
+
+	account, err := accountService.GetAccountByID(accountID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return nil, false
+	}
+	if !canAccessAccount(c, account, permission) {
+		forbidden(c, deniedMessage)
+		return nil, false
+	}
+	return account, true
+}
@@ -0,0 +1,50 @@
+// go-bank-app/handlers/errors.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-bank-app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler tidak menulis respons error sendiri: error dicatat lewat middleware.Fail dan
+// ErrorMiddleware yang memetakannya ke status HTTP dan envelope {"error": {...}}.
+
+// bindJSON membaca body JSON ke obj. Jika gagal, error validasi sudah dicatat dan handler
+// cukup return.
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		middleware.Fail(c, middleware.InvalidRequest(err))
+		return false
+	}
+	return true
+}
+
+// pathID membaca parameter path numerik, mis. pathID(c, "id", "account").
+func pathID(c *gin.Context, param, what string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		middleware.Fail(c, &middleware.APIError{Status: http.StatusBadRequest, Code: "invalid_id", Message: "Invalid " + what + " ID format", Detail: param + " must be an integer"})
+		return 0, false
+	}
+	return id, true
+}
+
+// unauthorized menolak request yang konteksnya tidak membawa user yang login.
+func unauthorized(c *gin.Context) {
+	middleware.Fail(c, middleware.NewAPIError(http.StatusUnauthorized, "unauthorized", "User ID not found in context"))
+}
+
+// forbidden menolak request yang tidak berhak atas resource yang diminta.
+func forbidden(c *gin.Context, message string) {
+	middleware.Fail(c, middleware.NewAPIError(http.StatusForbidden, "forbidden", message))
+}
+
+// badRequest menolak input yang tidak lolos pemeriksaan di handler (mis. query parameter). detail
+// boleh kosong; jika diisi, dikirim apa adanya di samping pesan yang diterjemahkan.
+func badRequest(c *gin.Context, code, message, detail string) {
+	middleware.Fail(c, &middleware.APIError{Status: http.StatusBadRequest, Code: code, Message: message, Detail: detail})
+}
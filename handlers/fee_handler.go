@@ -0,0 +1,46 @@
+// go-bank-app/handlers/fee_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeeHandler melayani perkiraan biaya penarikan dan transfer
+type FeeHandler struct {
+	FeeService     services.FeeService
+	AccountService services.AccountService // Untuk otorisasi
+}
+
+// NewFeeHandler membuat instance baru dari FeeHandler
+func NewFeeHandler(feeService services.FeeService, accountService services.AccountService) *FeeHandler {
+	return &FeeHandler{FeeService: feeService, AccountService: accountService}
+}
+
+// QuoteFee handles GET /accounts/:id/fee-quote?operation=withdraw|transfer&amount=150000
+// Nominal dibaca dalam mata uang akun; respons berisi biaya dan total yang akan didebit.
+func (h *FeeHandler) QuoteFee(c *gin.Context) {
+	account, ok := loadAccessibleAccount(c, h.AccountService, models.PermAccountsReadAny, "Unauthorized to quote fees for this account")
+	if !ok {
+		return
+	}
+
+	amount, err := money.Parse(c.Query("amount"), account.Currency)
+	if err != nil || !amount.IsPositive() {
+		badRequest(c, "invalid_amount", "Invalid amount", "amount must be a positive amount in the account currency")
+		return
+	}
+
+	quote, err := h.FeeService.QuoteFee(account.ID, c.Query("operation"), amount)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
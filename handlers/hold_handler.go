@@ -0,0 +1,143 @@
+// go-bank-app/handlers/hold_handler.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HoldHandler melayani hold dana (otorisasi kartu, blokir legal) pada akun
+type HoldHandler struct {
+	HoldService    services.HoldService
+	AccountService services.AccountService // Untuk otorisasi
+}
+
+// NewHoldHandler membuat instance baru dari HoldHandler
+func NewHoldHandler(holdService services.HoldService, accountService services.AccountService) *HoldHandler {
+	return &HoldHandler{HoldService: holdService, AccountService: accountService}
+}
+
+// CreateHold handles POST /accounts/:id/holds (khusus operator/admin)
+func (h *HoldHandler) CreateHold(c *gin.Context) {
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
+		return
+	}
+
+	var req models.CreateHoldRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	hold, err := h.HoldService.CreateHold(accountID, &req, c.GetInt("userID"))
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, hold)
+}
+
+// GetHolds handles GET /accounts/:id/holds?status=active
+func (h *HoldHandler) GetHolds(c *gin.Context) {
+	account, ok := loadAccessibleAccount(c, h.AccountService, models.PermAccountsReadAny, "Unauthorized to view holds for this account")
+	if !ok {
+		return
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.HoldActive, models.HoldCaptured, models.HoldReleased, models.HoldExpired:
+	default:
+		badRequest(c, "invalid_status", "Invalid status filter", "want active, captured, released or expired")
+		return
+	}
+
+	holds, err := h.HoldService.GetHoldsByAccountID(account.ID, status)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, holds)
+}
+
+// GetHold handles GET /accounts/:id/holds/:holdId
+func (h *HoldHandler) GetHold(c *gin.Context) {
+	account, ok := loadAccessibleAccount(c, h.AccountService, models.PermAccountsReadAny, "Unauthorized to view holds for this account")
+	if !ok {
+		return
+	}
+	hold, ok := h.loadHold(c, account.ID)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, hold)
+}
+
+// CaptureHold handles POST /accounts/:id/holds/:holdId/capture (khusus operator/admin)
+// Tanpa amount, seluruh hold dibukukan; capture sebagian melepas sisanya.
+func (h *HoldHandler) CaptureHold(c *gin.Context) {
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
+		return
+	}
+	hold, ok := h.loadHold(c, accountID)
+	if !ok {
+		return
+	}
+
+	var req models.CaptureHoldRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	captured, err := h.HoldService.CaptureHold(hold.ID, &req)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, captured)
+}
+
+// ReleaseHold handles POST /accounts/:id/holds/:holdId/release (khusus operator/admin)
+func (h *HoldHandler) ReleaseHold(c *gin.Context) {
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
+		return
+	}
+	hold, ok := h.loadHold(c, accountID)
+	if !ok {
+		return
+	}
+
+	released, err := h.HoldService.ReleaseHold(hold.ID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, released)
+}
+
+// loadHold membaca hold :holdId milik akun accountID. Hold dari akun lain dianggap tidak ada.
+func (h *HoldHandler) loadHold(c *gin.Context, accountID int) (*models.Hold, bool) {
+	holdID, ok := pathID(c, "holdId", "hold")
+	if !ok {
+		return nil, false
+	}
+
+	hold, err := h.HoldService.GetHoldByID(holdID)
+	if err != nil && !errors.Is(err, services.ErrHoldNotFound) {
+		middleware.Fail(c, err)
+		return nil, false
+	}
+	if err != nil || hold.AccountID != accountID {
+		middleware.Fail(c, services.ErrHoldNotFound)
+		return nil, false
+	}
+	return hold, true
+}
@@ -0,0 +1,60 @@
+// go-bank-app/handlers/interest_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InterestHandler melayani rencana bunga akun serta akrual dan kapitalisasinya
+type InterestHandler struct {
+	InterestService services.InterestService
+	AccountService  services.AccountService // Untuk otorisasi
+}
+
+// NewInterestHandler membuat instance baru dari InterestHandler
+func NewInterestHandler(interestService services.InterestService, accountService services.AccountService) *InterestHandler {
+	return &InterestHandler{InterestService: interestService, AccountService: accountService}
+}
+
+// GetAccountInterest handles GET /accounts/:id/interest
+// Menampilkan rencana bunga, akrual bulan berjalan dan riwayat kapitalisasi.
+func (h *InterestHandler) GetAccountInterest(c *gin.Context) {
+	account, ok := loadAccessibleAccount(c, h.AccountService, models.PermAccountsReadAny, "Unauthorized to view interest for this account")
+	if !ok {
+		return
+	}
+
+	view, err := h.InterestService.GetAccountInterest(account.ID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// UpdateInterestPlan handles PUT /accounts/:id/interest-plan (khusus operator/admin)
+// Rencana baru berlaku mulai akrual hari berikutnya.
+func (h *InterestHandler) UpdateInterestPlan(c *gin.Context) {
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateInterestPlanRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	account, err := h.InterestService.UpdateInterestPlan(accountID, &req)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
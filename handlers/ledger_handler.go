@@ -0,0 +1,33 @@
+// go-bank-app/handlers/ledger_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"go-bank-app/middleware"
+	"go-bank-app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LedgerHandler is a struct that contains the LedgerService dependency
+type LedgerHandler struct {
+	LedgerService services.LedgerService
+}
+
+// NewLedgerHandler returns a new instance of LedgerHandler
+func NewLedgerHandler(ledgerService services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{LedgerService: ledgerService}
+}
+
+// GetTrialBalance handles GET /ledger/trial-balance
+// It returns debit/credit totals per ledger account and flags any imbalance
+func (h *LedgerHandler) GetTrialBalance(c *gin.Context) {
+	trialBalance, err := h.LedgerService.GetTrialBalance()
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trialBalance)
+}
@@ -0,0 +1,60 @@
+// go-bank-app/handlers/limit_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitHandler melayani batas debit per akun (per transaksi, harian, bulanan, jumlah transfer)
+type LimitHandler struct {
+	LimitService   services.LimitService
+	AccountService services.AccountService // Untuk otorisasi
+}
+
+// NewLimitHandler membuat instance baru dari LimitHandler
+func NewLimitHandler(limitService services.LimitService, accountService services.AccountService) *LimitHandler {
+	return &LimitHandler{LimitService: limitService, AccountService: accountService}
+}
+
+// GetAccountLimits handles GET /accounts/:id/limits
+// Menampilkan batas efektif, override akun dan pemakaian hari/bulan ini.
+func (h *LimitHandler) GetAccountLimits(c *gin.Context) {
+	account, ok := loadAccessibleAccount(c, h.AccountService, models.PermAccountsReadAny, "Unauthorized to view limits for this account")
+	if !ok {
+		return
+	}
+
+	view, err := h.LimitService.GetAccountLimits(account.ID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// UpdateAccountLimits handles PUT /accounts/:id/limits (khusus operator/admin)
+// Body menggantikan seluruh override akun; field yang tidak diisi kembali ke default jenis akun.
+func (h *LimitHandler) UpdateAccountLimits(c *gin.Context) {
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAccountLimitsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	view, err := h.LimitService.UpdateAccountLimits(accountID, &req)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
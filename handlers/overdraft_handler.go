@@ -0,0 +1,43 @@
+// go-bank-app/handlers/overdraft_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OverdraftHandler melayani fasilitas cerukan (overdraft) akun checking
+type OverdraftHandler struct {
+	OverdraftService services.OverdraftService
+}
+
+// NewOverdraftHandler membuat instance baru dari OverdraftHandler
+func NewOverdraftHandler(overdraftService services.OverdraftService) *OverdraftHandler {
+	return &OverdraftHandler{OverdraftService: overdraftService}
+}
+
+// UpdateOverdraft handles PUT /accounts/:id/overdraft (khusus operator/admin)
+// Limit nol mencabut fasilitas; pemakaian cerukan terlihat di resource akun (overdraft_used).
+func (h *OverdraftHandler) UpdateOverdraft(c *gin.Context) {
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateOverdraftRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	account, err := h.OverdraftService.UpdateOverdraft(accountID, &req)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
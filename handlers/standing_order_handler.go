@@ -0,0 +1,175 @@
+// go-bank-app/handlers/standing_order_handler.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StandingOrderHandler melayani transfer terjadwal dan berulang (standing order)
+type StandingOrderHandler struct {
+	StandingOrderService services.StandingOrderService
+	AccountService       services.AccountService // Untuk otorisasi
+}
+
+// NewStandingOrderHandler membuat instance baru dari StandingOrderHandler
+func NewStandingOrderHandler(standingOrderService services.StandingOrderService, accountService services.AccountService) *StandingOrderHandler {
+	return &StandingOrderHandler{StandingOrderService: standingOrderService, AccountService: accountService}
+}
+
+// CreateStandingOrder handles POST /accounts/:id/standing-orders
+// Hanya pemilik akun yang boleh menjadwalkan transfer dari akunnya.
+func (h *StandingOrderHandler) CreateStandingOrder(c *gin.Context) {
+	account, ok := h.loadAccount(c, "")
+	if !ok {
+		return
+	}
+
+	var req models.CreateStandingOrderRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	order, err := h.StandingOrderService.CreateStandingOrder(account, &req, c.GetInt("userID"))
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, order)
+}
+
+// GetStandingOrders handles GET /accounts/:id/standing-orders
+func (h *StandingOrderHandler) GetStandingOrders(c *gin.Context) {
+	account, ok := h.loadAccount(c, models.PermTransactionsReadAny)
+	if !ok {
+		return
+	}
+
+	orders, err := h.StandingOrderService.GetStandingOrdersByAccountID(account.ID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	if orders == nil {
+		orders = []models.StandingOrder{}
+	}
+	c.JSON(http.StatusOK, orders)
+}
+
+// GetStandingOrder handles GET /accounts/:id/standing-orders/:orderId
+func (h *StandingOrderHandler) GetStandingOrder(c *gin.Context) {
+	order, ok := h.loadStandingOrder(c, models.PermTransactionsReadAny)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// UpdateStandingOrder handles PUT /accounts/:id/standing-orders/:orderId
+// Mengubah nominal, keterangan, batas akhir, atau menjeda/melanjutkan (status paused/active).
+func (h *StandingOrderHandler) UpdateStandingOrder(c *gin.Context) {
+	order, ok := h.loadStandingOrder(c, "")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateStandingOrderRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updated, err := h.StandingOrderService.UpdateStandingOrder(order.ID, &req)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// CancelStandingOrder handles DELETE /accounts/:id/standing-orders/:orderId
+// Order tidak dihapus, hanya diberi status cancelled agar riwayat eksekusinya tetap ada.
+func (h *StandingOrderHandler) CancelStandingOrder(c *gin.Context) {
+	order, ok := h.loadStandingOrder(c, "")
+	if !ok {
+		return
+	}
+
+	cancelled, err := h.StandingOrderService.CancelStandingOrder(order.ID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cancelled)
+}
+
+// GetExecutions handles GET /accounts/:id/standing-orders/:orderId/executions
+func (h *StandingOrderHandler) GetExecutions(c *gin.Context) {
+	order, ok := h.loadStandingOrder(c, models.PermTransactionsReadAny)
+	if !ok {
+		return
+	}
+
+	executions, err := h.StandingOrderService.GetExecutions(order.ID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	if executions == nil {
+		executions = []models.StandingOrderExecution{}
+	}
+	c.JSON(http.StatusOK, executions)
+}
+
+// loadAccount membaca akun :id dan memeriksa akses. Permission kosong berarti hanya pemilik
+// akun yang boleh. Jika gagal, error sudah dicatat untuk ErrorMiddleware.
+func (h *StandingOrderHandler) loadAccount(c *gin.Context, permission string) (*models.Account, bool) {
+	accountID, ok := pathID(c, "id", "account")
+	if !ok {
+		return nil, false
+	}
+
+	account, err := h.AccountService.GetAccountByID(accountID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return nil, false
+	}
+
+	allowed := account.UserID == c.GetInt("userID")
+	if !allowed && permission != "" {
+		allowed = canAccessAccount(c, account, permission)
+	}
+	if !allowed {
+		forbidden(c, "Unauthorized to access standing orders for this account")
+		return nil, false
+	}
+	return account, true
+}
+
+// loadStandingOrder membaca order :orderId milik akun :id. Order dari akun lain dianggap tidak ada.
+func (h *StandingOrderHandler) loadStandingOrder(c *gin.Context, permission string) (*models.StandingOrder, bool) {
+	account, ok := h.loadAccount(c, permission)
+	if !ok {
+		return nil, false
+	}
+	orderID, ok := pathID(c, "orderId", "standing order")
+	if !ok {
+		return nil, false
+	}
+
+	order, err := h.StandingOrderService.GetStandingOrderByID(orderID)
+	if err != nil && !errors.Is(err, services.ErrStandingOrderNotFound) {
+		middleware.Fail(c, err)
+		return nil, false
+	}
+	if err != nil || order.AccountID != account.ID {
+		middleware.Fail(c, services.ErrStandingOrderNotFound)
+		return nil, false
+	}
+	return order, true
+}
@@ -0,0 +1,80 @@
+// go-bank-app/handlers/statement_handler.go
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/services"
+	"go-bank-app/statement"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatementHandler melayani unduhan rekening koran
+type StatementHandler struct {
+	StatementService services.StatementService
+	AccountService   services.AccountService // Untuk otorisasi
+}
+
+// NewStatementHandler membuat instance baru dari StatementHandler
+func NewStatementHandler(statementService services.StatementService, accountService services.AccountService) *StatementHandler {
+	return &StatementHandler{StatementService: statementService, AccountService: accountService}
+}
+
+// GetStatement handles GET /accounts/:id/statement?from=&to=&format=csv|ofx|pdf
+// from/to memakai format yang sama dengan riwayat transaksi (to inklusif untuk tanggal saja).
+// Default: bulan berjalan sampai hari ini, format csv.
+func (h *StatementHandler) GetStatement(c *gin.Context) {
+	// Otorisasi: sama dengan GetAccountTransactions
+	account, ok := loadAccessibleAccount(c, h.AccountService, models.PermTransactionsReadAny, "Unauthorized to view statements for this account")
+	if !ok {
+		return
+	}
+
+	format, err := statement.ParseFormat(c.DefaultQuery("format", string(statement.CSV)))
+	if err != nil {
+		badRequest(c, "invalid_format", "Unsupported statement format", err.Error())
+		return
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	if v := c.Query("from"); v != "" {
+		if from, _, err = parseDateParam(v); err != nil {
+			badRequest(c, "invalid_query", "Invalid query parameter", "invalid from: "+err.Error())
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		var dateOnly bool
+		if to, dateOnly, err = parseDateParam(v); err != nil {
+			badRequest(c, "invalid_query", "Invalid query parameter", "invalid to: "+err.Error())
+			return
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+
+	st, err := h.StatementService.GenerateStatement(account.ID, from, to)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+
+	// Render ke buffer dulu agar error render masih bisa dijawab dengan envelope error 500
+	var buf bytes.Buffer
+	if err := statement.Write(&buf, format, st); err != nil {
+		middleware.Fail(c, fmt.Errorf("failed to render %s statement: %w", format, err))
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+format.Filename(st)+`"`)
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
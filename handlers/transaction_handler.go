@@ -2,12 +2,15 @@
 package handlers
 
 import (
-	"log"
+	"fmt"
 	"net/http"
 	"strconv" // Pastikan ada
 	"strings" // Pastikan ada
+	"time"
 
+	"go-bank-app/middleware"
 	"go-bank-app/models"
+	"go-bank-app/money"
 	"go-bank-app/services" // Import package services kita
 
 	"github.com/gin-gonic/gin"
@@ -28,90 +31,170 @@ func NewTransactionHandler(transactionService services.TransactionService, accou
 func (h *TransactionHandler) Transfer(c *gin.Context) {
 	loggedInUserID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		unauthorized(c)
 		return
 	}
 
 	var req models.TransferRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	if req.FromAccountID == req.ToAccountID {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot transfer to the same account"})
+		middleware.Fail(c, services.ErrSameAccount)
 		return
 	}
 
 	// Otorisasi: Pastikan user yang login adalah pemilik akun pengirim
 	fromAccount, err := h.AccountService.GetAccountByNumber(req.FromAccountID)
 	if err != nil {
-		if strings.Contains(err.Error(), "akun tidak ditemukan") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Source account not found"})
-		} else {
-			log.Printf("Error checking source account ownership for transfer: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify source account"})
-		}
+		middleware.Fail(c, err)
 		return
 	}
 	if fromAccount.UserID != loggedInUserID.(int) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to initiate transfer from this account"})
+		forbidden(c, "Unauthorized to initiate transfer from this account")
 		return
 	}
 
-	err = h.TransactionService.Transfer(&req)
+	transfer, err := h.TransactionService.Transfer(&req, loggedInUserID.(int))
 	if err != nil {
-		log.Printf("Error during transfer via service: %v", err)
-		if strings.Contains(err.Error(), "akun tidak ditemukan") {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else if strings.Contains(err.Error(), "saldo tidak cukup") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds in source account"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process transfer"})
-		}
+		middleware.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Transfer successful"})
+	// Transfer di atas batas persetujuan belum dibukukan: dana ditahan sampai approver memutuskan
+	if transfer.Status == models.TransferPendingApproval {
+		c.JSON(http.StatusAccepted, transfer)
+		return
+	}
+	c.JSON(http.StatusCreated, transfer)
 }
 
 // GetAccountTransactions handles GET /accounts/:id/transactions
 func (h *TransactionHandler) GetAccountTransactions(c *gin.Context) {
-	idParam := c.Param("id")
-	accountID, err := strconv.Atoi(idParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account ID format"})
+	if _, exists := c.Get("userID"); !exists {
+		unauthorized(c)
 		return
 	}
 
-	loggedInUserID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+	// Otorisasi: Pastikan user yang login adalah pemilik akun ini (admin/operator boleh melihat akun mana pun)
+	account, ok := loadAccessibleAccount(c, h.AccountService, models.PermTransactionsReadAny, "Unauthorized to view transactions for this account")
+	if !ok {
 		return
 	}
 
-	// Otorisasi: Pastikan user yang login adalah pemilik akun ini
-	account, err := h.AccountService.GetAccountByID(accountID)
+	filter, err := parseTransactionFilter(c, account)
 	if err != nil {
-		if strings.Contains(err.Error(), "akun tidak ditemukan") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
-		} else {
-			log.Printf("Error checking account ownership for transactions: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify account ownership"})
-		}
+		badRequest(c, "invalid_query", "Invalid query parameter", err.Error())
 		return
 	}
-	if account.UserID != loggedInUserID.(int) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to view transactions for this account"})
+
+	page, err := h.TransactionService.GetAccountTransactions(account.ID, filter)
+	if err != nil {
+		middleware.Fail(c, err)
 		return
 	}
 
-	transactions, err := h.TransactionService.GetAccountTransactions(accountID)
+	c.JSON(http.StatusOK, page)
+}
+
+// parseTransactionFilter membaca query string riwayat transaksi:
+//
+//	?cursor=...&limit=50&from=2024-01-01&to=2024-01-31&type=deposit,withdraw
+//	 &min_amount=10000&max_amount=500000&q=gaji
+//
+// from/to menerima tanggal (YYYY-MM-DD, to bersifat inklusif) atau RFC 3339. Nominal dibaca
+// dalam mata uang akun. limit di atas models.MaxTransactionPageSize tidak ditolak; service
+// memotongnya ke batas tersebut.
+func parseTransactionFilter(c *gin.Context, account *models.Account) (models.TransactionFilter, error) {
+	filter := models.TransactionFilter{Description: strings.TrimSpace(c.Query("q"))}
+
+	if v := c.Query("cursor"); v != "" {
+		cursor, err := models.DecodeCursor(v)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = cursor
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return filter, fmt.Errorf("limit must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+	if v := c.Query("from"); v != "" {
+		from, _, err := parseDateParam(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from: %w", err)
+		}
+		filter.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, dateOnly, err := parseDateParam(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to: %w", err)
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1) // Tanggal saja: sertakan seluruh hari tersebut
+		}
+		filter.To = &to
+	}
+	if v := c.Query("type"); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			t = strings.TrimSpace(t)
+			if !models.IsValidTransactionType(t) {
+				return filter, fmt.Errorf("invalid type %q, want one of %s", t, strings.Join(models.TransactionTypes, ", "))
+			}
+			filter.Types = append(filter.Types, t)
+		}
+	}
+	if v := c.Query("min_amount"); v != "" {
+		amount, err := money.Parse(v, account.Currency)
+		if err != nil {
+			return filter, fmt.Errorf("invalid min_amount: %w", err)
+		}
+		filter.MinAmount = &amount
+	}
+	if v := c.Query("max_amount"); v != "" {
+		amount, err := money.Parse(v, account.Currency)
+		if err != nil {
+			return filter, fmt.Errorf("invalid max_amount: %w", err)
+		}
+		filter.MaxAmount = &amount
+	}
+	return filter, nil
+}
+
+// parseDateParam menerima YYYY-MM-DD (dateOnly = true) atau timestamp RFC 3339.
+func parseDateParam(v string) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, v)
 	if err != nil {
-		log.Printf("Error getting account transactions via service: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve transactions"})
+		return time.Time{}, false, fmt.Errorf("want YYYY-MM-DD or RFC 3339 timestamp")
+	}
+	return t, false, nil
+}
+
+// ReverseTransaction handles POST /transactions/:id/reverse (khusus operator/admin).
+// Seluruh kaki transaksi asli dibalik sekaligus; untuk transfer, transfer_out dan transfer_in.
+func (h *TransactionHandler) ReverseTransaction(c *gin.Context) {
+	transactionID, ok := pathID(c, "id", "transaction")
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, transactions)
+	var req models.ReverseTransactionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	reversal, err := h.TransactionService.ReverseTransaction(transactionID, req.Reason, c.GetInt("userID"))
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, reversal)
 }
@@ -0,0 +1,159 @@
+// go-bank-app/handlers/transfer_handler.go
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransferHandler struct untuk dependensi service
+type TransferHandler struct {
+	TransactionService services.TransactionService
+	AccountService     services.AccountService // Untuk otorisasi
+}
+
+// NewTransferHandler membuat instance baru dari TransferHandler
+func NewTransferHandler(transactionService services.TransactionService, accountService services.AccountService) *TransferHandler {
+	return &TransferHandler{TransactionService: transactionService, AccountService: accountService}
+}
+
+// GetTransferByID handles GET /transfers/:id
+// Transfer hanya bisa dilihat oleh pemilik akun pengirim atau akun penerima
+func (h *TransferHandler) GetTransferByID(c *gin.Context) {
+	transferID, ok := pathID(c, "id", "transfer")
+	if !ok {
+		return
+	}
+
+	loggedInUserID, exists := c.Get("userID")
+	if !exists {
+		unauthorized(c)
+		return
+	}
+
+	transfer, err := h.TransactionService.GetTransferByID(transferID)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+
+	// Otorisasi: user harus memiliki salah satu akun yang terlibat
+	authorized := false
+	for _, accountID := range []int{transfer.FromAccountID, transfer.ToAccountID} {
+		account, err := h.AccountService.GetAccountByID(accountID)
+		if err != nil {
+			middleware.Fail(c, fmt.Errorf("failed to verify account ownership: %w", err))
+			return
+		}
+		if account.UserID == loggedInUserID.(int) {
+			authorized = true
+			break
+		}
+	}
+	// Approver akun pengirim boleh melihat transfer yang harus mereka putuskan
+	if !authorized && transfer.Approval != nil {
+		isApprover, err := h.isApprover(transfer.FromAccountID, loggedInUserID.(int))
+		if err != nil {
+			middleware.Fail(c, fmt.Errorf("failed to check transfer approver: %w", err))
+			return
+		}
+		authorized = isApprover
+	}
+	// Admin/operator boleh melihat transfer mana pun (dicatat di audit log)
+	if !authorized && middleware.HasPermission(c, models.PermTransactionsReadAny) {
+		middleware.MarkPrivilegedAccess(c, models.PermTransactionsReadAny)
+		authorized = true
+	}
+	if !authorized {
+		forbidden(c, "Unauthorized to view this transfer")
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// GetTransfers handles GET /transfers
+// Mengembalikan semua transfer yang melibatkan akun milik user yang login
+func (h *TransferHandler) GetTransfers(c *gin.Context) {
+	loggedInUserID, exists := c.Get("userID")
+	if !exists {
+		unauthorized(c)
+		return
+	}
+
+	transfers, err := h.TransactionService.GetTransfersByUserID(loggedInUserID.(int))
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+// GetPendingApprovals handles GET /transfers/pending-approvals
+// Mengembalikan transfer yang menunggu persetujuan dari akun-akun di mana user yang login adalah approver
+func (h *TransferHandler) GetPendingApprovals(c *gin.Context) {
+	transfers, err := h.TransactionService.GetPendingApprovals(c.GetInt("userID"))
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, transfers)
+}
+
+// ApproveTransfer handles POST /transfers/:id/approve
+// Checker membukukan transfer yang ditahan; maker tidak boleh menyetujui transfernya sendiri.
+func (h *TransferHandler) ApproveTransfer(c *gin.Context) {
+	transferID, ok := pathID(c, "id", "transfer")
+	if !ok {
+		return
+	}
+
+	transfer, err := h.TransactionService.ApproveTransfer(transferID, c.GetInt("userID"))
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, transfer)
+}
+
+// RejectTransfer handles POST /transfers/:id/reject
+// Menolak transfer yang menunggu persetujuan dan melepas dana yang ditahan.
+func (h *TransferHandler) RejectTransfer(c *gin.Context) {
+	transferID, ok := pathID(c, "id", "transfer")
+	if !ok {
+		return
+	}
+
+	var req models.RejectTransferRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	transfer, err := h.TransactionService.RejectTransfer(transferID, c.GetInt("userID"), req.Reason)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, transfer)
+}
+
+// isApprover melaporkan apakah userID adalah approver akun accountID.
+func (h *TransferHandler) isApprover(accountID, userID int) (bool, error) {
+	approvers, err := h.AccountService.GetApprovers(accountID)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range approvers {
+		if a.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -2,10 +2,10 @@
 package handlers
 
 import (
-	"database/sql"
 	"net/http"
-	"strconv" // Tambahkan untuk strconv.Atoi
 
+	"go-bank-app/middleware"
+	"go-bank-app/models"
 	"go-bank-app/services" // Import service
 
 	"github.com/gin-gonic/gin"
@@ -23,31 +23,25 @@ func NewUserHandler(userService services.UserService) *UserHandler {
 
 // GetUserByID handles GET /users/:id
 func (h *UserHandler) GetUserByID(c *gin.Context) { // Perhatikan receiver 'h *UserHandler'
-	idParam := c.Param("id")
-	requestedUserID, err := strconv.Atoi(idParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+	requestedUserID, ok := pathID(c, "id", "user")
+	if !ok {
 		return
 	}
 
-	loggedInUserID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+	if _, exists := c.Get("userID"); !exists {
+		unauthorized(c)
 		return
 	}
 
-	if requestedUserID != loggedInUserID.(int) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to access this user's profile"})
+	// User hanya boleh melihat profilnya sendiri, kecuali memiliki izin users:read
+	if !isSelfOrPermitted(c, requestedUserID, models.PermUsersRead) {
+		forbidden(c, "Unauthorized to access this user's profile")
 		return
 	}
 
 	user, err := h.UserService.GetUserByID(requestedUserID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
-		}
+		middleware.Fail(c, err)
 		return
 	}
 
@@ -55,11 +49,64 @@ func (h *UserHandler) GetUserByID(c *gin.Context) { // Perhatikan receiver 'h *U
 }
 
 // GetAllUsers handles GET /users
+// Hanya untuk admin/operator (dilindungi RequirePermission di routes)
 func (h *UserHandler) GetAllUsers(c *gin.Context) { // Perhatikan receiver 'h *UserHandler'
 	users, err := h.UserService.GetAllUsers()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve users"})
+		middleware.Fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, users)
 }
+
+// UpdateUserRole handles PUT /users/:id/role
+// Hanya untuk admin (dilindungi RequirePermission di routes)
+func (h *UserHandler) UpdateUserRole(c *gin.Context) {
+	userID, ok := pathID(c, "id", "user")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Admin tidak boleh menurunkan perannya sendiri agar sistem tidak kehilangan admin terakhir secara tidak sengaja
+	if userID == c.GetInt("userID") && req.Role != models.RoleAdmin {
+		badRequest(c, "cannot_demote_self", "Cannot remove your own admin role", "")
+		return
+	}
+
+	user, err := h.UserService.UpdateUserRole(userID, req.Role)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdatePreferredLanguage handles PUT /users/:id/language
+// User boleh mengubah bahasanya sendiri; admin boleh mengubah bahasa user lain.
+func (h *UserHandler) UpdatePreferredLanguage(c *gin.Context) {
+	userID, ok := pathID(c, "id", "user")
+	if !ok {
+		return
+	}
+	if !isSelfOrPermitted(c, userID, models.PermUsersManage) {
+		forbidden(c, "Unauthorized to change this user's language")
+		return
+	}
+
+	var req models.UpdatePreferredLanguageRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.UserService.UpdatePreferredLanguage(userID, req.PreferredLanguage)
+	if err != nil {
+		middleware.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
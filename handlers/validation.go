@@ -0,0 +1,61 @@
+// go-bank-app/handlers/validation.go
+package handlers
+
+import (
+	"reflect"
+	"strings"
+
+	"go-bank-app/accountnumber"
+	"go-bank-app/config"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// LegacyAccountNumbers dipakai validator account_number untuk menerima nomor akun lama, yang dulu
+// dibuat client tanpa check digit, selama nomor itu terdaftar di database. Diisi di main; jika nil,
+// hanya nomor dengan check digit yang valid yang diterima.
+var LegacyAccountNumbers repositories.AccountRepository
+
+// init mendaftarkan tipe kustom ke validator Gin agar tag seperti `binding:"required,gt=0"`
+// tetap bisa dipakai pada field money.Money (divalidasi berdasarkan jumlah minor unit-nya).
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+			if m, ok := field.Interface().(money.Money); ok {
+				return m.MinorUnits()
+			}
+			return nil
+		}, money.Money{})
+
+		// Nama field di error validasi memakai nama JSON-nya, sama dengan yang dikirim klien
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+
+		// `binding:"account_number"` menolak nomor akun dengan format atau check digit salah.
+		// Nomor dengan check digit yang valid lolos tanpa query ke database; selain itu hanya nomor
+		// lama yang terdaftar yang diterima.
+		v.RegisterValidation("account_number", func(fl validator.FieldLevel) bool {
+			number := fl.Field().String()
+			return config.AccountNumberScheme().Validate(number) == nil || isLegacyAccountNumber(number)
+		})
+	}
+}
+
+// isLegacyAccountNumber melaporkan apakah number adalah nomor akun lama yang terdaftar. Jika
+// database gagal dibaca, request diteruskan agar service yang melaporkan error sebenarnya, bukan
+// kesalahan validasi yang menyesatkan.
+func isLegacyAccountNumber(number string) bool {
+	if LegacyAccountNumbers == nil || len(number) < accountnumber.MinLength || len(number) > accountnumber.MaxLength {
+		return false
+	}
+	exists, err := LegacyAccountNumbers.AccountNumberExists(number)
+	return err != nil || exists
+}
@@ -0,0 +1,141 @@
+// go-bank-app/i18n/catalog.go
+package i18n
+
+import "strings"
+
+// messages translates every error code the API can return. English is the source text; a code
+// missing here falls back to the message the error was created with.
+var messages = map[string]map[Language]string{
+	// Request and routing
+	"invalid_request":   {English: "Malformed request body", Indonesian: "Body request tidak valid"},
+	"validation_failed": {English: "Request validation failed", Indonesian: "Validasi request gagal"},
+	"invalid_id":        {English: "Invalid ID format in path", Indonesian: "Format ID pada path tidak valid"},
+	"invalid_query":     {English: "Invalid query parameter", Indonesian: "Parameter query tidak valid"},
+	"invalid_status":    {English: "Invalid status filter", Indonesian: "Filter status tidak valid"},
+	"route_not_found":   {English: "Route not found", Indonesian: "Rute tidak ditemukan"},
+	"internal_error":    {English: "Internal server error", Indonesian: "Terjadi kesalahan pada server"},
+
+	// Authentication and authorization
+	"unauthorized":          {English: "Authentication required", Indonesian: "Autentikasi diperlukan"},
+	"invalid_token":         {English: "Invalid or expired token", Indonesian: "Token tidak valid atau sudah kedaluwarsa"},
+	"token_revoked":         {English: "Token has been revoked", Indonesian: "Token sudah dicabut"},
+	"forbidden":             {English: "You are not allowed to access this resource", Indonesian: "Anda tidak berhak mengakses resource ini"},
+	"invalid_credentials":   {English: "Invalid email or password", Indonesian: "Kredensial tidak valid"},
+	"invalid_refresh_token": {English: "Invalid or expired refresh token", Indonesian: "Refresh token tidak valid atau sudah kedaluwarsa"},
+	"refresh_token_reused":  {English: "Refresh token reuse detected, session revoked", Indonesian: "Refresh token dipakai ulang, sesi dicabut"},
+	"cannot_demote_self":    {English: "Cannot remove your own admin role", Indonesian: "Tidak dapat mencabut peran admin Anda sendiri"},
+
+	// Idempotency
+	"invalid_idempotency_key":    {English: "Idempotency-Key is too long", Indonesian: "Idempotency-Key terlalu panjang"},
+	"idempotency_key_in_use":     {English: "A request with this Idempotency-Key is still being processed, retry later", Indonesian: "Request dengan Idempotency-Key ini masih diproses, coba lagi nanti"},
+	"idempotency_key_reused":     {English: "Idempotency-Key was already used with a different request", Indonesian: "Idempotency-Key sudah dipakai untuk request yang berbeda"},
+	"idempotency_request_failed": {English: "The request with this Idempotency-Key failed after it may have been applied; check the result before retrying with a new key", Indonesian: "Request dengan Idempotency-Key ini gagal setelah mungkin sudah diproses; periksa hasilnya sebelum mengulang dengan kunci baru"},
+
+	// Not found
+	"account_not_found":        {English: "Account not found", Indonesian: "Akun tidak ditemukan"},
+	"user_not_found":           {English: "User not found", Indonesian: "User tidak ditemukan"},
+	"transaction_not_found":    {English: "Transaction not found", Indonesian: "Transaksi tidak ditemukan"},
+	"transfer_not_found":       {English: "Transfer not found", Indonesian: "Transfer tidak ditemukan"},
+	"hold_not_found":           {English: "Hold not found", Indonesian: "Hold tidak ditemukan"},
+	"standing_order_not_found": {English: "Standing order not found", Indonesian: "Standing order tidak ditemukan"},
+	"approver_not_found":       {English: "Approver not found", Indonesian: "Approver tidak ditemukan"},
+
+	// Conflicts with existing data
+	"email_taken":              {English: "Email already registered", Indonesian: "Email sudah terdaftar"},
+	"duplicate_account_number": {English: "Account number already exists", Indonesian: "Nomor akun sudah dipakai"},
+
+	// Balance, limits and account status
+	"insufficient_funds":        {English: "Insufficient funds", Indonesian: "Saldo tidak cukup"},
+	"limit_exceeded":            {English: "Account limit exceeded", Indonesian: "Batas transaksi akun terlampaui"},
+	"account_not_debitable":     {English: "Account status does not allow debits", Indonesian: "Status akun tidak mengizinkan debit"},
+	"account_not_creditable":    {English: "Account status does not allow credits", Indonesian: "Status akun tidak mengizinkan kredit"},
+	"invalid_status_transition": {English: "Invalid account status transition", Indonesian: "Perubahan status akun tidak diizinkan"},
+	"non_zero_balance":          {English: "Account balance must be zero or swept before closing", Indonesian: "Saldo akun harus nol atau dipindahkan sebelum akun ditutup"},
+	"funds_on_hold":             {English: "Account has funds on hold", Indonesian: "Akun masih memiliki dana yang ditahan"},
+
+	// Amounts and currencies
+	"invalid_amount":            {English: "Invalid amount", Indonesian: "Nominal tidak valid"},
+	"currency_mismatch":         {English: "Currency mismatch", Indonesian: "Mata uang tidak cocok"},
+	"unsupported_currency":      {English: "Unsupported currency", Indonesian: "Mata uang tidak didukung"},
+	"exchange_rate_unavailable": {English: "Exchange rate not available", Indonesian: "Kurs tidak tersedia"},
+
+	// Reversals, approvals and holds
+	"already_reversed":       {English: "Transaction has already been reversed", Indonesian: "Transaksi sudah dibatalkan sebelumnya"},
+	"not_reversible":         {English: "Transaction cannot be reversed", Indonesian: "Transaksi tidak dapat dibatalkan"},
+	"invalid_approver":       {English: "Invalid approver", Indonesian: "Approver tidak valid"},
+	"not_approver":           {English: "User is not an approver for this transfer", Indonesian: "User bukan approver untuk transfer ini"},
+	"transfer_not_pending":   {English: "Transfer is not awaiting approval", Indonesian: "Transfer tidak sedang menunggu persetujuan"},
+	"approval_expired":       {English: "Transfer approval has expired", Indonesian: "Batas waktu persetujuan transfer sudah lewat"},
+	"hold_not_active":        {English: "Hold is not active", Indonesian: "Hold tidak aktif"},
+	"invalid_hold_operation": {English: "Operation not allowed for this hold", Indonesian: "Operasi tidak diizinkan untuk hold ini"},
+
+	// Input rejected by a service
+	"same_account":             {English: "Source and destination are the same account", Indonesian: "Akun asal dan tujuan sama"},
+	"invalid_role":             {English: "Invalid role", Indonesian: "Peran tidak valid"},
+	"invalid_account_type":     {English: "Invalid account type", Indonesian: "Jenis akun tidak valid"},
+	"invalid_language":         {English: "Unsupported language", Indonesian: "Bahasa tidak didukung"},
+	"invalid_standing_order":   {English: "Invalid standing order", Indonesian: "Standing order tidak valid"},
+	"invalid_statement_period": {English: "Invalid statement period", Indonesian: "Periode rekening koran tidak valid"},
+	"invalid_format":           {English: "Unsupported statement format", Indonesian: "Format rekening koran tidak didukung"},
+	"unknown_fee_operation":    {English: "Unknown fee operation", Indonesian: "Jenis operasi biaya tidak dikenal"},
+	"invalid_interest_plan":    {English: "Invalid interest plan", Indonesian: "Rencana bunga tidak valid"},
+	"invalid_interest_date":    {English: "Interest can only be accrued for days that have ended", Indonesian: "Bunga hanya dapat diakrualkan untuk hari yang sudah berakhir"},
+	"invalid_overdraft":        {English: "Invalid overdraft", Indonesian: "Fasilitas cerukan tidak valid"},
+	"invalid_cursor":           {English: "Invalid pagination cursor", Indonesian: "Cursor halaman tidak valid"},
+}
+
+// Message returns the translation of an error code, or ok = false if the catalog has none.
+func Message(lang Language, code string) (msg string, ok bool) {
+	msg, ok = messages[code][lang]
+	if !ok {
+		msg, ok = messages[code][English]
+	}
+	return msg, ok
+}
+
+// fieldRules translates the validation rules used in `binding` tags. {param} is replaced by the
+// rule's parameter. Rules with a separate wording for text fields (length rather than value) have
+// a "<rule>:text" entry.
+var fieldRules = map[string]map[Language]string{
+	"required":       {English: "is required", Indonesian: "wajib diisi"},
+	"email":          {English: "must be a valid email address", Indonesian: "harus berupa alamat email yang valid"},
+	"oneof":          {English: "must be one of {param}", Indonesian: "harus salah satu dari {param}"},
+	"gt":             {English: "must be greater than {param}", Indonesian: "harus lebih besar dari {param}"},
+	"gte":            {English: "must be at least {param}", Indonesian: "minimal {param}"},
+	"lt":             {English: "must be less than {param}", Indonesian: "harus lebih kecil dari {param}"},
+	"lte":            {English: "must be at most {param}", Indonesian: "maksimal {param}"},
+	"min":            {English: "must be at least {param}", Indonesian: "minimal {param}"},
+	"max":            {English: "must be at most {param}", Indonesian: "maksimal {param}"},
+	"min:text":       {English: "must be at least {param} characters long", Indonesian: "minimal {param} karakter"},
+	"max:text":       {English: "must be at most {param} characters long", Indonesian: "maksimal {param} karakter"},
+	"len:text":       {English: "must be exactly {param} characters long", Indonesian: "harus tepat {param} karakter"},
+	"datetime":       {English: "must be a date in the format {param}", Indonesian: "harus berupa tanggal dengan format {param}"},
+	"account_number": {English: "must be a valid account number", Indonesian: "harus berupa nomor akun yang valid"},
+	"type":           {English: "must be of type {param}", Indonesian: "harus bertipe {param}"},
+}
+
+// FieldMessage describes a violated validation rule, e.g. FieldMessage(Indonesian, "min", "6", true)
+// returns "minimal 6 karakter". text reports whether the field is a string.
+func FieldMessage(lang Language, rule, param string, text bool) string {
+	templates, ok := fieldRules[rule+":text"]
+	if !ok || !text {
+		templates, ok = fieldRules[rule]
+	}
+	if !ok {
+		if lang == Indonesian {
+			return "tidak memenuhi aturan " + rule
+		}
+		return "failed the " + rule + " rule"
+	}
+	tmpl, ok := templates[lang]
+	if !ok {
+		tmpl = templates[English]
+	}
+	if rule == "oneof" {
+		param = strings.Join(strings.Fields(param), ", ")
+	}
+	if rule == "datetime" {
+		param = strings.NewReplacer("2006", "YYYY", "01", "MM", "02", "DD").Replace(param)
+	}
+	return strings.ReplaceAll(tmpl, "{param}", param)
+}
@@ -0,0 +1,69 @@
+// go-bank-app/i18n/language.go
+
+// Package i18n holds the translations of API messages and picks the language a response is
+// written in.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a supported response language, identified by its ISO 639-1 code.
+type Language string
+
+const (
+	Indonesian Language = "id"
+	English    Language = "en"
+)
+
+// Languages lists every supported language.
+var Languages = []Language{Indonesian, English}
+
+// Parse maps a language tag such as "id", "id-ID", "en_US" or "EN" to a supported language. "in",
+// the withdrawn code for Indonesian that older Android and Java clients still send, is accepted too.
+func Parse(tag string) (Language, error) {
+	base := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	switch base {
+	case "id", "in":
+		return Indonesian, nil
+	case "en":
+		return English, nil
+	}
+	return "", fmt.Errorf("unsupported language %q, want id or en", tag)
+}
+
+// Negotiate picks the supported language the client prefers most from an Accept-Language header,
+// e.g. "id-ID,id;q=0.9,en;q=0.8". ok is false when the header names no supported language.
+func Negotiate(acceptLanguage string) (lang Language, ok bool) {
+	type candidate struct {
+		lang Language
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if l, err := Parse(tag); err == nil && q > 0 {
+			candidates = append(candidates, candidate{l, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	// Stable so that among equal weights the one listed first wins
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang, true
+}
@@ -0,0 +1,114 @@
+// go-bank-app/interest/daycount.go
+
+// Package interest computes daily interest accruals from an annual rate and a day-count
+// convention.
+package interest
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go-bank-app/money"
+)
+
+// DayCount is a day-count convention: how many days a period counts for and how many days the
+// year has.
+type DayCount string
+
+const (
+	ACT365    DayCount = "ACT/365" // Actual days over a 365-day year
+	ACT360    DayCount = "ACT/360" // Actual days over a 360-day year
+	Thirty360 DayCount = "30/360"  // 30-day months over a 360-day year (US bond basis)
+)
+
+// ParseDayCount parses a day-count convention name, case-insensitively.
+func ParseDayCount(s string) (DayCount, error) {
+	switch dc := DayCount(strings.ToUpper(strings.TrimSpace(s))); dc {
+	case ACT365, ACT360, Thirty360:
+		return dc, nil
+	}
+	return "", fmt.Errorf("unknown day-count convention %q, want %s, %s or %s", s, ACT365, ACT360, Thirty360)
+}
+
+// YearFraction returns the fraction of a year between two dates under the convention. Only the
+// calendar dates of from and to are used.
+func (dc DayCount) YearFraction(from, to time.Time) *big.Rat {
+	switch dc {
+	case ACT360:
+		return big.NewRat(actualDays(from, to), 360)
+	case Thirty360:
+		return big.NewRat(thirty360Days(from, to), 360)
+	default:
+		return big.NewRat(actualDays(from, to), 365)
+	}
+}
+
+// actualDays counts calendar days from from to to. Dates are compared in UTC so a daylight
+// saving change in between does not shorten or lengthen a day.
+func actualDays(from, to time.Time) int64 {
+	f := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	t := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int64(t.Sub(f).Hours() / 24)
+}
+
+// thirty360Days counts days as if every month had 30 days: a 31st is treated as the 30th, and
+// an end date on the 31st only when the start date is on the 30th or 31st.
+func thirty360Days(from, to time.Time) int64 {
+	d1, d2 := from.Day(), to.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return int64(360*(to.Year()-from.Year()) + 30*(int(to.Month())-int(from.Month())) + d2 - d1)
+}
+
+// Plan is the interest an account earns: an annual rate and the convention it accrues under.
+type Plan struct {
+	AnnualRate *big.Rat // As a fraction (0.025 = 2.5% a year); zero means the account earns nothing
+	DayCount   DayCount
+}
+
+// ParsePlan builds a plan from an annual rate in percent ("2.5") and a day-count convention name.
+func ParsePlan(percent, dayCount string) (Plan, error) {
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(percent))
+	if !ok {
+		return Plan{}, fmt.Errorf("invalid interest rate %q", percent)
+	}
+	if rate.Sign() < 0 {
+		return Plan{}, fmt.Errorf("interest rate %s must not be negative", percent)
+	}
+	dc, err := ParseDayCount(dayCount)
+	if err != nil {
+		return Plan{}, err
+	}
+	return Plan{AnnualRate: rate.Quo(rate, big.NewRat(100, 1)), DayCount: dc}, nil
+}
+
+// Earns reports whether the plan pays any interest.
+func (p Plan) Earns() bool {
+	return p.AnnualRate != nil && p.AnnualRate.Sign() > 0
+}
+
+// RatePercent returns the annual rate in percent, e.g. "2.5".
+func (p Plan) RatePercent() string {
+	if p.AnnualRate == nil {
+		return "0"
+	}
+	percent := new(big.Rat).Mul(p.AnnualRate, big.NewRat(100, 1))
+	return strings.TrimRight(strings.TrimRight(percent.FloatString(6), "0"), ".")
+}
+
+// Daily returns the interest balance earns on day, i.e. from day to the next day, in major
+// units and unrounded. Negative balances earn nothing.
+func (p Plan) Daily(balance money.Money, day time.Time) *big.Rat {
+	if !p.Earns() || !balance.IsPositive() {
+		return new(big.Rat)
+	}
+	fraction := p.DayCount.YearFraction(day, day.AddDate(0, 0, 1))
+	accrued := new(big.Rat).Mul(balance.Rat(), p.AnnualRate)
+	return accrued.Mul(accrued, fraction)
+}
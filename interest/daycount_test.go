@@ -0,0 +1,155 @@
+package interest
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"go-bank-app/money"
+)
+
+func date(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+func TestParseDayCount(t *testing.T) {
+	for in, want := range map[string]DayCount{"ACT/365": ACT365, "act/360": ACT360, " 30/360 ": Thirty360} {
+		if got, err := ParseDayCount(in); err != nil || got != want {
+			t.Errorf("ParseDayCount(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "ACT/ACT", "30E/360"} {
+		if got, err := ParseDayCount(in); err == nil {
+			t.Errorf("ParseDayCount(%q) = %q, want error", in, got)
+		}
+	}
+}
+
+func TestYearFraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		dc       DayCount
+		from, to time.Time
+		want     *big.Rat
+	}{
+		{"ACT/365 over a leap year", ACT365, date(2024, 1, 1), date(2025, 1, 1), big.NewRat(366, 365)},
+		{"ACT/365 one day", ACT365, date(2024, 2, 28), date(2024, 2, 29), big.NewRat(1, 365)},
+		{"ACT/360 one month", ACT360, date(2024, 3, 1), date(2024, 4, 1), big.NewRat(31, 360)},
+		{"ACT/360 one year", ACT360, date(2023, 1, 1), date(2024, 1, 1), big.NewRat(365, 360)},
+		{"30/360 one month", Thirty360, date(2024, 1, 15), date(2024, 2, 15), big.NewRat(30, 360)},
+		{"30/360 one year", Thirty360, date(2024, 2, 29), date(2025, 3, 1), big.NewRat(362, 360)},
+		{"30/360 start on the 31st", Thirty360, date(2024, 1, 31), date(2024, 3, 1), big.NewRat(31, 360)},
+		{"30/360 30th to 31st counts nothing", Thirty360, date(2024, 1, 30), date(2024, 1, 31), big.NewRat(0, 1)},
+		{"30/360 31st kept when start is before the 30th", Thirty360, date(2024, 1, 15), date(2024, 1, 31), big.NewRat(16, 360)},
+		{"30/360 end of February", Thirty360, date(2023, 2, 28), date(2023, 3, 1), big.NewRat(3, 360)},
+		{"same day", ACT365, date(2024, 6, 1), date(2024, 6, 1), big.NewRat(0, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dc.YearFraction(tt.from, tt.to); got.Cmp(tt.want) != 0 {
+				t.Errorf("%s YearFraction(%s, %s) = %s, want %s", tt.dc, tt.from.Format("2006-01-02"), tt.to.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYearFractionUsesCalendarDates(t *testing.T) {
+	// Late evening in Jakarta and early morning UTC the next day are still one calendar day apart
+	jakarta := time.FixedZone("WIB", 7*3600)
+	from := time.Date(2024, 1, 1, 23, 0, 0, 0, jakarta)
+	to := time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC)
+	if got := ACT365.YearFraction(from, to); got.Cmp(big.NewRat(1, 365)) != 0 {
+		t.Errorf("YearFraction across zones = %s, want 1/365", got)
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	// 2024-03-10 is only 23 hours long in New York
+	from = time.Date(2024, 3, 10, 0, 0, 0, 0, newYork)
+	if got := ACT365.YearFraction(from, from.AddDate(0, 0, 1)); got.Cmp(big.NewRat(1, 365)) != 0 {
+		t.Errorf("YearFraction over a daylight saving change = %s, want 1/365", got)
+	}
+}
+
+// TestDailyAccrualsAddUpToTheMonth checks that day-by-day accrual, as the interest job runs it,
+// adds up to the convention's fraction for the whole month.
+func TestDailyAccrualsAddUpToTheMonth(t *testing.T) {
+	balance := money.MustParse("10000000", money.IDR)
+	tests := []struct {
+		dc    DayCount
+		month time.Time
+		days  int64 // Days the month counts for under the convention
+		year  int64
+	}{
+		{ACT365, date(2024, 1, 1), 31, 365},
+		{ACT365, date(2024, 2, 1), 29, 365},
+		{ACT360, date(2023, 2, 1), 28, 360},
+		{Thirty360, date(2024, 1, 1), 30, 360},
+		{Thirty360, date(2023, 2, 1), 30, 360},
+		{Thirty360, date(2024, 2, 1), 30, 360},
+		{Thirty360, date(2024, 4, 1), 30, 360},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dc)+" "+tt.month.Format("2006-01"), func(t *testing.T) {
+			plan := Plan{AnnualRate: big.NewRat(25, 1000), DayCount: tt.dc}
+			sum := new(big.Rat)
+			for day := tt.month; day.Month() == tt.month.Month(); day = day.AddDate(0, 0, 1) {
+				sum.Add(sum, plan.Daily(balance, day))
+			}
+			want := new(big.Rat).Mul(balance.Rat(), big.NewRat(25*tt.days, 1000*tt.year))
+			if sum.Cmp(want) != 0 {
+				t.Errorf("month accrued %s, want %s", sum.FloatString(10), want.FloatString(10))
+			}
+		})
+	}
+}
+
+func TestDaily(t *testing.T) {
+	plan, err := ParsePlan("2.5", "ACT/365")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := date(2024, 5, 20)
+	// 10,000,000 * 2.5% / 365 = 684.9315068...
+	if got := plan.Daily(money.MustParse("10000000", money.IDR), day); got.Cmp(big.NewRat(50000, 73)) != 0 {
+		t.Errorf("Daily = %s, want 684.9315068493", got.FloatString(10))
+	}
+	if got := plan.Daily(money.MustParse("-10000000", money.IDR), day); got.Sign() != 0 {
+		t.Errorf("Daily on a negative balance = %s, want 0", got.FloatString(10))
+	}
+	zero, _ := ParsePlan("0", "ACT/365")
+	if zero.Earns() {
+		t.Error("a 0% plan earns interest")
+	}
+	if got := zero.Daily(money.MustParse("10000000", money.IDR), day); got.Sign() != 0 {
+		t.Errorf("Daily on a 0%% plan = %s, want 0", got.FloatString(10))
+	}
+}
+
+func TestParsePlan(t *testing.T) {
+	for _, tt := range []struct{ percent, dayCount, want string }{
+		{"2.5", "ACT/365", "2.5"},
+		{" 0.125 ", "30/360", "0.125"},
+		{"3", "act/360", "3"},
+		{"0", "ACT/365", "0"},
+	} {
+		plan, err := ParsePlan(tt.percent, tt.dayCount)
+		if err != nil {
+			t.Errorf("ParsePlan(%q, %q): %v", tt.percent, tt.dayCount, err)
+			continue
+		}
+		if got := plan.RatePercent(); got != tt.want {
+			t.Errorf("ParsePlan(%q).RatePercent() = %q, want %q", tt.percent, got, tt.want)
+		}
+	}
+	for _, tt := range []struct{ percent, dayCount string }{
+		{"-1", "ACT/365"},
+		{"two", "ACT/365"},
+		{"", "ACT/365"},
+		{"2.5", "daily"},
+	} {
+		if _, err := ParsePlan(tt.percent, tt.dayCount); err == nil {
+			t.Errorf("ParsePlan(%q, %q) succeeded, want error", tt.percent, tt.dayCount)
+		}
+	}
+}
@@ -1,30 +1,42 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
-	"os"
+	"time"
 
 	"go-bank-app/config"
+	"go-bank-app/fees"
+	"go-bank-app/fx"
 	"go-bank-app/handlers"
 	"go-bank-app/repositories"
 	"go-bank-app/routes"
+	"go-bank-app/scheduler"
 	"go-bank-app/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	// Load configuration (if using Viper as suggested in Phase 5 Step 3)
-	// config.LoadConfig()
+	// Konfigurasi: default < file (-config / CONFIG_FILE) < environment variable < flag
+	config.BindFlags(flag.CommandLine)
+	flag.Parse()
+	if err := config.LoadConfig(); err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 
-	// Ensure JWT_SECRET_KEY is set. This is important for production.
-	if os.Getenv("JWT_SECRET_KEY") == "" {
-		log.Println("WARNING: JWT_SECRET_KEY environment variable not set. Using default key from config/config.go. DO NOT USE IN PRODUCTION!")
+	// Subcommand: go-bank-app [flags] migrate up|down [N]|status
+	if args := flag.Args(); len(args) > 0 {
+		if args[0] != "migrate" {
+			log.Fatalf("unknown command %q; %s", args[0], migrateUsage)
+		}
+		if err := runMigrate(args[1:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
 	}
-	// Or if using Viper:
-	// if config.AppCfg.JWTSecret == "" {
-	// 	log.Println("WARNING: JWT_SECRET_KEY (or jwt_secret in config) not set. Using hardcoded fallback. DO NOT USE IN PRODUCTION!")
-	// }
 
 	// Initialize database connection
 	config.InitDB()
@@ -36,20 +48,90 @@ func main() {
 	}()
 
 	// Initialize Repositories
-	userRepo := repositories.NewUserRepository(config.DB)
-	accountRepo := repositories.NewAccountRepository(config.DB)
-	transactionRepo := repositories.NewTransactionRepository(config.DB)
+	userRepo := repositories.NewUserRepository(config.DB, config.Dialect())
+	accountRepo := repositories.NewAccountRepository(config.DB, config.Dialect())
+	transactionRepo := repositories.NewTransactionRepository(config.DB, config.Dialect())
+	transferRepo := repositories.NewTransferRepository(config.DB, config.Dialect())
+	ledgerRepo := repositories.NewLedgerRepository(config.DB, config.Dialect())
+	tokenRepo := repositories.NewTokenRepository(config.DB, config.Dialect())
+	auditRepo := repositories.NewAuditRepository(config.DB, config.Dialect())
+	standingOrderRepo := repositories.NewStandingOrderRepository(config.DB, config.Dialect())
+	leaseRepo := repositories.NewLeaseRepository(config.DB, config.Dialect())
+	holdRepo := repositories.NewHoldRepository(config.DB, config.Dialect())
+	limitRepo := repositories.NewLimitRepository(config.DB, config.Dialect())
+	interestRepo := repositories.NewInterestRepository(config.DB, config.Dialect())
+	idempotencyRepo := repositories.NewIdempotencyRepository(config.DB, config.Dialect())
+	routes.IdempotencyRepo = idempotencyRepo
+	handlers.LegacyAccountNumbers = accountRepo // Nomor akun lama tanpa check digit tetap bisa dipakai
+
+	// Kurs valas: dari file jika fx.rates_file diisi (offline), selain itu dari tabel fx_rates
+	var rateProvider fx.RateProvider = repositories.NewFXRateRepository(config.DB, config.Dialect())
+	if config.AppCfg.FX.RatesFile != "" {
+		fileRates, err := fx.NewFileRateProvider(config.AppCfg.FX.RatesFile)
+		if err != nil {
+			log.Fatalf("Failed to load exchange rates: %v", err)
+		}
+		rateProvider = fileRates
+	}
+
+	// Biaya penarikan/transfer: tanpa fee_schedule_file tidak ada biaya yang dikenakan
+	var feeSchedule *fees.Schedule
+	if config.AppCfg.FeeScheduleFile != "" {
+		schedule, err := fees.LoadSchedule(config.AppCfg.FeeScheduleFile)
+		if err != nil {
+			log.Fatalf("Failed to load fee schedule: %v", err)
+		}
+		feeSchedule = schedule
+	}
 
 	// Initialize Services
-	userService := services.NewUserService(userRepo)
-	accountService := services.NewAccountService(accountRepo, transactionRepo)
-	transactionService := services.NewTransactionService(accountRepo, transactionRepo) // transactionService also requires accountRepo for transfer logic
+	tokenService := services.NewTokenService(tokenRepo, userRepo)
+	userService := services.NewUserService(userRepo, tokenService)
+	accountService := services.NewAccountService(accountRepo, transactionRepo, transferRepo, ledgerRepo, holdRepo, limitRepo, interestRepo, feeSchedule)
+	transactionService := services.NewTransactionService(accountRepo, transactionRepo, transferRepo, ledgerRepo, holdRepo, limitRepo, feeSchedule, rateProvider) // transactionService also requires accountRepo for transfer logic
+	ledgerService := services.NewLedgerService(ledgerRepo)
+	statementService := services.NewStatementService(accountRepo, transactionRepo)
+	auditService := services.NewAuditService(auditRepo)
+	holdService := services.NewHoldService(accountRepo, transactionRepo, ledgerRepo, holdRepo)
+	limitService := services.NewLimitService(accountRepo, limitRepo)
+	feeService := services.NewFeeService(accountRepo, feeSchedule)
+	interestService := services.NewInterestService(interestRepo, accountRepo, transactionRepo, ledgerRepo)
+	overdraftService := services.NewOverdraftService(accountRepo)
+	standingOrderService := services.NewStandingOrderService(standingOrderRepo, accountRepo, transactionRepo, transferRepo, ledgerRepo, holdRepo, limitRepo, feeSchedule, rateProvider, services.NewLogNotifier())
 
 	// Initialize Handlers
-	routes.AuthHandler = handlers.NewAuthHandler(userService)
+	routes.TokenService = tokenService
+	routes.AuditService = auditService
+	routes.AuthHandler = handlers.NewAuthHandler(userService, tokenService)
 	routes.UserHandler = handlers.NewUserHandler(userService)
 	routes.AccountHandler = handlers.NewAccountHandler(accountService)
 	routes.TransactionHandler = handlers.NewTransactionHandler(transactionService, accountService) // TransactionHandler also needs AccountService for transfer authorization
+	routes.TransferHandler = handlers.NewTransferHandler(transactionService, accountService)
+	routes.LedgerHandler = handlers.NewLedgerHandler(ledgerService)
+	routes.StatementHandler = handlers.NewStatementHandler(statementService, accountService)
+	routes.StandingOrderHandler = handlers.NewStandingOrderHandler(standingOrderService, accountService)
+	routes.HoldHandler = handlers.NewHoldHandler(holdService, accountService)
+	routes.LimitHandler = handlers.NewLimitHandler(limitService, accountService)
+	routes.FeeHandler = handlers.NewFeeHandler(feeService, accountService)
+	routes.InterestHandler = handlers.NewInterestHandler(interestService, accountService)
+	routes.OverdraftHandler = handlers.NewOverdraftHandler(overdraftService)
+
+	// Job latar belakang. Lease di DB memastikan tiap job hanya berjalan di satu instance sekaligus.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sched := scheduler.New(leaseRepo, scheduler.DefaultOwner())
+	sched.Register(scheduler.Job{Name: "standing-orders", Interval: config.AppCfg.StandingOrder.PollInterval, Run: standingOrderService.RunDue})
+	sched.Register(scheduler.Job{Name: "transfer-approval-expiry", Interval: config.AppCfg.Approval.SweepInterval, Run: transactionService.ExpirePendingTransfers})
+	sched.Register(scheduler.Job{Name: "hold-expiry", Interval: config.AppCfg.Hold.ExpirySweepInterval, Run: holdService.ExpireHolds})
+	sched.Register(scheduler.Job{Name: "interest-accrual", Interval: config.AppCfg.Interest.AccrualInterval, LeaseTTL: time.Hour, Run: interestService.RunDue})
+	sched.Register(scheduler.Job{Name: "idempotency-key-cleanup", Interval: config.AppCfg.Idempotency.CleanupInterval, Run: func(context.Context) error {
+		n, err := idempotencyRepo.DeleteExpired()
+		if n > 0 {
+			log.Printf("Deleted %d expired idempotency keys", n)
+		}
+		return err
+	}})
+	sched.Start(ctx)
 
 	// Initialize Gin router
 	router := gin.Default()
@@ -57,7 +139,9 @@ func main() {
 	// Setup all routes
 	routes.SetupRoutes(router)
 
-	// Run the server on port 8080
-	log.Printf("Server running on port %s", "8080") // Replace with config.AppCfg.ServerPort if using Viper
-	router.Run(":8080")                             // Replace with ":" + config.AppCfg.ServerPort if using Viper
+	addr := fmt.Sprintf(":%d", config.AppCfg.Server.Port)
+	log.Printf("Server running on port %d (%s profile)", config.AppCfg.Server.Port, config.AppCfg.Profile)
+	if err := router.Run(addr); err != nil {
+		log.Fatalf("Server stopped: %v", err)
+	}
 }
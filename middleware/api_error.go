@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// APIError adalah error yang sudah tahu bagaimana ia dijawab ke klien: status HTTP, kode mesin
+// yang stabil dan pesan untuk manusia. Handler dan middleware membuatnya untuk kesalahan yang
+// mereka temukan sendiri (ID tidak valid, akses ditolak); error service dipetakan oleh
+// ErrorMiddleware. Pesan diterjemahkan berdasarkan Code saat respons ditulis (lihat package i18n).
+type APIError struct {
+	Status  int
+	Code    string                 // Kode mesin, mis. "account_not_found"; tidak berubah antar versi
+	Message string                 // Pesan bahasa Inggris, dipakai jika Code tidak ada di katalog i18n
+	Detail  string                 // Rincian teknis yang tidak diterjemahkan, mis. aturan yang dilanggar
+	Details []FieldError           // Kesalahan per field, untuk body request yang tidak valid
+	Meta    map[string]interface{} // Data tambahan yang berguna bagi klien, mis. sisa batas debit
+	Err     error                  // Penyebab asli; dicatat di log, tidak pernah dikirim ke klien
+}
+
+func (e *APIError) Error() string {
+	if e.Detail != "" {
+		return e.Message + ": " + e.Detail
+	}
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *APIError) Unwrap() error { return e.Err }
+
+// FieldError menjelaskan satu field body request yang tidak valid.
+type FieldError struct {
+	Field   string `json:"field"`           // Nama field JSON, mis. "amount"
+	Code    string `json:"code"`            // Aturan yang dilanggar, mis. "required" atau "gt"
+	Param   string `json:"param,omitempty"` // Parameter aturan, mis. "6" untuk min=6
+	Message string `json:"message"`         // Penjelasan untuk manusia, dalam bahasa respons
+	text    bool   // Field bertipe string: min/max berarti panjang, bukan nilai
+}
+
+// ErrorBody adalah isi envelope error yang dikirim ke klien.
+type ErrorBody struct {
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Detail    string                 `json:"detail,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Details   []FieldError           `json:"details,omitempty"`
+	Meta      map[string]interface{} `json:"meta,omitempty"`
+}
+
+// ErrorEnvelope adalah bentuk setiap respons error API: {"error": {...}}.
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+// NewAPIError membuat APIError dengan status, kode dan pesan tertentu.
+func NewAPIError(status int, code, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// Fail mencatat err pada request dan menghentikan rantai handler. Respons error-nya ditulis oleh
+// ErrorMiddleware.
+func Fail(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// InvalidRequest mengubah error dari ShouldBindJSON menjadi APIError 400. Pelanggaran aturan
+// `binding` menjadi Details per field; JSON yang rusak dilaporkan di Detail apa adanya.
+func InvalidRequest(err error) *APIError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		apiErr := &APIError{Status: http.StatusBadRequest, Code: "validation_failed", Message: "Request validation failed", Err: err}
+		for _, fe := range validationErrs {
+			apiErr.Details = append(apiErr.Details, FieldError{
+				Field: fe.Field(),
+				Code:  fe.Tag(),
+				Param: fe.Param(),
+				text:  fe.Kind() == reflect.String,
+			})
+		}
+		return apiErr
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		return &APIError{Status: http.StatusBadRequest, Code: "invalid_request", Message: "Malformed request body", Detail: "request body is empty", Err: err}
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return &APIError{
+			Status:  http.StatusBadRequest,
+			Code:    "validation_failed",
+			Message: "Request validation failed",
+			Details: []FieldError{{Field: typeErr.Field, Code: "type", Param: typeErr.Type.String()}},
+			Err:     err,
+		}
+	}
+	return &APIError{Status: http.StatusBadRequest, Code: "invalid_request", Message: "Malformed request body", Detail: err.Error(), Err: err}
+}
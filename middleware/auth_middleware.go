@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -8,59 +9,66 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5" // Pastikan import yang benar untuk v5
 
-	"go-bank-app/auth"   // Import package auth kita
-	"go-bank-app/config" // Import config untuk JWTSecretKey
+	"go-bank-app/auth"     // Import package auth kita
+	"go-bank-app/services" // Untuk memeriksa daftar token yang dicabut
 )
 
 // AuthMiddleware memverifikasi JWT dan mengotorisasi permintaan.
-func AuthMiddleware() gin.HandlerFunc {
+// Token yang jti-nya ada di daftar pencabutan (misalnya setelah logout) ditolak.
+func AuthMiddleware(tokenService services.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort() // Hentikan pemrosesan request
+			Fail(c, NewAPIError(http.StatusUnauthorized, "unauthorized", "Authorization header required")) // Hentikan pemrosesan request
 			return
 		}
 
 		// Header harus dalam format "Bearer TOKEN"
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || strings.ToLower(tokenParts[0]) != "bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
-			c.Abort()
+			Fail(c, NewAPIError(http.StatusUnauthorized, "invalid_token", "Invalid token format"))
 			return
 		}
 
 		tokenString := tokenParts[1]
 
-		claims := &auth.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			// Memastikan metode penandatanganan cocok
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return config.JWTSecretKey, nil
-		})
-
+		claims, err := auth.ParseJWTToken(tokenString)
 		if err != nil {
-			if err == jwt.ErrSignatureInvalid {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token signature"})
-				c.Abort()
+			if errors.Is(err, jwt.ErrSignatureInvalid) || errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+				Fail(c, NewAPIError(http.StatusUnauthorized, "invalid_token", "Invalid token signature"))
 				return
 			}
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			Fail(c, NewAPIError(http.StatusUnauthorized, "invalid_token", "Invalid or expired token"))
+			return
+		}
+
+		// Token tanpa jti (diterbitkan sebelum mendukung pencabutan) tidak bisa dicabut, jadi ditolak
+		if claims.ID == "" || claims.ExpiresAt == nil {
+			Fail(c, NewAPIError(http.StatusUnauthorized, "invalid_token", "Invalid token"))
 			return
 		}
 
-		if !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+		revoked, err := tokenService.IsAccessTokenRevoked(claims.ID)
+		if err != nil {
+			Fail(c, fmt.Errorf("failed to check token revocation: %w", err))
+			return
+		}
+		if revoked {
+			Fail(c, NewAPIError(http.StatusUnauthorized, "token_revoked", "Token has been revoked"))
 			return
 		}
 
 		// Simpan userID dari token ke konteks Gin
 		// Ini akan sangat berguna untuk otorisasi (misalnya, user hanya bisa melihat akunnya sendiri)
 		c.Set("userID", claims.UserID)
+		// Peran dan izin untuk RequireRole / RequirePermission
+		c.Set("role", claims.Role)
+		c.Set("permissions", claims.Permissions)
+		// Preferensi bahasa untuk pesan error, lihat RequestLanguage
+		c.Set(languageKey, claims.Language)
+		// jti dan waktu kadaluarsa dibutuhkan untuk logout
+		c.Set("tokenID", claims.ID)
+		c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
 
 		c.Next() // Lanjutkan ke handler berikutnya
 	}
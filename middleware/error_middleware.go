@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-bank-app/fx"
+	"go-bank-app/i18n"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/services"
+)
+
+// errorMapping memetakan satu error domain ke respons HTTP-nya. Pesannya diambil dari katalog i18n
+// berdasarkan code.
+type errorMapping struct {
+	target error
+	status int
+	code   string
+}
+
+// errorMappings diperiksa berurutan dengan errors.Is; yang pertama cocok dipakai. Error yang tidak
+// ada di sini dijawab 500 internal_error tanpa membocorkan teksnya.
+var errorMappings = []errorMapping{
+	// Resource tidak ditemukan
+	{services.ErrAccountNotFound, http.StatusNotFound, "account_not_found"},
+	{services.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+	{services.ErrTransactionNotFound, http.StatusNotFound, "transaction_not_found"},
+	{services.ErrTransferNotFound, http.StatusNotFound, "transfer_not_found"},
+	{services.ErrHoldNotFound, http.StatusNotFound, "hold_not_found"},
+	{services.ErrStandingOrderNotFound, http.StatusNotFound, "standing_order_not_found"},
+	{services.ErrApproverNotFound, http.StatusNotFound, "approver_not_found"},
+
+	// Autentikasi
+	{services.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+	{services.ErrRefreshTokenReused, http.StatusUnauthorized, "refresh_token_reused"},
+	{services.ErrInvalidRefreshToken, http.StatusUnauthorized, "invalid_refresh_token"},
+
+	// Konflik dengan data yang sudah ada
+	{services.ErrDuplicateEmail, http.StatusConflict, "email_taken"},
+	{services.ErrDuplicateAccountNumber, http.StatusConflict, "duplicate_account_number"},
+
+	// Saldo, batas debit dan status akun
+	{services.ErrInsufficientFunds, http.StatusBadRequest, "insufficient_funds"},
+	{services.ErrLimitExceeded, http.StatusUnprocessableEntity, "limit_exceeded"},
+	{services.ErrAccountNotDebitable, http.StatusConflict, "account_not_debitable"},
+	{services.ErrAccountNotCreditable, http.StatusConflict, "account_not_creditable"},
+	{services.ErrInvalidStatusTransition, http.StatusConflict, "invalid_status_transition"},
+	{services.ErrNonZeroBalance, http.StatusConflict, "non_zero_balance"},
+	{services.ErrFundsOnHold, http.StatusConflict, "funds_on_hold"},
+
+	// Jumlah uang dan mata uang
+	{money.ErrTooPrecise, http.StatusBadRequest, "invalid_amount"},
+	{money.ErrCurrencyMismatch, http.StatusBadRequest, "currency_mismatch"},
+	{money.ErrUnsupportedCurrency, http.StatusBadRequest, "unsupported_currency"},
+	{fx.ErrRateNotFound, http.StatusUnprocessableEntity, "exchange_rate_unavailable"},
+
+	// Reversal, approval dan hold
+	{services.ErrAlreadyReversed, http.StatusConflict, "already_reversed"},
+	{services.ErrNotReversible, http.StatusUnprocessableEntity, "not_reversible"},
+	{services.ErrInvalidApprover, http.StatusBadRequest, "invalid_approver"},
+	{services.ErrNotApprover, http.StatusForbidden, "not_approver"},
+	{services.ErrTransferNotPending, http.StatusConflict, "transfer_not_pending"},
+	{services.ErrApprovalExpired, http.StatusConflict, "approval_expired"},
+	{services.ErrHoldNotActive, http.StatusConflict, "hold_not_active"},
+	{services.ErrInvalidHoldOperation, http.StatusConflict, "invalid_hold_operation"},
+
+	// Input yang ditolak service
+	{services.ErrSameAccount, http.StatusBadRequest, "same_account"},
+	{services.ErrInvalidRole, http.StatusBadRequest, "invalid_role"},
+	{services.ErrInvalidLanguage, http.StatusBadRequest, "invalid_language"},
+	{services.ErrInvalidAccountType, http.StatusBadRequest, "invalid_account_type"},
+	{services.ErrInvalidStandingOrder, http.StatusBadRequest, "invalid_standing_order"},
+	{services.ErrInvalidStatementPeriod, http.StatusBadRequest, "invalid_statement_period"},
+	{services.ErrUnknownFeeOperation, http.StatusBadRequest, "unknown_fee_operation"},
+	{services.ErrInvalidInterestPlan, http.StatusBadRequest, "invalid_interest_plan"},
+	{services.ErrInvalidInterestDate, http.StatusBadRequest, "invalid_interest_date"},
+	{services.ErrInvalidOverdraft, http.StatusBadRequest, "invalid_overdraft"},
+	{models.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor"},
+}
+
+// ErrorMiddleware menulis envelope error untuk request yang handler-nya mencatat error lewat
+// c.Error (atau Fail) tanpa menulis respons. Dipasang paling luar agar berlaku untuk semua rute.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		WriteError(c)
+	}
+}
+
+// WriteError menulis envelope untuk error terakhir yang tercatat pada request, jika ada dan respons
+// belum ditulis. Middleware yang membaca respons setelah c.Next() (idempotency, audit) memanggilnya
+// lebih dulu agar melihat status dan body error yang sebenarnya.
+func WriteError(c *gin.Context) {
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	apiErr := resolveError(c.Errors.Last().Err)
+	requestID := c.GetString(requestIDKey)
+	if apiErr.Status >= http.StatusInternalServerError {
+		log.Printf("[%s] %s %s: %v", requestID, c.Request.Method, c.Request.URL.Path, c.Errors.Last().Err)
+	}
+
+	lang := RequestLanguage(c)
+	message, ok := i18n.Message(lang, apiErr.Code)
+	if !ok {
+		message = apiErr.Message
+	}
+	details := make([]FieldError, len(apiErr.Details))
+	for i, fe := range apiErr.Details {
+		fe.Message = i18n.FieldMessage(lang, fe.Code, fe.Param, fe.text)
+		details[i] = fe
+	}
+
+	c.Header("Content-Language", string(lang))
+	c.JSON(apiErr.Status, ErrorEnvelope{Error: ErrorBody{
+		Code:      apiErr.Code,
+		Message:   message,
+		Detail:    apiErr.Detail,
+		RequestID: requestID,
+		Details:   details,
+		Meta:      apiErr.Meta,
+	}})
+}
+
+// resolveError menentukan respons untuk err: APIError dipakai apa adanya, error domain lewat
+// errorMappings, sisanya 500.
+func resolveError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	for _, m := range errorMappings {
+		if !errors.Is(err, m.target) {
+			continue
+		}
+		resolved := &APIError{Status: m.status, Code: m.code, Message: m.target.Error(), Detail: detailOf(err, m.target), Err: err}
+		var limitErr *services.LimitExceededError
+		if errors.As(err, &limitErr) {
+			resolved.Meta = map[string]interface{}{"limit": limitErr.Limit}
+			if limitErr.RemainingCount != nil {
+				resolved.Meta["remaining"] = *limitErr.RemainingCount
+			} else {
+				resolved.Meta["remaining"] = limitErr.Remaining
+			}
+		}
+		return resolved
+	}
+
+	return &APIError{Status: http.StatusInternalServerError, Code: "internal_error", Message: "Internal server error", Err: err}
+}
+
+// detailOf mengambil rincian yang ditambahkan setelah error domain saat dibungkus, mis.
+// "rate must not be negative" dari "failed to ...: invalid interest plan: rate must not be negative".
+// Konteks internal di depannya ("failed to ...") tidak pernah dikirim ke klien.
+func detailOf(err, target error) string {
+	// *LimitExceededError tidak memuat teks ErrLimitExceeded; pesannya sendiri adalah rinciannya
+	var limitErr *services.LimitExceededError
+	if errors.As(err, &limitErr) {
+		return limitErr.Error()
+	}
+
+	msg := err.Error()
+	i := strings.Index(msg, target.Error())
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimPrefix(msg[i+len(target.Error()):], ": ")
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-bank-app/config"
+	"go-bank-app/models"
+	"go-bank-app/repositories"
+	"go-bank-app/services"
+)
+
+// IdempotencyKeyHeader adalah header yang dikirim klien untuk menandai request yang aman diulang.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength sesuai dengan panjang kolom idempotency_key di database.
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyMiddleware membuat endpoint yang memindahkan uang aman untuk di-retry.
+// Request tanpa header Idempotency-Key diproses seperti biasa. Untuk request dengan header:
+//   - kunci baru: request diproses dan response-nya disimpan;
+//   - kunci sama dan body sama: response asli diputar ulang tanpa memproses ulang;
+//   - kunci sama tetapi body berbeda: 422 Unprocessable Entity;
+//   - kunci sama dan request pertama masih berjalan: 409 Conflict;
+//   - kunci sama dan request pertama gagal setelah mungkin sudah mengubah data: 409 Conflict.
+//
+// Kunci dilepas (boleh diulang dengan kunci yang sama) hanya jika handler terbukti tidak mengubah
+// apa pun: handler panik, atau gagal 5xx karena transaksinya di-rollback. Response lain disimpan
+// selama config.AppCfg.Idempotency.TTL. Kunci in_progress dari request yang mati diambil alih
+// oleh retry setelah Idempotency.LockTimeout.
+//
+// Harus dipasang setelah AuthMiddleware karena kunci disimpan per user.
+func IdempotencyMiddleware(repo repositories.IdempotencyRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			Fail(c, NewAPIError(http.StatusBadRequest, "invalid_idempotency_key", "Idempotency-Key is too long"))
+			return
+		}
+
+		userID := c.GetInt("userID")
+
+		// Baca body untuk sidik jari, lalu kembalikan agar handler tetap bisa membacanya
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			Fail(c, NewAPIError(http.StatusBadRequest, "invalid_request", "Failed to read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &models.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: requestFingerprint(c.Request.Method, c.Request.URL.Path, body),
+		}
+
+		cfg := config.AppCfg.Idempotency
+		reserved, err := repo.Reserve(record, cfg.LockTimeout)
+		if err != nil {
+			Fail(c, fmt.Errorf("failed to reserve idempotency key: %w", err))
+			return
+		}
+
+		if !reserved {
+			existing, err := repo.GetByKey(userID, key)
+			if err != nil {
+				// Bisa terjadi jika request pertama baru saja gagal dan kuncinya dilepas
+				log.Printf("Error loading idempotency key: %v", err)
+				Fail(c, NewAPIError(http.StatusConflict, "idempotency_key_in_use", "A request with this Idempotency-Key is being processed, retry later"))
+				return
+			}
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				_ = c.Error(NewAPIError(http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency-Key was already used with a different request"))
+			case existing.Status == models.IdempotencyFailed:
+				_ = c.Error(NewAPIError(http.StatusConflict, "idempotency_request_failed", "The request with this Idempotency-Key failed and may have been applied"))
+			case existing.Status != models.IdempotencyCompleted:
+				_ = c.Error(NewAPIError(http.StatusConflict, "idempotency_key_in_use", "A request with this Idempotency-Key is already in progress"))
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.ResponseCode, "application/json; charset=utf-8", existing.ResponseBody)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		panicked := true
+		defer func() {
+			// Transaksi handler yang panik sudah di-rollback: lepaskan kunci agar klien boleh mencoba lagi
+			if panicked {
+				release(repo, userID, key)
+			}
+		}()
+
+		c.Next()
+		panicked = false
+		// Tulis envelope error sekarang agar ikut tersimpan dan diputar ulang
+		WriteError(c)
+
+		status := models.IdempotencyCompleted
+		if recorder.Status() >= http.StatusInternalServerError {
+			if rolledBack(c) {
+				release(repo, userID, key)
+				return
+			}
+			// Gagal setelah commit (mis. saat membaca ulang hasilnya): uang mungkin sudah berpindah
+			status = models.IdempotencyFailed
+		}
+		if err := repo.Finish(userID, key, status, recorder.Status(), recorder.body.Bytes(), cfg.TTL); err != nil {
+			// Kunci tetap in_progress dan baru bisa diambil alih setelah LockTimeout
+			log.Printf("Error storing idempotent response: %v", err)
+		}
+	}
+}
+
+// rolledBack melaporkan apakah error terakhir request berasal dari transaksi DB yang di-rollback,
+// sehingga request terbukti tidak mengubah apa pun.
+func rolledBack(c *gin.Context) bool {
+	last := c.Errors.Last()
+	return last != nil && services.RolledBack(last.Err)
+}
+
+// release menghapus kunci agar request yang sama boleh diproses ulang.
+func release(repo repositories.IdempotencyRepository, userID int, key string) {
+	if err := repo.Delete(userID, key); err != nil {
+		log.Printf("Error releasing idempotency key: %v", err)
+	}
+}
+
+// requestFingerprint menghasilkan hash yang membedakan request dengan kunci sama tetapi isi berbeda.
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder meneruskan response ke klien sekaligus menyalin body-nya untuk disimpan.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"go-bank-app/config"
+	"go-bank-app/i18n"
+)
+
+// languageKey adalah kunci konteks Gin berisi preferensi bahasa user yang login (dari JWT).
+const languageKey = "language"
+
+// RequestLanguage menentukan bahasa respons untuk request ini: preferensi bahasa user yang login,
+// lalu header Accept-Language, lalu config.DefaultLanguage. Preferensi didahulukan karena dipilih
+// sendiri oleh user, sedangkan Accept-Language biasanya hanya mengikuti bahasa perangkat.
+func RequestLanguage(c *gin.Context) i18n.Language {
+	if pref := c.GetString(languageKey); pref != "" {
+		if lang, err := i18n.Parse(pref); err == nil {
+			return lang
+		}
+	}
+	if lang, ok := i18n.Negotiate(c.GetHeader("Accept-Language")); ok {
+		return lang
+	}
+	return config.DefaultLanguage()
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader adalah header yang membawa ID request. Klien boleh mengirimnya sendiri untuk
+// menelusuri request-nya; jika tidak, server membuatkannya.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey adalah kunci konteks Gin berisi ID request.
+const requestIDKey = "requestID"
+
+// maxRequestIDLength membatasi ID dari klien agar tidak membanjiri log.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware memberi setiap request sebuah ID yang dikembalikan di header X-Request-ID
+// dan di envelope error, sehingga laporan klien bisa dicocokkan dengan log server.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		c.Set(requestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// newRequestID menghasilkan 16 byte acak dalam bentuk heksadesimal.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,78 @@
+// go-bank-app/migrate.go
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"go-bank-app/config"
+	"go-bank-app/migrations"
+)
+
+const migrateUsage = "usage: go-bank-app [flags] migrate up | down [N] | status"
+
+// runMigrate menjalankan subcommand migrate: up menerapkan semua migrasi yang belum diterapkan, down
+// membatalkan N migrasi terakhir (default 1), status menampilkan migrasi dan kapan diterapkan.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	steps := 1
+	switch {
+	case args[0] == "down" && len(args) == 2:
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid step count %q: must be a positive integer", args[1])
+		}
+		steps = n
+	case len(args) != 1:
+		return errors.New(migrateUsage)
+	}
+
+	config.OpenDB()
+	defer config.DB.Close()
+	migrator, err := migrations.New(config.DB, config.Dialect())
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied     %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+	case "down":
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, m := range rolledBack {
+			fmt.Printf("rolled back %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(rolledBack) == 0 {
+			fmt.Println("no migrations to roll back")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		w.Flush()
+		return err
+	}
+	return errors.New(migrateUsage)
+}
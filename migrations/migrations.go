@@ -0,0 +1,444 @@
+// go-bank-app/migrations/migrations.go
+
+// Package migrations applies the versioned SQL schema embedded in the binary. Each database dialect
+// has its own directory under sql/ holding pairs of NNNN_name.up.sql and NNNN_name.down.sql files;
+// every dialect must have the same versions. Applied versions are recorded in the schema_migrations
+// table, and a database lock keeps concurrently starting instances from migrating at the same time.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-bank-app/dialect"
+)
+
+//go:embed sql
+var files embed.FS
+
+// lockName is the lock held while migrating.
+const lockName = "go-bank-app:migrate"
+
+// ErrUnmanagedSchema is returned by Up when the database already has application tables but no
+// recorded migrations, e.g. a database created from the old schema.sql.
+var ErrUnmanagedSchema = errors.New("database has tables that were not created by migrations")
+
+// DefaultLockTimeout is how long a Migrator waits for another instance to finish migrating.
+const DefaultLockTimeout = time.Minute
+
+// Migration is one schema version.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration together with when it was applied (nil if pending).
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies and rolls back migrations on a database.
+type Migrator struct {
+	db          *sql.DB
+	dialect     dialect.Dialect
+	migrations  []Migration
+	LockTimeout time.Duration
+}
+
+// New returns a Migrator for the migrations embedded in the binary for d.
+func New(db *sql.DB, d dialect.Dialect) (*Migrator, error) {
+	sub, err := fs.Sub(files, path.Join("sql", d.Name()))
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := Load(sub)
+	if err != nil {
+		return nil, err
+	}
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no migrations embedded for %s", d.Name())
+	}
+	return &Migrator{db: db, dialect: d, migrations: migrations, LockTimeout: DefaultLockTimeout}, nil
+}
+
+// Load reads NNNN_name.up.sql / NNNN_name.down.sql pairs from the root of fsys, ordered by version.
+// Every version needs both files, and versions must be unique.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+		base := strings.TrimSuffix(e.Name(), ".sql")
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: name must end in .up.sql or .down.sql", e.Name())
+		}
+		base = strings.TrimSuffix(base, "."+direction)
+
+		digits, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(digits)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must start with a positive version number and an underscore", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by both %q and %q", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies every pending migration in version order and returns the ones it applied. On a
+// database with no recorded migrations it first checks that none of the tables the first migration
+// creates exists yet, and returns ErrUnmanagedSchema otherwise: the baseline cannot adopt tables it
+// did not create.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			if err := m.checkEmpty(ctx, conn); err != nil {
+				return err
+			}
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			record := func(q dialect.Querier) error {
+				_, err := q.ExecContext(ctx, m.dialect.Rebind("INSERT INTO schema_migrations (version, name) VALUES (?, ?)"), mig.Version, mig.Name)
+				return err
+			}
+			if err := m.run(ctx, conn, mig.Up, record); err != nil {
+				return fmt.Errorf("migration %04d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the steps most recently applied migrations and returns them, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, errors.New("steps must be positive")
+	}
+	var rolledBack []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+			unrecord := func(q dialect.Querier) error {
+				_, err := q.ExecContext(ctx, m.dialect.Rebind("DELETE FROM schema_migrations WHERE version = ?"), mig.Version)
+				return err
+			}
+			if err := m.run(ctx, conn, mig.Down, unrecord); err != nil {
+				return fmt.Errorf("migration %04d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			rolledBack = append(rolledBack, mig)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status lists every known migration and when it was applied. Versions recorded in the database
+// that this binary does not know about (applied by a newer release) are reported as an error.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	done, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{Migration: mig}
+		if at, ok := done[mig.Version]; ok {
+			statuses[i].AppliedAt = &at
+			delete(done, mig.Version)
+		}
+	}
+	if len(done) > 0 {
+		unknown := make([]int, 0, len(done))
+		for v := range done {
+			unknown = append(unknown, v)
+		}
+		sort.Ints(unknown)
+		return statuses, fmt.Errorf("database has migrations this binary does not know: %v", unknown)
+	}
+	return statuses, nil
+}
+
+// withLock runs fn on a dedicated connection while holding the migration lock. Locks belong to a
+// database session, so acquiring, migrating and releasing must all use the same connection.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.dialect.Lock(ctx, conn, lockName, m.LockTimeout); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer m.dialect.Unlock(context.Background(), conn, lockName)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// run executes a migration script and then record, which updates schema_migrations. Where DDL is
+// transactional both happen in one transaction, so a failed migration leaves no trace. MySQL
+// commits every DDL statement implicitly, so there a migration that fails halfway is not rolled
+// back: undo what it did by hand (for the first migration, drop the tables it created) before
+// running it again.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, script string, record func(q dialect.Querier) error) error {
+	if !m.dialect.TransactionalDDL() {
+		if err := execScript(ctx, conn, script); err != nil {
+			return err
+		}
+		return record(conn)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := execScript(ctx, tx, script); err != nil {
+		return err
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// createTablePattern finds the tables a migration script creates.
+var createTablePattern = regexp.MustCompile(`(?i)\bCREATE\s+TABLE\s+(\w+)`)
+
+// checkEmpty returns ErrUnmanagedSchema if any table created by the first migration already exists.
+func (m *Migrator) checkEmpty(ctx context.Context, conn *sql.Conn) error {
+	var existing []string
+	for _, stmt := range SplitStatements(m.migrations[0].Up) {
+		match := createTablePattern.FindStringSubmatch(stmt)
+		if match == nil {
+			continue
+		}
+		ok, err := m.dialect.TableExists(ctx, conn, match[1])
+		if err != nil {
+			return fmt.Errorf("failed to check for table %s: %w", match[1], err)
+		}
+		if ok {
+			existing = append(existing, match[1])
+		}
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("%w: schema_migrations is empty but %s already exist; create an empty database, run the migrations there and move the data over",
+			ErrUnmanagedSchema, strings.Join(existing, ", "))
+	}
+	return nil
+}
+
+// ensureTable creates schema_migrations if it does not exist yet.
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	// Plain types that every dialect understands
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT NOT NULL PRIMARY KEY,
+		name       VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// appliedVersions returns the applied versions and when each was applied.
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	done := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = appliedAt
+	}
+	return done, rows.Err()
+}
+
+// execScript runs the statements of a migration file one by one, since the MySQL driver rejects
+// multi-statement queries unless multiStatements is enabled in the DSN.
+func execScript(ctx context.Context, q dialect.Querier, script string) error {
+	for i, stmt := range SplitStatements(script) {
+		if _, err := q.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("statement %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// SplitStatements splits an SQL script on semicolons that end a statement, i.e. ones outside
+// quotes, PostgreSQL dollar-quoted bodies ($$ ... $$ or $tag$ ... $tag$) and comments. Comments are
+// dropped and empty statements are skipped.
+func SplitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); i++ {
+		ch := script[i]
+		switch {
+		case ch == '-' && strings.HasPrefix(script[i:], "--"), ch == '#':
+			// Line comment: skip to the end of the line, keep the newline
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				i = len(script)
+				continue
+			}
+			i += end - 1
+		case ch == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+				continue
+			}
+			i += end + 3
+		case ch == '\'' || ch == '"' || ch == '`':
+			// Quoted string or identifier; a doubled quote or a backslash escapes the quote
+			j := i + 1
+			for j < len(script) {
+				if script[j] == '\\' && ch != '`' {
+					j += 2
+					continue
+				}
+				if script[j] == ch {
+					if j+1 < len(script) && script[j+1] == ch {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			if j >= len(script) {
+				j = len(script) - 1
+			}
+			current.WriteString(script[i : j+1])
+			i = j
+		case ch == '$' && dollarTag(script[i:]) != "":
+			// Dollar-quoted function body: copy through to the matching closing tag
+			tag := dollarTag(script[i:])
+			end := strings.Index(script[i+len(tag):], tag)
+			if end < 0 {
+				current.WriteString(script[i:])
+				i = len(script)
+				continue
+			}
+			j := i + len(tag) + end + len(tag)
+			current.WriteString(script[i:j])
+			i = j - 1
+		case ch == ';':
+			flush()
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	flush()
+	return statements
+}
+
+// dollarTag returns the dollar-quote opening s, such as $$ or $body$, or "" if s does not start with one.
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == '$':
+			return s[:i+1]
+		case ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || i > 1 && ch >= '0' && ch <= '9':
+		default:
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,173 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"go-bank-app/dialect"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"one per semicolon", "CREATE TABLE a (x INT);\nCREATE TABLE b (y INT);\n",
+			[]string{"CREATE TABLE a (x INT)", "CREATE TABLE b (y INT)"}},
+		{"no trailing semicolon", "SELECT 1; SELECT 2", []string{"SELECT 1", "SELECT 2"}},
+		{"semicolon in a string", "INSERT INTO t VALUES ('a;b');SELECT 1",
+			[]string{"INSERT INTO t VALUES ('a;b')", "SELECT 1"}},
+		{"doubled quote", "SELECT 'it''s; fine'; SELECT 2", []string{"SELECT 'it''s; fine'", "SELECT 2"}},
+		{"backslash escape", `SELECT 'a\';b'; SELECT 3`, []string{`SELECT 'a\';b'`, "SELECT 3"}},
+		{"quoted identifiers", "SELECT \"x;y\" FROM `t;u`; SELECT 4", []string{"SELECT \"x;y\" FROM `t;u`", "SELECT 4"}},
+		{"line comments", "-- setup; nothing to run\nSELECT 1; -- trailing; comment\nSELECT 2",
+			[]string{"SELECT 1", "SELECT 2"}},
+		{"comment inside a statement", "SELECT 1, -- one; two\n 2;", []string{"SELECT 1, \n 2"}},
+		{"hash comment", "# mysql comment; ignored\nSELECT 1;", []string{"SELECT 1"}},
+		{"block comments", "SELECT /* a; b */ 1; /* only a comment; */", []string{"SELECT  1"}},
+		{"dollar-quoted body",
+			"CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.x := 1;\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;\nSELECT 1;",
+			[]string{"CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.x := 1;\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql", "SELECT 1"}},
+		{"tagged dollar quote", "DO $body$ BEGIN PERFORM 1; PERFORM '$$'; END $body$; SELECT 2",
+			[]string{"DO $body$ BEGIN PERFORM 1; PERFORM '$$'; END $body$", "SELECT 2"}},
+		{"placeholder is not a dollar quote", "SELECT $1; SELECT $2", []string{"SELECT $1", "SELECT $2"}},
+		{"empty statements", ";;\n ; SELECT 1;;", []string{"SELECT 1"}},
+		{"unterminated string", "SELECT 'abc; def", []string{"SELECT 'abc; def"}},
+		{"only comments", "-- nothing\n/* at all */\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements(%q)\n got %q\nwant %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEmbeddedMigrationsSplit checks every shipped script splits into real statements: comments
+// gone, nothing empty, and the PostgreSQL trigger function kept in one piece.
+func TestEmbeddedMigrationsSplit(t *testing.T) {
+	for _, name := range []string{"mysql", "postgres", "sqlite"} {
+		sub, err := fs.Sub(files, path.Join("sql", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		migrations, err := Load(sub)
+		if err != nil || len(migrations) == 0 {
+			t.Fatalf("%s: Load = %d migrations, %v", name, len(migrations), err)
+		}
+		for _, m := range migrations {
+			for direction, script := range map[string]string{"up": m.Up, "down": m.Down} {
+				statements := SplitStatements(script)
+				if len(statements) == 0 {
+					t.Errorf("%s %04d %s: no statements", name, m.Version, direction)
+				}
+				for _, stmt := range statements {
+					if strings.Contains(stmt, "--") || strings.HasSuffix(stmt, ";") {
+						t.Errorf("%s %04d %s: statement not cleanly split: %q", name, m.Version, direction, stmt)
+					}
+				}
+				if name == "postgres" && direction == "up" && m.Version == 1 {
+					found := false
+					for _, stmt := range statements {
+						if strings.HasPrefix(stmt, "CREATE OR REPLACE FUNCTION set_updated_at()") {
+							found = strings.HasSuffix(stmt, "$$ LANGUAGE plpgsql")
+						}
+					}
+					if !found {
+						t.Errorf("postgres: set_updated_at() was not kept as one statement")
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	up, down := &fstest.MapFile{Data: []byte("SELECT 1;")}, &fstest.MapFile{Data: []byte("SELECT 2;")}
+	migrations, err := Load(fstest.MapFS{
+		"0002_second.up.sql":   up,
+		"0002_second.down.sql": down,
+		"0001_first.up.sql":    up,
+		"0001_first.down.sql":  down,
+		"README.md":            {Data: []byte("ignored")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 1 || migrations[1].Name != "second" {
+		t.Errorf("Load = %+v, want versions 1 and 2 in order", migrations)
+	}
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"missing down":       {"0001_first.up.sql": up},
+		"bad direction":      {"0001_first.sql": up},
+		"no version":         {"first.up.sql": up, "first.down.sql": down},
+		"version used twice": {"0001_a.up.sql": up, "0001_a.down.sql": down, "0001_b.up.sql": up, "0001_b.down.sql": down},
+	} {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s: Load succeeded, want error", name)
+		}
+	}
+}
+
+// TestUpRefusesUnmanagedSchema runs the SQLite migrations on a database that already has one of
+// the baseline tables but no schema_migrations rows: Up must fail without applying anything rather
+// than skip the table and record the baseline as applied.
+func TestUpRefusesUnmanagedSchema(t *testing.T) {
+	ctx := context.Background()
+	d, err := dialect.Get(dialect.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := func(name string) *Migrator {
+		db, err := sql.Open(d.DriverName(), "file:"+filepath.Join(t.TempDir(), name)+"?_pragma=foreign_keys(1)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		m, err := New(db, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+
+	legacy := open("legacy.db")
+	if _, err := legacy.db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, email VARCHAR(100))"); err != nil {
+		t.Fatal(err)
+	}
+	applied, err := legacy.Up(ctx)
+	if !errors.Is(err, ErrUnmanagedSchema) || len(applied) != 0 {
+		t.Fatalf("Up on a database with a users table = %d applied, %v; want ErrUnmanagedSchema", len(applied), err)
+	}
+	if !strings.Contains(err.Error(), "users") {
+		t.Errorf("error %q does not name the existing table", err)
+	}
+	statuses, err := legacy.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt != nil {
+			t.Errorf("migration %04d recorded as applied", s.Version)
+		}
+	}
+
+	fresh := open("fresh.db")
+	if applied, err := fresh.Up(ctx); err != nil || len(applied) != len(fresh.migrations) {
+		t.Fatalf("Up on an empty database = %d applied, %v; want all %d", len(applied), err, len(fresh.migrations))
+	}
+	if applied, err := fresh.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("second Up = %d applied, %v; want none", len(applied), err)
+	}
+}
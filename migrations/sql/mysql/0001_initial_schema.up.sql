@@ -0,0 +1,376 @@
+-- go-bank-app/migrations/sql/mysql/0001_initial_schema.up.sql
+-- Skema awal database MySQL untuk go-bank-app, dijalankan lewat `go run . migrate up` (atau otomatis
+-- saat startup jika database.auto_migrate aktif). Migrasi ini membutuhkan database baru yang kosong:
+-- database lama yang dibuat dari schema.sql tidak bisa diadopsi. Migrator menolak berjalan jika
+-- salah satu tabel di bawah sudah ada sementara schema_migrations masih kosong, dan CREATE TABLE
+-- sengaja ditulis tanpa IF NOT EXISTS agar tabel yang tidak cocok gagal dengan jelas, bukan
+-- dilewati diam-diam. Buat database baru, jalankan migrasi, lalu pindahkan datanya.
+-- Semua kolom uang memakai DECIMAL(20,4) agar cukup untuk mata uang dengan 0-3 digit desimal.
+
+CREATE TABLE users (
+    id            INT AUTO_INCREMENT PRIMARY KEY,
+    name          VARCHAR(100) NOT NULL,
+    email         VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role          VARCHAR(20) NOT NULL DEFAULT 'customer',  -- customer, operator, admin
+    preferred_language VARCHAR(5) NOT NULL DEFAULT '',      -- id, en; kosong berarti ikut Accept-Language
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
+
+-- Nomor urut per kode cabang untuk nomor akun yang dibuat server (lihat package accountnumber).
+-- Nomor akun lama yang dibuat client tanpa check digit tetap diterima selama terdaftar di tabel accounts.
+CREATE TABLE account_number_sequences (
+    branch_prefix VARCHAR(10) NOT NULL PRIMARY KEY,
+    last_value    BIGINT NOT NULL
+) ENGINE=InnoDB;
+
+CREATE TABLE accounts (
+    id             INT AUTO_INCREMENT PRIMARY KEY,
+    user_id        INT NOT NULL,
+    account_number VARCHAR(20) NOT NULL UNIQUE,
+    type           VARCHAR(20) NOT NULL DEFAULT 'checking', -- checking, savings
+    currency       CHAR(3) NOT NULL DEFAULT 'IDR',          -- ISO 4217
+    balance        DECIMAL(20,4) NOT NULL DEFAULT 0,
+    held_amount    DECIMAL(20,4) NOT NULL DEFAULT 0,        -- Total hold aktif; saldo tersedia = balance - held_amount + overdraft_limit
+    status         VARCHAR(20) NOT NULL DEFAULT 'active',  -- active, frozen, dormant, closed
+    interest_rate  DECIMAL(9,6) NOT NULL DEFAULT 0,         -- Bunga tahunan dalam persen; 0 = tidak berbunga
+    day_count      VARCHAR(10) NOT NULL DEFAULT 'ACT/365',  -- ACT/365, ACT/360, 30/360
+    overdraft_limit         DECIMAL(20,4) NOT NULL DEFAULT 0, -- Cerukan yang disetujui (hanya checking); saldo boleh turun sampai -overdraft_limit
+    overdraft_interest_rate DECIMAL(9,6) NOT NULL DEFAULT 0,  -- Bunga debit tahunan dalam persen atas saldo negatif
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_accounts_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB;
+
+-- Buku besar (double-entry). Setiap entri jurnal terdiri dari beberapa posting yang jumlahnya
+-- harus nol per mata uang. Posting positif = debit, negatif = kredit.
+CREATE TABLE ledger_accounts (
+    id         INT AUTO_INCREMENT PRIMARY KEY,
+    code       VARCHAR(50) NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    type       VARCHAR(20) NOT NULL,          -- asset, liability, equity, income, expense
+    account_id INT NULL UNIQUE,               -- diisi untuk akun nasabah, NULL untuk akun sistem
+    currency   CHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_ledger_accounts_code_currency (code, currency),
+    CONSTRAINT fk_ledger_accounts_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+) ENGINE=InnoDB;
+
+CREATE TABLE journal_entries (
+    id          INT AUTO_INCREMENT PRIMARY KEY,
+    reference   VARCHAR(100) NOT NULL DEFAULT '',
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
+
+CREATE TABLE postings (
+    id                INT AUTO_INCREMENT PRIMARY KEY,
+    journal_entry_id  INT NOT NULL,
+    ledger_account_id INT NOT NULL,
+    amount            DECIMAL(20,4) NOT NULL,
+    currency          CHAR(3) NOT NULL,
+    KEY idx_postings_ledger_account (ledger_account_id),
+    CONSTRAINT fk_postings_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id),
+    CONSTRAINT fk_postings_ledger_account FOREIGN KEY (ledger_account_id) REFERENCES ledger_accounts (id)
+) ENGINE=InnoDB;
+
+INSERT IGNORE INTO ledger_accounts (code, name, type, currency) VALUES
+    ('CASH_VAULT',  'Cash vault',  'asset',     'IDR'),
+    ('FEES_INCOME', 'Fees income', 'income',    'IDR'),
+    ('SUSPENSE',    'Suspense',    'liability', 'IDR');
+
+CREATE TABLE transactions (
+    id               INT AUTO_INCREMENT PRIMARY KEY,
+    account_id       INT NOT NULL,
+    transaction_type VARCHAR(30) NOT NULL,   -- deposit, withdraw, transfer_out, transfer_in, capture, reversal_in, reversal_out, fee, interest
+    amount           DECIMAL(20,4) NOT NULL,
+    currency         CHAR(3) NOT NULL DEFAULT 'IDR',
+    description      VARCHAR(255) NOT NULL DEFAULT '',
+    transaction_date TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    journal_entry_id INT NULL,
+    -- Pembalikan: baris reversal_in/reversal_out menunjuk transaksi aslinya (paling banyak satu
+    -- pembalikan per transaksi), transaksi asli menunjuk balik ke pembaliknya
+    reversal_of_transaction_id INT NULL,
+    reversed_by_transaction_id INT NULL,
+    UNIQUE KEY uq_transactions_reversal_of (reversal_of_transaction_id),
+    -- Riwayat per akun dipaginasi dengan cursor (transaction_date, id) DESC
+    KEY idx_transactions_account_date (account_id, transaction_date, id),
+    KEY idx_transactions_account_type (account_id, transaction_type, transaction_date, id),
+    CONSTRAINT fk_transactions_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_transactions_journal_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id),
+    CONSTRAINT fk_transactions_reversal_of FOREIGN KEY (reversal_of_transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_transactions_reversed_by FOREIGN KEY (reversed_by_transaction_id) REFERENCES transactions (id)
+) ENGINE=InnoDB;
+
+-- Idempotency-Key untuk endpoint yang memindahkan uang (deposit, withdraw, transfer)
+CREATE TABLE idempotency_keys (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    user_id         INT NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    method          VARCHAR(10) NOT NULL,
+    path            VARCHAR(255) NOT NULL,
+    request_hash    CHAR(64) NOT NULL,
+    status          VARCHAR(20) NOT NULL,     -- in_progress, completed
+    response_code   INT NULL,
+    response_body   MEDIUMBLOB NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_idempotency_keys_user_key (user_id, idempotency_key),
+    CONSTRAINT fk_idempotency_keys_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB;
+
+-- Dana yang ditahan (hold): mengurangi saldo tersedia tanpa posting di buku besar.
+-- accounts.held_amount menyimpan SUM(amount) hold yang masih active;
+-- saldo tersedia = accounts.balance - accounts.held_amount + accounts.overdraft_limit.
+CREATE TABLE holds (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    account_id      INT NOT NULL,
+    type            VARCHAR(20) NOT NULL,                  -- authorization, legal, transfer
+    amount          DECIMAL(20,4) NOT NULL,
+    captured_amount DECIMAL(20,4) NULL,                    -- Terisi saat capture; sisanya dilepas
+    reason          VARCHAR(255) NOT NULL DEFAULT '',
+    reference       VARCHAR(64) NOT NULL DEFAULT '',
+    status          VARCHAR(20) NOT NULL DEFAULT 'active', -- active, captured, released, expired
+    expires_at      DATETIME NULL,
+    transaction_id  INT NULL,
+    created_by      INT NOT NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_holds_account_status (account_id, status),
+    KEY idx_holds_expiry (status, expires_at),
+    CONSTRAINT fk_holds_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_holds_transaction FOREIGN KEY (transaction_id) REFERENCES transactions (id)
+) ENGINE=InnoDB;
+
+-- Override batas debit per akun. Kolom NULL berarti memakai default jenis akun dari konfigurasi
+-- (LIMITS_<JENIS>_*). Nominal dalam mata uang akun.
+CREATE TABLE account_limits (
+    account_id      INT PRIMARY KEY,
+    per_transaction DECIMAL(20,4) NULL,
+    daily_debit     DECIMAL(20,4) NULL,
+    monthly_debit   DECIMAL(20,4) NULL,
+    daily_transfers INT NULL,
+    updated_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_account_limits_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+) ENGINE=InnoDB;
+
+-- Approver (checker) per akun: transfer di atas TRANSFER_APPROVAL_THRESHOLDS dari akun yang punya
+-- approver harus disetujui salah satu dari mereka.
+CREATE TABLE account_approvers (
+    account_id INT NOT NULL,
+    user_id    INT NOT NULL,
+    added_by   INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, user_id),
+    KEY idx_account_approvers_user (user_id),
+    CONSTRAINT fk_account_approvers_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_account_approvers_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB;
+
+-- Transfer antar akun; menghubungkan kedua transaksi (transfer_out dan transfer_in).
+-- amount dalam mata uang pengirim, destination_amount dalam mata uang penerima. Kolom fx_* hanya
+-- terisi untuk transfer lintas mata uang; fx_spread (mata uang penerima) dibukukan ke FEES_INCOME.
+-- fee (mata uang pengirim) adalah biaya transfer menurut fee schedule, dicatat sebagai transaksi fee.
+-- Transfer yang memerlukan persetujuan (requested_by terisi) dimulai sebagai pending_approval dengan
+-- dana ditahan di hold_id, tanpa transaksi dan entri jurnal sampai disetujui.
+CREATE TABLE transfers (
+    id                      INT AUTO_INCREMENT PRIMARY KEY,
+    from_account_id         INT NOT NULL,
+    to_account_id           INT NOT NULL,
+    amount                  DECIMAL(20,4) NOT NULL,
+    destination_amount      DECIMAL(20,4) NOT NULL,
+    fee                     DECIMAL(20,4) NOT NULL DEFAULT 0,
+    fx_mid_rate             DECIMAL(30,10) NULL,
+    fx_applied_rate         DECIMAL(30,10) NULL,
+    fx_rate_source          VARCHAR(100) NULL,
+    fx_rate_as_of           TIMESTAMP NULL,
+    fx_spread               DECIMAL(20,4) NULL,
+    description             VARCHAR(255) NOT NULL DEFAULT '',
+    status                  VARCHAR(20) NOT NULL,     -- completed, pending_approval, rejected, expired, reversed
+    outbound_transaction_id INT NULL,
+    inbound_transaction_id  INT NULL,
+    journal_entry_id        INT NULL,
+    requested_by            INT NULL,
+    approval_expires_at     DATETIME NULL,
+    hold_id                 INT NULL,
+    decided_by              INT NULL,
+    decided_at              DATETIME NULL,
+    decision_reason         VARCHAR(255) NULL,
+    created_at              TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_transfers_from_account (from_account_id, created_at),
+    KEY idx_transfers_to_account (to_account_id, created_at),
+    KEY idx_transfers_pending (status, approval_expires_at),
+    CONSTRAINT fk_transfers_from_account FOREIGN KEY (from_account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_transfers_to_account FOREIGN KEY (to_account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_transfers_outbound_tx FOREIGN KEY (outbound_transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_transfers_inbound_tx FOREIGN KEY (inbound_transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_transfers_journal_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id),
+    CONSTRAINT fk_transfers_hold FOREIGN KEY (hold_id) REFERENCES holds (id)
+) ENGINE=InnoDB;
+
+-- Refresh token (hanya hash-nya yang disimpan). Token hasil rotasi dari satu login
+-- berbagi family_id; memakai ulang token yang sudah dirotasi mencabut seluruh keluarga.
+CREATE TABLE refresh_tokens (
+    id             INT AUTO_INCREMENT PRIMARY KEY,
+    user_id        INT NOT NULL,
+    token_hash     CHAR(64) NOT NULL UNIQUE,
+    family_id      VARCHAR(32) NOT NULL,
+    expires_at     TIMESTAMP NOT NULL,
+    revoked_at     TIMESTAMP NULL,
+    replaced_by_id INT NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_refresh_tokens_family (family_id),
+    CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB;
+
+-- Daftar access token (jti) yang dicabut sebelum kadaluarsa, diperiksa oleh AuthMiddleware
+CREATE TABLE revoked_tokens (
+    jti        VARCHAR(64) PRIMARY KEY,
+    user_id    INT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_revoked_tokens_expires (expires_at)
+) ENGINE=InnoDB;
+
+-- Audit log untuk setiap akses yang memakai hak istimewa (admin/operator).
+-- Admin pertama diangkat manual: UPDATE users SET role = 'admin' WHERE email = '...';
+CREATE TABLE audit_logs (
+    id            INT AUTO_INCREMENT PRIMARY KEY,
+    actor_user_id INT NOT NULL,
+    actor_role    VARCHAR(20) NOT NULL,
+    action        VARCHAR(100) NOT NULL,
+    method        VARCHAR(10) NOT NULL,
+    path          VARCHAR(255) NOT NULL,
+    status_code   INT NOT NULL,
+    client_ip     VARCHAR(45) NOT NULL DEFAULT '',
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_audit_logs_actor (actor_user_id, created_at)
+) ENGINE=InnoDB;
+
+-- Riwayat perubahan status akun (freeze, unfreeze, dormant, close) beserta alasan dan pelakunya.
+CREATE TABLE account_status_history (
+    id            INT AUTO_INCREMENT PRIMARY KEY,
+    account_id    INT NOT NULL,
+    from_status   VARCHAR(20) NOT NULL,
+    to_status     VARCHAR(20) NOT NULL,
+    reason        VARCHAR(255) NOT NULL,
+    actor_user_id INT NOT NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_account_status_history_account (account_id, created_at),
+    CONSTRAINT fk_account_status_history_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+) ENGINE=InnoDB;
+
+-- Kurs valas untuk fx.RateProvider berbasis database (dipakai jika FX_RATES_FILE tidak diisi).
+-- Cukup simpan satu arah per pasangan; arah sebaliknya dihitung otomatis. Baris terbaru menang.
+CREATE TABLE fx_rates (
+    id             INT AUTO_INCREMENT PRIMARY KEY,
+    base_currency  CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate           DECIMAL(30,10) NOT NULL,   -- 1 base_currency = rate quote_currency
+    source         VARCHAR(100) NOT NULL,
+    as_of          TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_fx_rates_pair (base_currency, quote_currency, as_of)
+) ENGINE=InnoDB;
+
+-- Standing order: transfer terjadwal (sekali) atau berulang dari satu akun ke nomor akun tujuan.
+-- next_run_at adalah waktu eksekusi berikutnya (bisa lebih lambat dari scheduled_for saat retry).
+CREATE TABLE standing_orders (
+    id                        INT AUTO_INCREMENT PRIMARY KEY,
+    account_id                INT NOT NULL,
+    to_account_number         VARCHAR(20) NOT NULL,
+    amount                    DECIMAL(20,4) NOT NULL,
+    description               VARCHAR(255) NOT NULL DEFAULT '',
+    frequency                 VARCHAR(20) NOT NULL,          -- once, daily, weekly, monthly, end_of_month
+    day_of_month              TINYINT NULL,                  -- Hanya untuk monthly; dipotong ke akhir bulan jika perlu
+    start_date                DATE NOT NULL,
+    end_date                  DATE NULL,
+    max_runs                  INT NULL,
+    runs_completed            INT NOT NULL DEFAULT 0,
+    insufficient_funds_policy VARCHAR(20) NOT NULL DEFAULT 'skip', -- skip, retry, notify
+    max_retries               INT NOT NULL DEFAULT 0,
+    retry_count               INT NOT NULL DEFAULT 0,
+    scheduled_for             DATETIME NULL,
+    next_run_at               DATETIME NULL,
+    status                    VARCHAR(20) NOT NULL DEFAULT 'active', -- active, paused, completed, cancelled, suspended
+    created_by                INT NOT NULL,
+    created_at                TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_standing_orders_due (status, next_run_at),
+    KEY idx_standing_orders_account (account_id),
+    CONSTRAINT fk_standing_orders_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+) ENGINE=InnoDB;
+
+-- Riwayat eksekusi standing order. Kunci unik mencegah satu jadwal (dan percobaannya) tercatat dua kali.
+CREATE TABLE standing_order_executions (
+    id                INT AUTO_INCREMENT PRIMARY KEY,
+    standing_order_id INT NOT NULL,
+    scheduled_for     DATETIME NOT NULL,
+    attempt           INT NOT NULL,
+    status            VARCHAR(20) NOT NULL,   -- succeeded, pending_approval, skipped, retrying, failed
+    transfer_id       INT NULL,
+    error             VARCHAR(255) NOT NULL DEFAULT '',
+    executed_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_standing_order_executions_run (standing_order_id, scheduled_for, attempt),
+    CONSTRAINT fk_standing_order_executions_order FOREIGN KEY (standing_order_id) REFERENCES standing_orders (id),
+    CONSTRAINT fk_standing_order_executions_transfer FOREIGN KEY (transfer_id) REFERENCES transfers (id)
+) ENGINE=InnoDB;
+
+-- Lease untuk job scheduler: hanya satu instance aplikasi yang menjalankan job yang sama sekaligus.
+CREATE TABLE scheduler_leases (
+    name       VARCHAR(100) PRIMARY KEY,
+    owner      VARCHAR(255) NOT NULL,
+    token      VARCHAR(64) NOT NULL,
+    expires_at DATETIME(6) NOT NULL
+) ENGINE=InnoDB;
+
+-- Bunga harian per akun, dihitung dari saldo akhir hari (jumlah transaksi sebelum tengah malam berikutnya).
+-- amount belum dibulatkan; baru dibulatkan saat bunga sebulan dikapitalisasi. Primary key membuat
+-- job bunga aman diulang untuk tanggal yang sama.
+CREATE TABLE interest_accruals (
+    account_id         INT NOT NULL,
+    accrual_date       DATE NOT NULL,
+    end_of_day_balance DECIMAL(20,4) NOT NULL,
+    interest_rate      DECIMAL(9,6) NOT NULL,      -- Rencana bunga yang berlaku saat akrual dicatat
+    day_count          VARCHAR(10) NOT NULL,
+    amount             DECIMAL(30,10) NOT NULL,
+    capitalization_id  INT NULL,
+    created_at         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, accrual_date),
+    KEY idx_interest_accruals_date (accrual_date, capitalization_id),
+    CONSTRAINT fk_interest_accruals_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+) ENGINE=InnoDB;
+
+-- Kapitalisasi bunga bulanan: jumlah akrual positif sebulan dikreditkan sebagai transaksi interest (dari
+-- INTEREST_EXPENSE) dan akrual negatif (bunga cerukan) didebit sebagai transaksi overdraft_interest (ke
+-- INTEREST_INCOME), bertanggal hari pertama bulan berikutnya. Paling banyak satu per akun per bulan.
+CREATE TABLE interest_capitalizations (
+    id               INT AUTO_INCREMENT PRIMARY KEY,
+    account_id       INT NOT NULL,
+    period_start     DATE NOT NULL,
+    period_end       DATE NOT NULL,
+    accrued          DECIMAL(30,10) NOT NULL,   -- Jumlah akrual sebelum dibulatkan
+    amount           DECIMAL(20,4) NOT NULL,    -- Yang dikreditkan; 0 berarti tidak ada transaksi
+    transaction_id   INT NULL,
+    debit_accrued    DECIMAL(30,10) NOT NULL DEFAULT 0, -- Jumlah bunga cerukan sebelum dibulatkan (positif)
+    debit_amount     DECIMAL(20,4) NOT NULL DEFAULT 0,  -- Yang didebit; 0 berarti tidak ada transaksi
+    debit_transaction_id INT NULL,
+    journal_entry_id INT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_interest_capitalizations_period (account_id, period_start),
+    CONSTRAINT fk_interest_capitalizations_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_interest_capitalizations_tx FOREIGN KEY (transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_interest_capitalizations_debit_tx FOREIGN KEY (debit_transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_interest_capitalizations_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id)
+) ENGINE=InnoDB;
+
+-- Tanggal yang sudah selesai diproses job bunga untuk semua akun. Job berikutnya melanjutkan dari
+-- tanggal terakhir di sini; tanggal yang terlewat sebelum itu diisi dengan cmd/interest-backfill.
+CREATE TABLE interest_runs (
+    run_date     DATE PRIMARY KEY,
+    accrued      INT NOT NULL,
+    skipped      INT NOT NULL,
+    capitalized  INT NOT NULL,
+    completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB;
@@ -0,0 +1,8 @@
+-- go-bank-app/migrations/sql/mysql/0002_idempotency_expiry.down.sql
+-- Menghapus masa berlaku Idempotency-Key. Kunci berstatus failed tetap ditolak sebagai konflik.
+
+DROP INDEX idx_idempotency_keys_expires_at ON idempotency_keys;
+
+ALTER TABLE idempotency_keys
+    DROP COLUMN locked_at,
+    DROP COLUMN expires_at;
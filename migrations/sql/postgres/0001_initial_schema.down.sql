@@ -0,0 +1,28 @@
+-- go-bank-app/migrations/sql/postgres/0001_initial_schema.down.sql
+-- Menghapus seluruh skema awal, termasuk datanya. Tabel dihapus dalam urutan terbalik agar foreign
+-- key tidak menghalangi.
+
+DROP TABLE IF EXISTS interest_runs;
+DROP TABLE IF EXISTS interest_capitalizations;
+DROP TABLE IF EXISTS interest_accruals;
+DROP TABLE IF EXISTS scheduler_leases;
+DROP TABLE IF EXISTS standing_order_executions;
+DROP TABLE IF EXISTS standing_orders;
+DROP TABLE IF EXISTS fx_rates;
+DROP TABLE IF EXISTS account_status_history;
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS transfers;
+DROP TABLE IF EXISTS account_approvers;
+DROP TABLE IF EXISTS account_limits;
+DROP TABLE IF EXISTS holds;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS postings;
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS ledger_accounts;
+DROP TABLE IF EXISTS accounts;
+DROP TABLE IF EXISTS account_number_sequences;
+DROP TABLE IF EXISTS users;
+DROP FUNCTION IF EXISTS set_updated_at();
@@ -0,0 +1,391 @@
+-- go-bank-app/migrations/sql/postgres/0001_initial_schema.up.sql
+-- Skema awal untuk PostgreSQL; isinya sama dengan versi MySQL (sql/mysql), hanya sintaksnya yang
+-- berbeda: SERIAL untuk AUTO_INCREMENT, TIMESTAMPTZ untuk TIMESTAMP/DATETIME, index dibuat terpisah,
+-- dan updated_at diperbarui oleh trigger karena PostgreSQL tidak punya ON UPDATE CURRENT_TIMESTAMP.
+-- Seperti versi MySQL, migrasi ini harus dijalankan pada database baru yang kosong.
+-- Semua kolom uang memakai DECIMAL(20,4) agar cukup untuk mata uang dengan 0-3 digit desimal.
+
+CREATE TABLE users (
+    id            SERIAL PRIMARY KEY,
+    name          VARCHAR(100) NOT NULL,
+    email         VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role          VARCHAR(20) NOT NULL DEFAULT 'customer',  -- customer, operator, admin
+    preferred_language VARCHAR(5) NOT NULL DEFAULT '',      -- id, en; kosong berarti ikut Accept-Language
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Nomor urut per kode cabang untuk nomor akun yang dibuat server (lihat package accountnumber).
+-- Nomor akun lama yang dibuat client tanpa check digit tetap diterima selama terdaftar di tabel accounts.
+CREATE TABLE account_number_sequences (
+    branch_prefix VARCHAR(10) NOT NULL PRIMARY KEY,
+    last_value    BIGINT NOT NULL
+);
+
+CREATE TABLE accounts (
+    id             SERIAL PRIMARY KEY,
+    user_id        INT NOT NULL,
+    account_number VARCHAR(20) NOT NULL UNIQUE,
+    type           VARCHAR(20) NOT NULL DEFAULT 'checking', -- checking, savings
+    currency       CHAR(3) NOT NULL DEFAULT 'IDR',          -- ISO 4217
+    balance        DECIMAL(20,4) NOT NULL DEFAULT 0,
+    held_amount    DECIMAL(20,4) NOT NULL DEFAULT 0,        -- Total hold aktif; saldo tersedia = balance - held_amount + overdraft_limit
+    status         VARCHAR(20) NOT NULL DEFAULT 'active',  -- active, frozen, dormant, closed
+    interest_rate  DECIMAL(9,6) NOT NULL DEFAULT 0,         -- Bunga tahunan dalam persen; 0 = tidak berbunga
+    day_count      VARCHAR(10) NOT NULL DEFAULT 'ACT/365',  -- ACT/365, ACT/360, 30/360
+    overdraft_limit         DECIMAL(20,4) NOT NULL DEFAULT 0, -- Cerukan yang disetujui (hanya checking); saldo boleh turun sampai -overdraft_limit
+    overdraft_interest_rate DECIMAL(9,6) NOT NULL DEFAULT 0,  -- Bunga debit tahunan dalam persen atas saldo negatif
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_accounts_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+-- Buku besar (double-entry). Setiap entri jurnal terdiri dari beberapa posting yang jumlahnya
+-- harus nol per mata uang. Posting positif = debit, negatif = kredit.
+CREATE TABLE ledger_accounts (
+    id         SERIAL PRIMARY KEY,
+    code       VARCHAR(50) NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    type       VARCHAR(20) NOT NULL,          -- asset, liability, equity, income, expense
+    account_id INT NULL UNIQUE,               -- diisi untuk akun nasabah, NULL untuk akun sistem
+    currency   CHAR(3) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_ledger_accounts_code_currency UNIQUE (code, currency),
+    CONSTRAINT fk_ledger_accounts_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+
+CREATE TABLE journal_entries (
+    id          SERIAL PRIMARY KEY,
+    reference   VARCHAR(100) NOT NULL DEFAULT '',
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE postings (
+    id                SERIAL PRIMARY KEY,
+    journal_entry_id  INT NOT NULL,
+    ledger_account_id INT NOT NULL,
+    amount            DECIMAL(20,4) NOT NULL,
+    currency          CHAR(3) NOT NULL,
+    CONSTRAINT fk_postings_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id),
+    CONSTRAINT fk_postings_ledger_account FOREIGN KEY (ledger_account_id) REFERENCES ledger_accounts (id)
+);
+CREATE INDEX idx_postings_ledger_account ON postings (ledger_account_id);
+
+INSERT INTO ledger_accounts (code, name, type, currency) VALUES
+    ('CASH_VAULT',  'Cash vault',  'asset',     'IDR'),
+    ('FEES_INCOME', 'Fees income', 'income',    'IDR'),
+    ('SUSPENSE',    'Suspense',    'liability', 'IDR')
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE transactions (
+    id               SERIAL PRIMARY KEY,
+    account_id       INT NOT NULL,
+    transaction_type VARCHAR(30) NOT NULL,   -- deposit, withdraw, transfer_out, transfer_in, capture, reversal_in, reversal_out, fee, interest
+    amount           DECIMAL(20,4) NOT NULL,
+    currency         CHAR(3) NOT NULL DEFAULT 'IDR',
+    description      VARCHAR(255) NOT NULL DEFAULT '',
+    transaction_date TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    journal_entry_id INT NULL,
+    -- Pembalikan: baris reversal_in/reversal_out menunjuk transaksi aslinya (paling banyak satu
+    -- pembalikan per transaksi), transaksi asli menunjuk balik ke pembaliknya
+    reversal_of_transaction_id INT NULL,
+    reversed_by_transaction_id INT NULL,
+    CONSTRAINT uq_transactions_reversal_of UNIQUE (reversal_of_transaction_id),
+    CONSTRAINT fk_transactions_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_transactions_journal_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id),
+    CONSTRAINT fk_transactions_reversal_of FOREIGN KEY (reversal_of_transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_transactions_reversed_by FOREIGN KEY (reversed_by_transaction_id) REFERENCES transactions (id)
+);
+-- Riwayat per akun dipaginasi dengan cursor (transaction_date, id) DESC
+CREATE INDEX idx_transactions_account_date ON transactions (account_id, transaction_date, id);
+CREATE INDEX idx_transactions_account_type ON transactions (account_id, transaction_type, transaction_date, id);
+
+-- Idempotency-Key untuk endpoint yang memindahkan uang (deposit, withdraw, transfer)
+CREATE TABLE idempotency_keys (
+    id              SERIAL PRIMARY KEY,
+    user_id         INT NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    method          VARCHAR(10) NOT NULL,
+    path            VARCHAR(255) NOT NULL,
+    request_hash    CHAR(64) NOT NULL,
+    status          VARCHAR(20) NOT NULL,     -- in_progress, completed
+    response_code   INT NULL,
+    response_body   BYTEA NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_idempotency_keys_user_key UNIQUE (user_id, idempotency_key),
+    CONSTRAINT fk_idempotency_keys_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+-- Dana yang ditahan (hold): mengurangi saldo tersedia tanpa posting di buku besar.
+-- accounts.held_amount menyimpan SUM(amount) hold yang masih active;
+-- saldo tersedia = accounts.balance - accounts.held_amount + accounts.overdraft_limit.
+CREATE TABLE holds (
+    id              SERIAL PRIMARY KEY,
+    account_id      INT NOT NULL,
+    type            VARCHAR(20) NOT NULL,                  -- authorization, legal, transfer
+    amount          DECIMAL(20,4) NOT NULL,
+    captured_amount DECIMAL(20,4) NULL,                    -- Terisi saat capture; sisanya dilepas
+    reason          VARCHAR(255) NOT NULL DEFAULT '',
+    reference       VARCHAR(64) NOT NULL DEFAULT '',
+    status          VARCHAR(20) NOT NULL DEFAULT 'active', -- active, captured, released, expired
+    expires_at      TIMESTAMPTZ NULL,
+    transaction_id  INT NULL,
+    created_by      INT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_holds_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_holds_transaction FOREIGN KEY (transaction_id) REFERENCES transactions (id)
+);
+CREATE INDEX idx_holds_account_status ON holds (account_id, status);
+CREATE INDEX idx_holds_expiry ON holds (status, expires_at);
+
+-- Override batas debit per akun. Kolom NULL berarti memakai default jenis akun dari konfigurasi
+-- (LIMITS_<JENIS>_*). Nominal dalam mata uang akun.
+CREATE TABLE account_limits (
+    account_id      INT PRIMARY KEY,
+    per_transaction DECIMAL(20,4) NULL,
+    daily_debit     DECIMAL(20,4) NULL,
+    monthly_debit   DECIMAL(20,4) NULL,
+    daily_transfers INT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_account_limits_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+
+-- Approver (checker) per akun: transfer di atas TRANSFER_APPROVAL_THRESHOLDS dari akun yang punya
+-- approver harus disetujui salah satu dari mereka.
+CREATE TABLE account_approvers (
+    account_id INT NOT NULL,
+    user_id    INT NOT NULL,
+    added_by   INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, user_id),
+    CONSTRAINT fk_account_approvers_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_account_approvers_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_account_approvers_user ON account_approvers (user_id);
+
+-- Transfer antar akun; menghubungkan kedua transaksi (transfer_out dan transfer_in).
+-- amount dalam mata uang pengirim, destination_amount dalam mata uang penerima. Kolom fx_* hanya
+-- terisi untuk transfer lintas mata uang; fx_spread (mata uang penerima) dibukukan ke FEES_INCOME.
+-- fee (mata uang pengirim) adalah biaya transfer menurut fee schedule, dicatat sebagai transaksi fee.
+-- Transfer yang memerlukan persetujuan (requested_by terisi) dimulai sebagai pending_approval dengan
+-- dana ditahan di hold_id, tanpa transaksi dan entri jurnal sampai disetujui.
+CREATE TABLE transfers (
+    id                      SERIAL PRIMARY KEY,
+    from_account_id         INT NOT NULL,
+    to_account_id           INT NOT NULL,
+    amount                  DECIMAL(20,4) NOT NULL,
+    destination_amount      DECIMAL(20,4) NOT NULL,
+    fee                     DECIMAL(20,4) NOT NULL DEFAULT 0,
+    fx_mid_rate             DECIMAL(30,10) NULL,
+    fx_applied_rate         DECIMAL(30,10) NULL,
+    fx_rate_source          VARCHAR(100) NULL,
+    fx_rate_as_of           TIMESTAMPTZ NULL,
+    fx_spread               DECIMAL(20,4) NULL,
+    description             VARCHAR(255) NOT NULL DEFAULT '',
+    status                  VARCHAR(20) NOT NULL,     -- completed, pending_approval, rejected, expired, reversed
+    outbound_transaction_id INT NULL,
+    inbound_transaction_id  INT NULL,
+    journal_entry_id        INT NULL,
+    requested_by            INT NULL,
+    approval_expires_at     TIMESTAMPTZ NULL,
+    hold_id                 INT NULL,
+    decided_by              INT NULL,
+    decided_at              TIMESTAMPTZ NULL,
+    decision_reason         VARCHAR(255) NULL,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transfers_from_account FOREIGN KEY (from_account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_transfers_to_account FOREIGN KEY (to_account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_transfers_outbound_tx FOREIGN KEY (outbound_transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_transfers_inbound_tx FOREIGN KEY (inbound_transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_transfers_journal_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id),
+    CONSTRAINT fk_transfers_hold FOREIGN KEY (hold_id) REFERENCES holds (id)
+);
+CREATE INDEX idx_transfers_from_account ON transfers (from_account_id, created_at);
+CREATE INDEX idx_transfers_to_account ON transfers (to_account_id, created_at);
+CREATE INDEX idx_transfers_pending ON transfers (status, approval_expires_at);
+
+-- Refresh token (hanya hash-nya yang disimpan). Token hasil rotasi dari satu login
+-- berbagi family_id; memakai ulang token yang sudah dirotasi mencabut seluruh keluarga.
+CREATE TABLE refresh_tokens (
+    id             SERIAL PRIMARY KEY,
+    user_id        INT NOT NULL,
+    token_hash     CHAR(64) NOT NULL UNIQUE,
+    family_id      VARCHAR(32) NOT NULL,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    revoked_at     TIMESTAMPTZ NULL,
+    replaced_by_id INT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens (family_id);
+
+-- Daftar access token (jti) yang dicabut sebelum kadaluarsa, diperiksa oleh AuthMiddleware
+CREATE TABLE revoked_tokens (
+    jti        VARCHAR(64) PRIMARY KEY,
+    user_id    INT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_revoked_tokens_expires ON revoked_tokens (expires_at);
+
+-- Audit log untuk setiap akses yang memakai hak istimewa (admin/operator).
+-- Admin pertama diangkat manual: UPDATE users SET role = 'admin' WHERE email = '...';
+CREATE TABLE audit_logs (
+    id            SERIAL PRIMARY KEY,
+    actor_user_id INT NOT NULL,
+    actor_role    VARCHAR(20) NOT NULL,
+    action        VARCHAR(100) NOT NULL,
+    method        VARCHAR(10) NOT NULL,
+    path          VARCHAR(255) NOT NULL,
+    status_code   INT NOT NULL,
+    client_ip     VARCHAR(45) NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_audit_logs_actor ON audit_logs (actor_user_id, created_at);
+
+-- Riwayat perubahan status akun (freeze, unfreeze, dormant, close) beserta alasan dan pelakunya.
+CREATE TABLE account_status_history (
+    id            SERIAL PRIMARY KEY,
+    account_id    INT NOT NULL,
+    from_status   VARCHAR(20) NOT NULL,
+    to_status     VARCHAR(20) NOT NULL,
+    reason        VARCHAR(255) NOT NULL,
+    actor_user_id INT NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_account_status_history_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_account_status_history_account ON account_status_history (account_id, created_at);
+
+-- Kurs valas untuk fx.RateProvider berbasis database (dipakai jika FX_RATES_FILE tidak diisi).
+-- Cukup simpan satu arah per pasangan; arah sebaliknya dihitung otomatis. Baris terbaru menang.
+CREATE TABLE fx_rates (
+    id             SERIAL PRIMARY KEY,
+    base_currency  CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate           DECIMAL(30,10) NOT NULL,   -- 1 base_currency = rate quote_currency
+    source         VARCHAR(100) NOT NULL,
+    as_of          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_fx_rates_pair ON fx_rates (base_currency, quote_currency, as_of);
+
+-- Standing order: transfer terjadwal (sekali) atau berulang dari satu akun ke nomor akun tujuan.
+-- next_run_at adalah waktu eksekusi berikutnya (bisa lebih lambat dari scheduled_for saat retry).
+CREATE TABLE standing_orders (
+    id                        SERIAL PRIMARY KEY,
+    account_id                INT NOT NULL,
+    to_account_number         VARCHAR(20) NOT NULL,
+    amount                    DECIMAL(20,4) NOT NULL,
+    description               VARCHAR(255) NOT NULL DEFAULT '',
+    frequency                 VARCHAR(20) NOT NULL,          -- once, daily, weekly, monthly, end_of_month
+    day_of_month              SMALLINT NULL,                  -- Hanya untuk monthly; dipotong ke akhir bulan jika perlu
+    start_date                DATE NOT NULL,
+    end_date                  DATE NULL,
+    max_runs                  INT NULL,
+    runs_completed            INT NOT NULL DEFAULT 0,
+    insufficient_funds_policy VARCHAR(20) NOT NULL DEFAULT 'skip', -- skip, retry, notify
+    max_retries               INT NOT NULL DEFAULT 0,
+    retry_count               INT NOT NULL DEFAULT 0,
+    scheduled_for             TIMESTAMPTZ NULL,
+    next_run_at               TIMESTAMPTZ NULL,
+    status                    VARCHAR(20) NOT NULL DEFAULT 'active', -- active, paused, completed, cancelled, suspended
+    created_by                INT NOT NULL,
+    created_at                TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_standing_orders_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_standing_orders_due ON standing_orders (status, next_run_at);
+CREATE INDEX idx_standing_orders_account ON standing_orders (account_id);
+
+-- Riwayat eksekusi standing order. Kunci unik mencegah satu jadwal (dan percobaannya) tercatat dua kali.
+CREATE TABLE standing_order_executions (
+    id                SERIAL PRIMARY KEY,
+    standing_order_id INT NOT NULL,
+    scheduled_for     TIMESTAMPTZ NOT NULL,
+    attempt           INT NOT NULL,
+    status            VARCHAR(20) NOT NULL,   -- succeeded, pending_approval, skipped, retrying, failed
+    transfer_id       INT NULL,
+    error             VARCHAR(255) NOT NULL DEFAULT '',
+    executed_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_standing_order_executions_run UNIQUE (standing_order_id, scheduled_for, attempt),
+    CONSTRAINT fk_standing_order_executions_order FOREIGN KEY (standing_order_id) REFERENCES standing_orders (id),
+    CONSTRAINT fk_standing_order_executions_transfer FOREIGN KEY (transfer_id) REFERENCES transfers (id)
+);
+
+-- Lease untuk job scheduler: hanya satu instance aplikasi yang menjalankan job yang sama sekaligus.
+CREATE TABLE scheduler_leases (
+    name       VARCHAR(100) PRIMARY KEY,
+    owner      VARCHAR(255) NOT NULL,
+    token      VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- Bunga harian per akun, dihitung dari saldo akhir hari (jumlah transaksi sebelum tengah malam berikutnya).
+-- amount belum dibulatkan; baru dibulatkan saat bunga sebulan dikapitalisasi. Primary key membuat
+-- job bunga aman diulang untuk tanggal yang sama.
+CREATE TABLE interest_accruals (
+    account_id         INT NOT NULL,
+    accrual_date       DATE NOT NULL,
+    end_of_day_balance DECIMAL(20,4) NOT NULL,
+    interest_rate      DECIMAL(9,6) NOT NULL,      -- Rencana bunga yang berlaku saat akrual dicatat
+    day_count          VARCHAR(10) NOT NULL,
+    amount             DECIMAL(30,10) NOT NULL,
+    capitalization_id  INT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, accrual_date),
+    CONSTRAINT fk_interest_accruals_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_interest_accruals_date ON interest_accruals (accrual_date, capitalization_id);
+
+-- Kapitalisasi bunga bulanan: jumlah akrual positif sebulan dikreditkan sebagai transaksi interest (dari
+-- INTEREST_EXPENSE) dan akrual negatif (bunga cerukan) didebit sebagai transaksi overdraft_interest (ke
+-- INTEREST_INCOME), bertanggal hari pertama bulan berikutnya. Paling banyak satu per akun per bulan.
+CREATE TABLE interest_capitalizations (
+    id               SERIAL PRIMARY KEY,
+    account_id       INT NOT NULL,
+    period_start     DATE NOT NULL,
+    period_end       DATE NOT NULL,
+    accrued          DECIMAL(30,10) NOT NULL,   -- Jumlah akrual sebelum dibulatkan
+    amount           DECIMAL(20,4) NOT NULL,    -- Yang dikreditkan; 0 berarti tidak ada transaksi
+    transaction_id   INT NULL,
+    debit_accrued    DECIMAL(30,10) NOT NULL DEFAULT 0, -- Jumlah bunga cerukan sebelum dibulatkan (positif)
+    debit_amount     DECIMAL(20,4) NOT NULL DEFAULT 0,  -- Yang didebit; 0 berarti tidak ada transaksi
+    debit_transaction_id INT NULL,
+    journal_entry_id INT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_interest_capitalizations_period UNIQUE (account_id, period_start),
+    CONSTRAINT fk_interest_capitalizations_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_interest_capitalizations_tx FOREIGN KEY (transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_interest_capitalizations_debit_tx FOREIGN KEY (debit_transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_interest_capitalizations_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id)
+);
+
+-- Tanggal yang sudah selesai diproses job bunga untuk semua akun. Job berikutnya melanjutkan dari
+-- tanggal terakhir di sini; tanggal yang terlewat sebelum itu diisi dengan cmd/interest-backfill.
+CREATE TABLE interest_runs (
+    run_date     DATE PRIMARY KEY,
+    accrued      INT NOT NULL,
+    skipped      INT NOT NULL,
+    capitalized  INT NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Pengganti ON UPDATE CURRENT_TIMESTAMP milik MySQL
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_users_updated_at BEFORE UPDATE ON users FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER trg_accounts_updated_at BEFORE UPDATE ON accounts FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER trg_idempotency_keys_updated_at BEFORE UPDATE ON idempotency_keys FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER trg_holds_updated_at BEFORE UPDATE ON holds FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER trg_account_limits_updated_at BEFORE UPDATE ON account_limits FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER trg_transfers_updated_at BEFORE UPDATE ON transfers FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+CREATE TRIGGER trg_standing_orders_updated_at BEFORE UPDATE ON standing_orders FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,15 @@
+-- go-bank-app/migrations/sql/postgres/0002_idempotency_expiry.up.sql
+-- Masa berlaku Idempotency-Key. locked_at dicatat saat sebuah request memegang kunci: kunci
+-- in_progress yang locked_at-nya lebih tua dari idempotency.lock_timeout ditinggalkan request yang
+-- mati dan boleh diambil alih oleh retry. expires_at diisi saat hasilnya disimpan; scheduler
+-- menghapus kunci yang sudah lewat. Kunci yang sudah ada berlaku 24 jam sejak migrasi ini.
+-- Kolom status kini juga bisa berisi failed: request gagal setelah mungkin sudah mengubah data.
+
+ALTER TABLE idempotency_keys
+    ADD COLUMN locked_at  TIMESTAMPTZ NULL,
+    ADD COLUMN expires_at TIMESTAMPTZ NULL;
+
+UPDATE idempotency_keys SET locked_at = clock_timestamp() WHERE status = 'in_progress';
+UPDATE idempotency_keys SET expires_at = clock_timestamp() + INTERVAL '24 hours' WHERE status <> 'in_progress';
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
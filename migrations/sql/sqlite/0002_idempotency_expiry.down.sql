@@ -0,0 +1,7 @@
+-- go-bank-app/migrations/sql/sqlite/0002_idempotency_expiry.down.sql
+-- Menghapus masa berlaku Idempotency-Key. Kunci berstatus failed tetap ditolak sebagai konflik.
+
+DROP INDEX idx_idempotency_keys_expires_at;
+
+ALTER TABLE idempotency_keys DROP COLUMN locked_at;
+ALTER TABLE idempotency_keys DROP COLUMN expires_at;
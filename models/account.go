@@ -1,22 +1,146 @@
 // go-bank-app/models/account.go
 package models
 
-import "time"
+import (
+	"time"
+
+	"go-bank-app/interest"
+	"go-bank-app/money"
+)
+
+// AccountStatus adalah status siklus hidup akun.
+type AccountStatus string
+
+const (
+	AccountActive  AccountStatus = "active"
+	AccountFrozen  AccountStatus = "frozen"  // Dibekukan bank: dana masuk boleh, dana keluar ditolak
+	AccountDormant AccountStatus = "dormant" // Tidak aktif lama: dana masuk boleh, dana keluar ditolak sampai diaktifkan lagi
+	AccountClosed  AccountStatus = "closed"  // Ditutup permanen: semua transaksi ditolak
+)
+
+// accountStatusTransitions adalah mesin status akun: status asal -> status tujuan yang diizinkan.
+var accountStatusTransitions = map[AccountStatus][]AccountStatus{
+	AccountActive:  {AccountFrozen, AccountDormant, AccountClosed},
+	AccountFrozen:  {AccountActive, AccountClosed},
+	AccountDormant: {AccountActive, AccountFrozen, AccountClosed},
+	AccountClosed:  {},
+}
+
+// CanTransitionTo melaporkan apakah perubahan status dari s ke to diizinkan.
+func (s AccountStatus) CanTransitionTo(to AccountStatus) bool {
+	for _, allowed := range accountStatusTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsDebit melaporkan apakah dana boleh keluar dari akun dengan status ini.
+func (s AccountStatus) AllowsDebit() bool {
+	return s == AccountActive
+}
+
+// AllowsCredit melaporkan apakah dana boleh masuk ke akun dengan status ini.
+func (s AccountStatus) AllowsCredit() bool {
+	return s == AccountActive || s == AccountFrozen || s == AccountDormant
+}
+
+// AccountType adalah jenis produk akun; menentukan batas default (lihat config.AccountLimitDefaults)
+// dan rencana bunga awal (lihat config.DefaultInterestPlan).
+type AccountType string
+
+const (
+	AccountTypeChecking AccountType = "checking" // Rekening giro/transaksi harian
+	AccountTypeSavings  AccountType = "savings"  // Tabungan
+)
+
+// IsValid melaporkan apakah t adalah jenis akun yang dikenal.
+func (t AccountType) IsValid() bool {
+	return t == AccountTypeChecking || t == AccountTypeSavings
+}
 
 type Account struct {
-	ID            int       `json:"id"`
-	UserID        int       `json:"user_id"`
-	AccountNumber string    `json:"account_number"`
-	Balance       float64   `json:"balance"` // Gunakan float64 untuk kenyamanan, tapi ingat DECIMAL di DB
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID                    int               `json:"id"`
+	UserID                int               `json:"user_id"`
+	AccountNumber         string            `json:"account_number"`
+	Type                  AccountType       `json:"type"`
+	Currency              money.Currency    `json:"currency"`          // Kode ISO 4217, tidak berubah setelah akun dibuat
+	Balance               money.Money       `json:"balance"`           // Saldo buku besar; disimpan sebagai DECIMAL di DB, dihitung dalam minor unit
+	HeldAmount            money.Money       `json:"held_amount"`       // Total hold aktif
+	AvailableBalance      money.Money       `json:"available_balance"` // Balance - HeldAmount + OverdraftLimit: yang boleh ditarik atau ditransfer
+	Status                AccountStatus     `json:"status"`
+	InterestRate          string            `json:"interest_rate"`           // Bunga tahunan dalam persen, mis. "2.5"; "0" berarti akun tidak berbunga
+	DayCount              interest.DayCount `json:"day_count"`               // Konvensi hitungan hari untuk akrual bunga (juga bunga cerukan)
+	OverdraftLimit        money.Money       `json:"overdraft_limit"`         // Batas cerukan yang disetujui; saldo boleh turun sampai -OverdraftLimit
+	OverdraftUsed         money.Money       `json:"overdraft_used"`          // Cerukan yang sedang dipakai: -Balance jika negatif, selain itu nol
+	OverdraftInterestRate string            `json:"overdraft_interest_rate"` // Bunga debit tahunan dalam persen atas saldo negatif
+	CreatedAt             time.Time         `json:"created_at"`
+	UpdatedAt             time.Time         `json:"updated_at"`
 }
 
+// InterestPlan mengembalikan rencana bunga akun (InterestRate dan DayCount).
+func (a *Account) InterestPlan() (interest.Plan, error) {
+	return interest.ParsePlan(a.InterestRate, string(a.DayCount))
+}
+
+// OverdraftInterestPlan mengembalikan rencana bunga debit atas saldo negatif (OverdraftInterestRate
+// dengan konvensi hitungan hari akun).
+func (a *Account) OverdraftInterestPlan() (interest.Plan, error) {
+	return interest.ParsePlan(a.OverdraftInterestRate, string(a.DayCount))
+}
+
+// UpdateOverdraftRequest adalah body untuk PUT /accounts/:id/overdraft. Limit nol mencabut fasilitas
+// cerukan; saldo yang sudah negatif tetap ada, tetapi tidak boleh bertambah negatif.
+type UpdateOverdraftRequest struct {
+	Limit        money.Money `json:"limit"`
+	InterestRate string      `json:"interest_rate"` // Opsional, default config.AppCfg.Overdraft.InterestRate
+}
+
+// AccountStatusChange mencatat satu perubahan status akun beserta alasan dan pelakunya.
+type AccountStatusChange struct {
+	ID          int           `json:"id"`
+	AccountID   int           `json:"account_id"`
+	FromStatus  AccountStatus `json:"from_status"`
+	ToStatus    AccountStatus `json:"to_status"`
+	Reason      string        `json:"reason"`
+	ActorUserID int           `json:"actor_user_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// AccountApprover adalah user yang boleh menyetujui (checker) transfer besar dari sebuah akun.
+type AccountApprover struct {
+	AccountID int       `json:"account_id"`
+	UserID    int       `json:"user_id"`
+	AddedBy   int       `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddApproverRequest adalah body untuk POST /accounts/:id/approvers.
+type AddApproverRequest struct {
+	UserID int `json:"user_id" binding:"required"`
+}
+
+// CreateAccountRequest adalah body untuk POST /accounts. Nomor akun dibuat oleh server
+// (kode cabang + nomor urut + check digit), jadi tidak dikirim oleh client.
 type CreateAccountRequest struct {
-	UserID        int    `json:"user_id" binding:"required"`
-	AccountNumber string `json:"account_number" binding:"required,min=10,max=20"` // Contoh validasi panjang akun
+	UserID   int         `json:"user_id" binding:"required"`
+	Currency string      `json:"currency"`                                        // Opsional, default money.DefaultCurrency
+	Type     AccountType `json:"type" binding:"omitempty,oneof=checking savings"` // Opsional, default checking
 }
 
 type DepositWithdrawRequest struct {
-	Amount float64 `json:"amount" binding:"required,gt=0"` // Jumlah harus positif (greater than 0)
+	Amount money.Money `json:"amount" binding:"required,gt=0"` // Jumlah harus positif (greater than 0)
+}
+
+// AccountStatusRequest adalah body untuk freeze, unfreeze dan dormant.
+type AccountStatusRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// CloseAccountRequest adalah body untuk POST /accounts/:id/close. Jika saldo belum nol,
+// SweepToAccountNumber wajib diisi dan sisa saldo dipindahkan ke akun tersebut.
+type CloseAccountRequest struct {
+	Reason               string `json:"reason" binding:"required,max=255"`
+	SweepToAccountNumber string `json:"sweep_to_account_number" binding:"omitempty,account_number"`
 }
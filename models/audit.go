@@ -0,0 +1,17 @@
+// go-bank-app/models/audit.go
+package models
+
+import "time"
+
+// AuditLog mencatat setiap akses yang memakai hak istimewa (admin/operator).
+type AuditLog struct {
+	ID          int       `json:"id"`
+	ActorUserID int       `json:"actor_user_id"`
+	ActorRole   string    `json:"actor_role"`
+	Action      string    `json:"action"` // Izin yang dipakai, misalnya "accounts:read_any"
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	StatusCode  int       `json:"status_code"`
+	ClientIP    string    `json:"client_ip"`
+	CreatedAt   time.Time `json:"created_at"`
+}
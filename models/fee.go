@@ -0,0 +1,15 @@
+// go-bank-app/models/fee.go
+package models
+
+import "go-bank-app/money"
+
+// FeeQuote adalah perkiraan biaya sebuah penarikan atau transfer sebelum dijalankan.
+// Total = Amount + Fee, yaitu yang akan didebit dari akun.
+type FeeQuote struct {
+	AccountID   int         `json:"account_id"`
+	AccountType AccountType `json:"account_type"`
+	Operation   string      `json:"operation"` // withdraw atau transfer
+	Amount      money.Money `json:"amount"`
+	Fee         money.Money `json:"fee"`
+	Total       money.Money `json:"total"`
+}
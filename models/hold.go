@@ -0,0 +1,58 @@
+// go-bank-app/models/hold.go
+package models
+
+import (
+	"time"
+
+	"go-bank-app/money"
+)
+
+// Jenis hold
+const (
+	HoldTypeAuthorization = "authorization" // Otorisasi gaya kartu: di-capture (penuh/sebagian) atau kadaluarsa
+	HoldTypeLegal         = "legal"         // Blokir atas perintah hukum: hanya bisa dilepas, tidak di-capture
+	HoldTypeTransfer      = "transfer"      // Transfer yang menunggu persetujuan; dikelola oleh alur persetujuan
+)
+
+// Status hold
+const (
+	HoldActive   = "active"   // Mengurangi saldo tersedia, belum dibukukan
+	HoldCaptured = "captured" // Dana sudah dibukukan (mis. transfer disetujui, otorisasi di-capture)
+	HoldReleased = "released" // Dilepas tanpa pembukuan (mis. transfer ditolak/kadaluarsa)
+	HoldExpired  = "expired"  // Lewat ExpiresAt tanpa di-capture; dilepas otomatis oleh scheduler
+)
+
+// Hold menahan sebagian saldo akun tanpa membuat posting di buku besar. Selama aktif, jumlahnya
+// tidak bisa dipakai untuk penarikan atau transfer lain.
+type Hold struct {
+	ID             int          `json:"id"`
+	AccountID      int          `json:"account_id"`
+	Type           string       `json:"type"`
+	Amount         money.Money  `json:"amount"`                    // Dalam mata uang akun
+	CapturedAmount *money.Money `json:"captured_amount,omitempty"` // Jumlah yang dibukukan saat capture; sisanya dilepas
+	Reason         string       `json:"reason"`
+	Reference      string       `json:"reference,omitempty"` // Referensi eksternal, mis. kode otorisasi kartu
+	Status         string       `json:"status"`
+	ExpiresAt      *time.Time   `json:"expires_at,omitempty"`     // Kosong berarti berlaku sampai dilepas
+	TransactionID  *int         `json:"transaction_id,omitempty"` // Transaksi hasil capture
+	CreatedBy      int          `json:"created_by"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// CreateHoldRequest adalah body untuk POST /accounts/:id/holds. Otorisasi tanpa expires_at
+// kadaluarsa setelah config.AppCfg.Hold.AuthorizationTTL; hold legal tanpa expires_at berlaku sampai dilepas.
+type CreateHoldRequest struct {
+	Type      string      `json:"type" binding:"required,oneof=authorization legal"`
+	Amount    money.Money `json:"amount" binding:"required,gt=0"`
+	Reason    string      `json:"reason" binding:"required,max=255"`
+	Reference string      `json:"reference" binding:"max=64"`
+	ExpiresAt *time.Time  `json:"expires_at"` // RFC 3339
+}
+
+// CaptureHoldRequest adalah body untuk POST /accounts/:id/holds/:holdId/capture.
+// Tanpa amount, seluruh hold di-capture; dengan amount lebih kecil, sisanya dilepas.
+type CaptureHoldRequest struct {
+	Amount      *money.Money `json:"amount" binding:"omitempty,gt=0"`
+	Description string       `json:"description" binding:"max=255"`
+}
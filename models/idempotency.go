@@ -0,0 +1,26 @@
+// go-bank-app/models/idempotency.go
+package models
+
+import "time"
+
+// Status kunci idempotensi
+const (
+	IdempotencyInProgress = "in_progress" // Request pertama masih diproses
+	IdempotencyCompleted  = "completed"   // Response sudah tersimpan dan bisa diputar ulang
+	IdempotencyFailed     = "failed"      // Gagal di sisi server setelah mungkin sudah mengubah data; tidak boleh diulang
+)
+
+// IdempotencyRecord menyimpan satu Idempotency-Key beserta sidik jari request dan response-nya.
+type IdempotencyRecord struct {
+	ID           int       `json:"id"`
+	UserID       int       `json:"user_id"`
+	Key          string    `json:"key"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	RequestHash  string    `json:"request_hash"` // SHA-256 dari method, path dan body
+	Status       string    `json:"status"`
+	ResponseCode int       `json:"response_code"`
+	ResponseBody []byte    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
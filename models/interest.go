@@ -0,0 +1,72 @@
+// go-bank-app/models/interest.go
+package models
+
+import (
+	"time"
+
+	"go-bank-app/interest"
+	"go-bank-app/money"
+)
+
+// InterestAccrualScale adalah jumlah digit desimal akrual harian yang disimpan sebelum dibulatkan.
+const InterestAccrualScale = 10
+
+// InterestAccrual adalah bunga satu akun untuk satu hari, dihitung dari saldo akhir hari itu.
+// Saldo negatif (cerukan) menghasilkan bunga debit dengan Amount negatif.
+// Amount belum dibulatkan (presisi InterestAccrualScale desimal); pembulatan baru terjadi saat kapitalisasi bulanan.
+type InterestAccrual struct {
+	AccountID        int               `json:"account_id"`
+	AccrualDate      time.Time         `json:"accrual_date"`
+	EndOfDayBalance  money.Money       `json:"end_of_day_balance"`
+	InterestRate     string            `json:"interest_rate"` // Rencana bunga (atau bunga cerukan) yang berlaku pada hari itu
+	DayCount         interest.DayCount `json:"day_count"`
+	Amount           string            `json:"amount"` // Desimal tanpa pembulatan, dalam mata uang akun
+	CapitalizationID *int              `json:"capitalization_id,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+// InterestCapitalization adalah bunga satu bulan yang dikreditkan ke akun sebagai transaksi interest,
+// dan bunga cerukan bulan itu yang didebit sebagai transaksi overdraft_interest.
+// Paling banyak satu per akun per bulan, sehingga job yang diulang tidak membukukan dua kali.
+type InterestCapitalization struct {
+	ID                 int         `json:"id"`
+	AccountID          int         `json:"account_id"`
+	PeriodStart        time.Time   `json:"period_start"` // Hari pertama bulan
+	PeriodEnd          time.Time   `json:"period_end"`   // Hari terakhir bulan
+	Accrued            string      `json:"accrued"`      // Jumlah akrual harian positif sebelum dibulatkan
+	Amount             money.Money `json:"amount"`       // Yang dikreditkan, dibulatkan ke minor unit
+	TransactionID      *int        `json:"transaction_id,omitempty"`
+	DebitAccrued       string      `json:"debit_accrued"` // Jumlah bunga cerukan harian sebelum dibulatkan (positif)
+	DebitAmount        money.Money `json:"debit_amount"`  // Yang didebit, dibulatkan ke minor unit
+	DebitTransactionID *int        `json:"debit_transaction_id,omitempty"`
+	JournalEntryID     *int        `json:"journal_entry_id,omitempty"`
+	CreatedAt          time.Time   `json:"created_at"`
+}
+
+// AccountInterest adalah respons GET /accounts/:id/interest: rencana bunga akun, akrual bulan
+// berjalan yang belum dikapitalisasi, dan riwayat kapitalisasi.
+type AccountInterest struct {
+	AccountID       int                      `json:"account_id"`
+	AccountType     AccountType              `json:"account_type"`
+	InterestRate    string                   `json:"interest_rate"`
+	DayCount        interest.DayCount        `json:"day_count"`
+	AccruedToDate   money.Money              `json:"accrued_to_date"` // Total akrual yang belum dikapitalisasi, dibulatkan; negatif bila bunga cerukan lebih besar
+	Accruals        []InterestAccrual        `json:"accruals"`
+	Capitalizations []InterestCapitalization `json:"capitalizations"`
+}
+
+// UpdateInterestPlanRequest adalah body untuk PUT /accounts/:id/interest-plan. Rencana baru
+// berlaku untuk akrual hari-hari berikutnya; akrual yang sudah tercatat tidak dihitung ulang.
+type UpdateInterestPlanRequest struct {
+	InterestRate string `json:"interest_rate" binding:"required"` // Persen per tahun, mis. "2.5"
+	DayCount     string `json:"day_count" binding:"required"`     // ACT/365, ACT/360 atau 30/360
+}
+
+// InterestRunResult merangkum satu eksekusi job bunga untuk satu tanggal.
+type InterestRunResult struct {
+	Date        time.Time `json:"date"`
+	Accrued     int       `json:"accrued"`     // Akun yang akrualnya dicatat pada eksekusi ini
+	Skipped     int       `json:"skipped"`     // Akun yang akrualnya sudah ada dari eksekusi sebelumnya
+	Capitalized int       `json:"capitalized"` // Akun yang bunganya dikapitalisasi (hanya di akhir bulan)
+	Failed      int       `json:"failed"`
+}
@@ -0,0 +1,130 @@
+// go-bank-app/models/ledger.go
+package models
+
+import (
+	"time"
+
+	"go-bank-app/money"
+)
+
+// LedgerAccountType follows the usual accounting classification of general-ledger accounts.
+type LedgerAccountType string
+
+const (
+	LedgerAsset     LedgerAccountType = "asset"
+	LedgerLiability LedgerAccountType = "liability"
+	LedgerEquity    LedgerAccountType = "equity"
+	LedgerIncome    LedgerAccountType = "income"
+	LedgerExpense   LedgerAccountType = "expense"
+)
+
+// Kode akun sistem (akun milik bank sendiri, bukan milik nasabah).
+const (
+	LedgerCashVault       = "CASH_VAULT"       // Uang tunai fisik yang masuk/keluar lewat setor dan tarik tunai
+	LedgerFeesIncome      = "FEES_INCOME"      // Pendapatan biaya
+	LedgerSuspense        = "SUSPENSE"         // Penampungan sementara untuk dana yang belum jelas tujuannya
+	LedgerFXPosition      = "FX_POSITION"      // Posisi valas bank: satu akun per mata uang, menyeimbangkan transfer lintas mata uang
+	LedgerSettlement      = "SETTLEMENT"       // Utang ke jaringan kartu/merchant atas otorisasi yang sudah di-capture
+	LedgerInterestExpense = "INTEREST_EXPENSE" // Beban bunga yang dibayarkan ke tabungan nasabah
+	LedgerInterestIncome  = "INTEREST_INCOME"  // Pendapatan bunga cerukan yang dibebankan ke nasabah
+)
+
+// SystemLedgerAccounts describes every system account the ledger may create on demand.
+var SystemLedgerAccounts = map[string]struct {
+	Name string
+	Type LedgerAccountType
+}{
+	LedgerCashVault:       {Name: "Cash vault", Type: LedgerAsset},
+	LedgerFeesIncome:      {Name: "Fees income", Type: LedgerIncome},
+	LedgerSuspense:        {Name: "Suspense", Type: LedgerLiability},
+	LedgerFXPosition:      {Name: "FX position", Type: LedgerAsset},
+	LedgerSettlement:      {Name: "Card settlement", Type: LedgerLiability},
+	LedgerInterestExpense: {Name: "Interest expense", Type: LedgerExpense},
+	LedgerInterestIncome:  {Name: "Interest income", Type: LedgerIncome},
+}
+
+// LedgerAccount is an account in the general ledger. Customer accounts are liabilities of the
+// bank and point at their row in `accounts`; system accounts have a code and no AccountID.
+type LedgerAccount struct {
+	ID        int               `json:"id"`
+	Code      string            `json:"code"`
+	Name      string            `json:"name"`
+	Type      LedgerAccountType `json:"type"`
+	AccountID *int              `json:"account_id,omitempty"`
+	Currency  money.Currency    `json:"currency"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// JournalEntry is one balanced business event. Its postings must sum to zero per currency.
+type JournalEntry struct {
+	ID          int       `json:"id"`
+	Reference   string    `json:"reference"`
+	Description string    `json:"description"`
+	Postings    []Posting `json:"postings"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Posting is a single line of a journal entry. Amount is signed: positive is a debit,
+// negative is a credit. A customer account (a liability) therefore has a balance equal to
+// minus the sum of its postings.
+type Posting struct {
+	ID              int         `json:"id"`
+	JournalEntryID  int         `json:"journal_entry_id"`
+	LedgerAccountID int         `json:"ledger_account_id"`
+	Amount          money.Money `json:"amount"`
+	AccountID       int         `json:"-"` // Customer account moved by this posting, 0 for system accounts
+}
+
+// Debit builds a posting that debits the given ledger account.
+func Debit(la *LedgerAccount, amount money.Money) Posting {
+	return newPosting(la, amount.Abs())
+}
+
+// Credit builds a posting that credits the given ledger account.
+func Credit(la *LedgerAccount, amount money.Money) Posting {
+	return newPosting(la, amount.Abs().Neg())
+}
+
+func newPosting(la *LedgerAccount, amount money.Money) Posting {
+	p := Posting{LedgerAccountID: la.ID, Amount: amount}
+	if la.AccountID != nil {
+		p.AccountID = *la.AccountID
+	}
+	return p
+}
+
+// TrialBalanceLine is the total of all postings on one ledger account.
+type TrialBalanceLine struct {
+	LedgerAccountID int               `json:"ledger_account_id"`
+	Code            string            `json:"code"`
+	Name            string            `json:"name"`
+	Type            LedgerAccountType `json:"type"`
+	Currency        money.Currency    `json:"currency"`
+	Debit           money.Money       `json:"debit"`
+	Credit          money.Money       `json:"credit"`
+}
+
+// TrialBalanceTotal sums every line of one currency. Debit and Credit must be equal.
+type TrialBalanceTotal struct {
+	Currency money.Currency `json:"currency"`
+	Debit    money.Money    `json:"debit"`
+	Credit   money.Money    `json:"credit"`
+	Balanced bool           `json:"balanced"`
+}
+
+// BalanceDiscrepancy reports a customer account whose stored balance differs from its postings.
+type BalanceDiscrepancy struct {
+	AccountID     int         `json:"account_id"`
+	AccountNumber string      `json:"account_number"`
+	StoredBalance money.Money `json:"stored_balance"`
+	LedgerBalance money.Money `json:"ledger_balance"`
+}
+
+// TrialBalance is the response of GET /ledger/trial-balance.
+type TrialBalance struct {
+	Lines         []TrialBalanceLine   `json:"lines"`
+	Totals        []TrialBalanceTotal  `json:"totals"`
+	Balanced      bool                 `json:"balanced"`
+	Discrepancies []BalanceDiscrepancy `json:"discrepancies"`
+	GeneratedAt   time.Time            `json:"generated_at"`
+}
@@ -0,0 +1,53 @@
+// go-bank-app/models/limit.go
+package models
+
+import (
+	"time"
+
+	"go-bank-app/money"
+)
+
+// Nama batas, dipakai pada error pelanggaran batas dan respons API.
+const (
+	LimitPerTransaction = "per_transaction" // Nominal maksimum satu penarikan/transfer
+	LimitDailyDebit     = "daily_debit"     // Total penarikan + transfer keluar per hari kalender
+	LimitMonthlyDebit   = "monthly_debit"   // Total penarikan + transfer keluar per bulan kalender
+	LimitDailyTransfers = "daily_transfers" // Jumlah transfer keluar per hari kalender
+)
+
+// LimitedDebitTypes adalah jenis transaksi yang dihitung terhadap batas debit harian/bulanan.
+// Transaksi yang sudah dibalik tidak dihitung.
+var LimitedDebitTypes = []string{TransactionWithdraw, TransactionTransferOut}
+
+// AccountLimits adalah sekumpulan batas debit. Field nil berarti tidak dibatasi (pada batas
+// efektif) atau tidak di-override (pada override per akun).
+type AccountLimits struct {
+	PerTransaction *money.Money `json:"per_transaction" binding:"omitempty,gt=0"`
+	DailyDebit     *money.Money `json:"daily_debit" binding:"omitempty,gt=0"`
+	MonthlyDebit   *money.Money `json:"monthly_debit" binding:"omitempty,gt=0"`
+	DailyTransfers *int         `json:"daily_transfers" binding:"omitempty,gt=0"`
+}
+
+// LimitUsage adalah pemakaian batas sejak awal hari/bulan berjalan.
+type LimitUsage struct {
+	DailyDebit     money.Money `json:"daily_debit"`
+	MonthlyDebit   money.Money `json:"monthly_debit"`
+	DailyTransfers int         `json:"daily_transfers"`
+	AsOf           time.Time   `json:"as_of"`
+}
+
+// AccountLimitsView adalah respons GET /accounts/:id/limits: batas efektif (default jenis akun
+// ditimpa override), override milik akun itu sendiri, dan pemakaian saat ini.
+type AccountLimitsView struct {
+	AccountID   int           `json:"account_id"`
+	AccountType AccountType   `json:"account_type"`
+	Limits      AccountLimits `json:"limits"`
+	Overrides   AccountLimits `json:"overrides"`
+	Usage       LimitUsage    `json:"usage"`
+}
+
+// UpdateAccountLimitsRequest adalah body untuk PUT /accounts/:id/limits. Body menggantikan seluruh
+// override akun; field yang kosong kembali memakai default jenis akun.
+type UpdateAccountLimitsRequest struct {
+	AccountLimits
+}
@@ -0,0 +1,38 @@
+// go-bank-app/models/pagination.go
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor dikembalikan jika cursor dari client tidak bisa dibaca.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor menunjuk posisi terakhir pada daftar yang diurutkan (waktu DESC, id DESC).
+// Client menerimanya sebagai string opaque dan mengirimkannya kembali apa adanya.
+type Cursor struct {
+	Time time.Time `json:"t"`
+	ID   int       `json:"id"`
+}
+
+// Encode mengubah cursor menjadi string opaque yang aman untuk query string.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c) // Cursor hanya berisi time.Time dan int, marshal tidak mungkin gagal
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor membaca cursor yang sebelumnya dibuat oleh Cursor.Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 || c.Time.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
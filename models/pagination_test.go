@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, c := range []Cursor{
+		{Time: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), ID: 1},
+		{Time: time.Date(2024, 12, 31, 23, 59, 59, 0, time.FixedZone("WIB", 7*3600)), ID: 987654},
+	} {
+		encoded := c.Encode()
+		for _, ch := range encoded {
+			if ch == '+' || ch == '/' || ch == '=' {
+				t.Fatalf("Encode() = %q is not safe in a query string", encoded)
+			}
+		}
+		got, err := DecodeCursor(encoded)
+		if err != nil {
+			t.Fatalf("DecodeCursor(%q): %v", encoded, err)
+		}
+		if !got.Time.Equal(c.Time) || got.ID != c.ID {
+			t.Errorf("round trip of %+v gave %+v", c, *got)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	for name, in := range map[string]string{
+		"empty":        "",
+		"not base64":   "!!!",
+		"padded":       base64.URLEncoding.EncodeToString([]byte(`{"t":"2024-03-01T10:30:00Z","id":1}`)),
+		"not json":     raw("hello"),
+		"missing id":   raw(`{"t":"2024-03-01T10:30:00Z"}`),
+		"negative id":  raw(`{"t":"2024-03-01T10:30:00Z","id":-5}`),
+		"missing time": raw(`{"id":3}`),
+		"bad time":     raw(`{"t":"yesterday","id":3}`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if c, err := DecodeCursor(in); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) = %+v, %v; want ErrInvalidCursor", in, c, err)
+			}
+		})
+	}
+}
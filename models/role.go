@@ -0,0 +1,62 @@
+// go-bank-app/models/role.go
+package models
+
+// Peran (role) user. User baru selalu mendapat RoleCustomer; admin pertama diangkat
+// langsung lewat database, admin berikutnya lewat PUT /users/:id/role.
+const (
+	RoleCustomer = "customer"
+	RoleOperator = "operator" // Staf back-office: boleh melihat data nasabah, tidak boleh mengelola user
+	RoleAdmin    = "admin"
+)
+
+// Izin (permission) yang diperiksa oleh middleware.RequirePermission dan handler.
+const (
+	PermUsersRead           = "users:read"            // Melihat profil user mana pun
+	PermUsersManage         = "users:manage"          // Mengubah peran user
+	PermAccountsReadAny     = "accounts:read_any"     // Melihat akun milik siapa pun
+	PermAccountsManage      = "accounts:manage"       // Membekukan, mengaktifkan dan menutup akun milik siapa pun
+	PermTransactionsReadAny = "transactions:read_any" // Melihat transaksi dan transfer akun milik siapa pun
+	PermLedgerRead          = "ledger:read"           // Melihat neraca saldo buku besar
+	PermHoldsManage         = "holds:manage"          // Membuat, meng-capture dan melepas hold di akun mana pun
+	PermTransactionsReverse = "transactions:reverse"  // Membalik transaksi yang salah dengan entri kompensasi
+)
+
+// RolePermissions memetakan setiap peran ke izin yang dimilikinya.
+var RolePermissions = map[string][]string{
+	RoleCustomer: {},
+	RoleOperator: {
+		PermUsersRead,
+		PermAccountsReadAny,
+		PermAccountsManage,
+		PermTransactionsReadAny,
+		PermLedgerRead,
+		PermHoldsManage,
+		PermTransactionsReverse,
+	},
+	RoleAdmin: {
+		PermUsersRead,
+		PermUsersManage,
+		PermAccountsReadAny,
+		PermAccountsManage,
+		PermTransactionsReadAny,
+		PermLedgerRead,
+		PermHoldsManage,
+		PermTransactionsReverse,
+	},
+}
+
+// IsValidRole melaporkan apakah role dikenal.
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// PermissionsForRole mengembalikan daftar izin untuk sebuah peran (kosong untuk peran tak dikenal).
+func PermissionsForRole(role string) []string {
+	return append([]string{}, RolePermissions[role]...)
+}
+
+// UpdateUserRoleRequest adalah body untuk PUT /users/:id/role.
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=customer operator admin"`
+}
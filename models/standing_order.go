@@ -0,0 +1,186 @@
+// go-bank-app/models/standing_order.go
+package models
+
+import (
+	"time"
+
+	"go-bank-app/money"
+)
+
+// StandingOrderFrequency menentukan seberapa sering standing order dijalankan.
+type StandingOrderFrequency string
+
+const (
+	FrequencyOnce       StandingOrderFrequency = "once"         // Sekali pada StartDate
+	FrequencyDaily      StandingOrderFrequency = "daily"        // Setiap hari
+	FrequencyWeekly     StandingOrderFrequency = "weekly"       // Setiap 7 hari sejak StartDate
+	FrequencyMonthly    StandingOrderFrequency = "monthly"      // Setiap bulan pada DayOfMonth (tanggal 29-31 mengikuti akhir bulan jika tidak ada)
+	FrequencyEndOfMonth StandingOrderFrequency = "end_of_month" // Setiap hari terakhir bulan
+)
+
+// InsufficientFundsPolicy menentukan apa yang terjadi jika saldo tidak cukup saat jadwal tiba.
+type InsufficientFundsPolicy string
+
+const (
+	PolicySkip   InsufficientFundsPolicy = "skip"   // Lewati jadwal ini, lanjut ke jadwal berikutnya
+	PolicyRetry  InsufficientFundsPolicy = "retry"  // Coba lagi hingga MaxRetries kali, lalu lewati
+	PolicyNotify InsufficientFundsPolicy = "notify" // Beri tahu pemilik akun, lalu lewati
+)
+
+// Status standing order
+const (
+	StandingOrderActive    = "active"
+	StandingOrderPaused    = "paused"
+	StandingOrderCompleted = "completed" // Kondisi akhir tercapai
+	StandingOrderCancelled = "cancelled"
+	StandingOrderSuspended = "suspended" // Gagal karena sebab selain saldo (mis. akun dibekukan); aktifkan lagi secara manual
+)
+
+// Status eksekusi standing order
+const (
+	ExecutionSucceeded       = "succeeded"
+	ExecutionPendingApproval = "pending_approval" // Di atas batas persetujuan; transfer menunggu approver, run tetap dihitung
+	ExecutionSkipped         = "skipped"          // Saldo tidak cukup, jadwal dilewati
+	ExecutionRetrying        = "retrying"         // Saldo tidak cukup, akan dicoba lagi
+	ExecutionFailed          = "failed"           // Gagal karena sebab lain; order ditangguhkan
+)
+
+// StandingOrder adalah transfer terjadwal dari AccountID ke ToAccountNumber.
+type StandingOrder struct {
+	ID                      int                     `json:"id"`
+	AccountID               int                     `json:"account_id"`
+	ToAccountNumber         string                  `json:"to_account_number"`
+	Amount                  money.Money             `json:"amount"` // Dalam mata uang akun pengirim
+	Description             string                  `json:"description"`
+	Frequency               StandingOrderFrequency  `json:"frequency"`
+	DayOfMonth              *int                    `json:"day_of_month,omitempty"` // Hanya untuk monthly
+	StartDate               time.Time               `json:"start_date"`
+	EndDate                 *time.Time              `json:"end_date,omitempty"` // Kondisi akhir: tidak ada jadwal setelah tanggal ini
+	MaxRuns                 *int                    `json:"max_runs,omitempty"` // Kondisi akhir: berhenti setelah sekian eksekusi berhasil
+	RunsCompleted           int                     `json:"runs_completed"`
+	InsufficientFundsPolicy InsufficientFundsPolicy `json:"insufficient_funds_policy"`
+	MaxRetries              int                     `json:"max_retries"`
+	RetryCount              int                     `json:"retry_count"`             // Percobaan ulang untuk jadwal yang sedang berjalan
+	ScheduledFor            *time.Time              `json:"scheduled_for,omitempty"` // Jadwal yang sedang dijalankan; nil jika selesai
+	NextRunAt               *time.Time              `json:"next_run_at,omitempty"`   // Kapan scheduler mencoba lagi (sama dengan ScheduledFor kecuali saat retry)
+	Status                  string                  `json:"status"`
+	CreatedBy               int                     `json:"created_by"`
+	CreatedAt               time.Time               `json:"created_at"`
+	UpdatedAt               time.Time               `json:"updated_at"`
+}
+
+// StandingOrderExecution mencatat satu percobaan eksekusi standing order.
+type StandingOrderExecution struct {
+	ID              int       `json:"id"`
+	StandingOrderID int       `json:"standing_order_id"`
+	ScheduledFor    time.Time `json:"scheduled_for"`
+	Attempt         int       `json:"attempt"`
+	Status          string    `json:"status"`
+	TransferID      *int      `json:"transfer_id,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	ExecutedAt      time.Time `json:"executed_at"`
+}
+
+// FirstRun mengembalikan jadwal pertama pada atau setelah StartDate.
+func (o *StandingOrder) FirstRun() time.Time {
+	start := startOfDay(o.StartDate)
+	switch o.Frequency {
+	case FrequencyMonthly:
+		run := monthDay(start.Year(), start.Month(), *o.DayOfMonth, start.Location())
+		if run.Before(start) {
+			run = monthDay(start.Year(), start.Month()+1, *o.DayOfMonth, start.Location())
+		}
+		return run
+	case FrequencyEndOfMonth:
+		return monthDay(start.Year(), start.Month(), 31, start.Location())
+	default:
+		return start
+	}
+}
+
+// NextRunAfter mengembalikan jadwal setelah prev, atau false jika tidak ada jadwal lagi
+// (frekuensi once, EndDate terlewati, atau MaxRuns tercapai).
+func (o *StandingOrder) NextRunAfter(prev time.Time) (time.Time, bool) {
+	if o.MaxRuns != nil && o.RunsCompleted >= *o.MaxRuns {
+		return time.Time{}, false
+	}
+	prev = startOfDay(prev)
+	var next time.Time
+	switch o.Frequency {
+	case FrequencyDaily:
+		next = prev.AddDate(0, 0, 1)
+	case FrequencyWeekly:
+		next = prev.AddDate(0, 0, 7)
+	case FrequencyMonthly:
+		next = monthDay(prev.Year(), prev.Month()+1, *o.DayOfMonth, prev.Location())
+	case FrequencyEndOfMonth:
+		next = monthDay(prev.Year(), prev.Month()+1, 31, prev.Location())
+	default:
+		return time.Time{}, false
+	}
+	if !o.withinEndDate(next) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// withinEndDate melaporkan apakah jadwal t masih sebelum atau pada EndDate.
+func (o *StandingOrder) withinEndDate(t time.Time) bool {
+	return o.EndDate == nil || !startOfDay(t).After(startOfDay(*o.EndDate))
+}
+
+// IsRecurring melaporkan apakah order dijalankan lebih dari sekali.
+func (f StandingOrderFrequency) IsRecurring() bool {
+	return f != FrequencyOnce
+}
+
+// IsValid melaporkan apakah f adalah frekuensi yang dikenal.
+func (f StandingOrderFrequency) IsValid() bool {
+	switch f {
+	case FrequencyOnce, FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyEndOfMonth:
+		return true
+	}
+	return false
+}
+
+// startOfDay memotong t ke pukul 00:00 di zona waktu lokal server.
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// monthDay mengembalikan tanggal day pada bulan tertentu, dibatasi ke hari terakhir bulan itu.
+// month boleh di luar 1-12 (mis. 13 = Januari tahun berikutnya).
+func monthDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	last := first.AddDate(0, 1, -1).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, loc)
+}
+
+// CreateStandingOrderRequest adalah body untuk POST /accounts/:id/standing-orders.
+// Tanggal memakai format YYYY-MM-DD. Order berulang wajib punya kondisi akhir (end_date atau max_runs).
+type CreateStandingOrderRequest struct {
+	ToAccountNumber         string                  `json:"to_account_number" binding:"required,account_number"`
+	Amount                  money.Money             `json:"amount" binding:"required,gt=0"`
+	Description             string                  `json:"description" binding:"max=255"`
+	Frequency               StandingOrderFrequency  `json:"frequency" binding:"required,oneof=once daily weekly monthly end_of_month"`
+	DayOfMonth              *int                    `json:"day_of_month" binding:"omitempty,min=1,max=31"`
+	StartDate               string                  `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate                 string                  `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	MaxRuns                 *int                    `json:"max_runs" binding:"omitempty,min=1"`
+	InsufficientFundsPolicy InsufficientFundsPolicy `json:"insufficient_funds_policy" binding:"omitempty,oneof=skip retry notify"`
+	MaxRetries              int                     `json:"max_retries" binding:"omitempty,min=1,max=10"`
+}
+
+// UpdateStandingOrderRequest adalah body untuk PUT /accounts/:id/standing-orders/:orderId.
+// Hanya field yang diisi yang diubah; status hanya bisa active atau paused (pakai DELETE untuk membatalkan).
+type UpdateStandingOrderRequest struct {
+	Amount      *money.Money `json:"amount" binding:"omitempty,gt=0"`
+	Description *string      `json:"description" binding:"omitempty,max=255"`
+	EndDate     *string      `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	MaxRuns     *int         `json:"max_runs" binding:"omitempty,min=1"`
+	Status      *string      `json:"status" binding:"omitempty,oneof=active paused"`
+}
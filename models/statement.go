@@ -0,0 +1,47 @@
+// go-bank-app/models/statement.go
+package models
+
+import (
+	"time"
+
+	"go-bank-app/money"
+)
+
+// MaxStatementPeriod membatasi panjang periode satu rekening koran agar query tetap ringan.
+const MaxStatementPeriod = 366 * 24 * time.Hour
+
+// Statement adalah rekening koran satu akun untuk periode [From, To).
+type Statement struct {
+	AccountID      int             `json:"account_id"`
+	AccountNumber  string          `json:"account_number"`
+	Currency       money.Currency  `json:"currency"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	OpeningBalance money.Money     `json:"opening_balance"` // Saldo tepat sebelum From
+	ClosingBalance money.Money     `json:"closing_balance"` // Saldo setelah baris terakhir
+	TotalCredits   money.Money     `json:"total_credits"`
+	TotalDebits    money.Money     `json:"total_debits"`
+	Lines          []StatementLine `json:"lines"`
+	GeneratedAt    time.Time       `json:"generated_at"`
+
+	// Cerukan (hanya relevan untuk akun checking dengan fasilitas overdraft)
+	OverdraftLimit    money.Money `json:"overdraft_limit"`     // Batas cerukan saat rekening koran dibuat
+	OverdraftUsed     money.Money `json:"overdraft_used"`      // Cerukan terpakai pada saldo penutup
+	PeakOverdraftUsed money.Money `json:"peak_overdraft_used"` // Cerukan terbesar yang terpakai selama periode
+}
+
+// HasOverdraft melaporkan apakah rekening koran perlu menampilkan ringkasan cerukan.
+func (s *Statement) HasOverdraft() bool {
+	return s.OverdraftLimit.IsPositive() || s.PeakOverdraftUsed.IsPositive()
+}
+
+// StatementLine adalah satu transaksi pada rekening koran beserta saldo berjalan setelahnya.
+type StatementLine struct {
+	TransactionID   int         `json:"transaction_id"`
+	TransactionDate time.Time   `json:"transaction_date"`
+	TransactionType string      `json:"transaction_type"`
+	Description     string      `json:"description"`
+	Debit           money.Money `json:"debit"`  // Nol untuk dana masuk
+	Credit          money.Money `json:"credit"` // Nol untuk dana keluar
+	Balance         money.Money `json:"balance"`
+}
@@ -0,0 +1,35 @@
+// go-bank-app/models/token.go
+package models
+
+import "time"
+
+// RefreshToken adalah refresh token yang tersimpan (hanya hash-nya) di database.
+// Semua token hasil rotasi dari satu login berbagi FamilyID yang sama.
+type RefreshToken struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"user_id"`
+	TokenHash    string     `json:"-"`
+	FamilyID     string     `json:"family_id"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ReplacedByID *int       `json:"replaced_by_id,omitempty"` // Diisi saat token dirotasi
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TokenPair adalah response login dan refresh.
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // Detik sampai access token kadaluarsa
+}
+
+// RefreshTokenRequest adalah body untuk POST /auth/refresh.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest adalah body (opsional) untuk POST /auth/logout.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
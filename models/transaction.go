@@ -1,20 +1,132 @@
 // go-bank-app/models/transaction.go
 package models
 
-import "time"
+import (
+	"time"
+
+	"go-bank-app/money"
+)
+
+// Jenis transaksi. Setiap jenis menentukan arah dana pada akun tempat transaksi dicatat.
+const (
+	TransactionDeposit           = "deposit"
+	TransactionWithdraw          = "withdraw"
+	TransactionTransferOut       = "transfer_out"
+	TransactionTransferIn        = "transfer_in"
+	TransactionCapture           = "capture"            // Pembayaran dari hold otorisasi yang di-capture
+	TransactionReversalIn        = "reversal_in"        // Pembalikan transaksi yang dulu mengurangi saldo (dana kembali masuk)
+	TransactionReversalOut       = "reversal_out"       // Pembalikan transaksi yang dulu menambah saldo (dana ditarik kembali)
+	TransactionFee               = "fee"                // Biaya penarikan/transfer menurut fee schedule
+	TransactionInterest          = "interest"           // Kapitalisasi bunga bulanan
+	TransactionOverdraftInterest = "overdraft_interest" // Bunga cerukan bulanan yang didebit dari akun
+)
+
+// TransactionTypes berisi semua jenis transaksi yang valid, dipakai untuk validasi filter.
+var TransactionTypes = []string{
+	TransactionDeposit, TransactionWithdraw, TransactionTransferOut, TransactionTransferIn, TransactionCapture,
+	TransactionReversalIn, TransactionReversalOut, TransactionFee, TransactionInterest, TransactionOverdraftInterest,
+}
+
+// creditTransactionTypes adalah jenis transaksi yang menambah saldo akun; jenis lain mengurangi.
+var creditTransactionTypes = map[string]bool{
+	TransactionDeposit:    true,
+	TransactionTransferIn: true,
+	TransactionReversalIn: true,
+	TransactionInterest:   true,
+}
+
+// CreditTransactionTypes mengembalikan daftar jenis transaksi yang menambah saldo akun.
+func CreditTransactionTypes() []string {
+	var types []string
+	for _, t := range TransactionTypes {
+		if creditTransactionTypes[t] {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// IsCredit melaporkan apakah transaksi menambah saldo akun (dana masuk).
+func (t Transaction) IsCredit() bool {
+	return creditTransactionTypes[t.TransactionType]
+}
+
+// SignedAmount mengembalikan Amount dengan tanda: positif untuk dana masuk, negatif untuk dana keluar.
+func (t Transaction) SignedAmount() money.Money {
+	if t.IsCredit() {
+		return t.Amount
+	}
+	return t.Amount.Neg()
+}
+
+// IsValidTransactionType melaporkan apakah t adalah jenis transaksi yang dikenal.
+func IsValidTransactionType(t string) bool {
+	for _, known := range TransactionTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
 
 type Transaction struct {
-	ID              int       `json:"id"`
-	AccountID       int       `json:"account_id"`
-	TransactionType string    `json:"transaction_type"` // deposit, withdraw, transfer_out, transfer_in
-	Amount          float64   `json:"amount"`
-	Description     string    `json:"description"`
-	TransactionDate time.Time `json:"transaction_date"`
+	ID              int            `json:"id"`
+	AccountID       int            `json:"account_id"`
+	TransactionType string         `json:"transaction_type"` // deposit, withdraw, transfer_out, transfer_in, capture, reversal_in, reversal_out, fee, interest
+	Amount          money.Money    `json:"amount"`
+	Currency        money.Currency `json:"currency"` // Mata uang akun tempat transaksi dicatat
+	Description     string         `json:"description"`
+	TransactionDate time.Time      `json:"transaction_date"`
+	JournalEntryID  int            `json:"journal_entry_id,omitempty"` // Entri jurnal buku besar yang mencatat transaksi ini
+	ReversalOf      *int           `json:"reversal_of,omitempty"`      // Transaksi asli yang dibalik oleh transaksi ini
+	ReversedBy      *int           `json:"reversed_by,omitempty"`      // Transaksi pembalik, jika transaksi ini sudah dibalik
+	Reversed        bool           `json:"reversed"`                   // true jika transaksi ini sudah dibalik
+}
+
+// ReverseTransactionRequest adalah body untuk POST /transactions/:id/reverse.
+type ReverseTransactionRequest struct {
+	Reason string `json:"reason" binding:"required,max=200"`
+}
+
+// TransactionReversal adalah hasil pembalikan: satu entri jurnal kompensasi dan satu transaksi
+// pembalik untuk setiap kaki transaksi asli (dua kaki untuk transfer).
+type TransactionReversal struct {
+	OriginalTransactionID int           `json:"original_transaction_id"`
+	JournalEntryID        int           `json:"journal_entry_id"` // Entri jurnal kompensasi
+	TransferID            *int          `json:"transfer_id,omitempty"`
+	Reason                string        `json:"reason"`
+	ActorUserID           int           `json:"actor_user_id"`
+	Transactions          []Transaction `json:"transactions"`
 }
 
 type TransferRequest struct {
-	FromAccountID string  `json:"from_account_id" binding:"required"`
-	ToAccountID   string  `json:"to_account_id" binding:"required"`
-	Amount        float64 `json:"amount" binding:"required,gt=0"`
-	Description   string  `json:"description"`
+	FromAccountID string      `json:"from_account_id" binding:"required,account_number"` // Nomor akun; check digit divalidasi sebelum query DB
+	ToAccountID   string      `json:"to_account_id" binding:"required,account_number"`
+	Amount        money.Money `json:"amount" binding:"required,gt=0"`
+	Description   string      `json:"description"`
+}
+
+// Batas jumlah baris per halaman riwayat transaksi.
+const (
+	DefaultTransactionPageSize = 50
+	MaxTransactionPageSize     = 200
+)
+
+// TransactionFilter adalah filter dan posisi halaman untuk riwayat transaksi satu akun.
+// Field yang kosong/nil berarti tidak difilter.
+type TransactionFilter struct {
+	From        *time.Time   // transaction_date >= From
+	To          *time.Time   // transaction_date < To
+	Types       []string     // transaction_type IN (...)
+	MinAmount   *money.Money // amount >= MinAmount
+	MaxAmount   *money.Money // amount <= MaxAmount
+	Description string       // Substring dari description
+	After       *Cursor      // Lanjutkan setelah baris ini (urutan transaction_date DESC, id DESC)
+	Limit       int
+}
+
+// TransactionPage adalah satu halaman riwayat transaksi. NextCursor kosong berarti halaman terakhir.
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
 }
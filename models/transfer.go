@@ -0,0 +1,65 @@
+// go-bank-app/models/transfer.go
+package models
+
+import (
+	"time"
+
+	"go-bank-app/money"
+)
+
+// Status transfer. Transfer di atas batas persetujuan dibuat sebagai pending_approval dengan dana
+// ditahan (hold), lalu menjadi completed, rejected atau expired setelah diputuskan. Transfer
+// completed yang dibalik operator/admin menjadi reversed.
+const (
+	TransferCompleted       = "completed"
+	TransferPendingApproval = "pending_approval"
+	TransferRejected        = "rejected"
+	TransferExpired         = "expired"
+	TransferReversed        = "reversed"
+)
+
+// Transfer adalah satu perpindahan dana antar akun beserta referensi ke kedua transaksinya.
+type Transfer struct {
+	ID                    int               `json:"id"`
+	FromAccountID         int               `json:"from_account_id"`
+	FromAccountNumber     string            `json:"from_account_number"`
+	ToAccountID           int               `json:"to_account_id"`
+	ToAccountNumber       string            `json:"to_account_number"`
+	Amount                money.Money       `json:"amount"`             // Didebit dari pengirim, dalam mata uang pengirim
+	DestinationAmount     money.Money       `json:"destination_amount"` // Dikredit ke penerima, dalam mata uang penerima
+	Fee                   money.Money       `json:"fee"`                // Biaya transfer, didebit dari pengirim di luar Amount
+	FX                    *TransferFX       `json:"fx,omitempty"`       // Hanya terisi untuk transfer lintas mata uang
+	Description           string            `json:"description"`
+	Status                string            `json:"status"`
+	OutboundTransactionID *int              `json:"outbound_transaction_id"` // Transaksi transfer_out di akun pengirim
+	InboundTransactionID  *int              `json:"inbound_transaction_id"`  // Transaksi transfer_in di akun penerima
+	JournalEntryID        *int              `json:"journal_entry_id"`
+	Approval              *TransferApproval `json:"approval,omitempty"` // Hanya terisi untuk transfer yang memerlukan persetujuan
+	CreatedAt             time.Time         `json:"created_at"`
+	UpdatedAt             time.Time         `json:"updated_at"`
+}
+
+// TransferFX mencatat konversi yang dipakai pada transfer lintas mata uang.
+type TransferFX struct {
+	MidRate     string      `json:"mid_rate"`     // Kurs tengah dari RateProvider (1 mata uang pengirim = MidRate mata uang penerima)
+	AppliedRate string      `json:"applied_rate"` // Kurs yang diberikan ke nasabah setelah spread
+	RateSource  string      `json:"rate_source"`
+	RateAsOf    time.Time   `json:"rate_as_of"`
+	Spread      money.Money `json:"spread"` // Selisih kurs dalam mata uang penerima, dibukukan sebagai pendapatan biaya
+}
+
+// TransferApproval mencatat alur maker-checker sebuah transfer: siapa yang membuat, sampai kapan
+// menunggu persetujuan, dan siapa yang memutuskan.
+type TransferApproval struct {
+	RequestedBy int        `json:"requested_by"` // Maker: user yang membuat transfer
+	ExpiresAt   time.Time  `json:"expires_at"`   // Setelah lewat, transfer otomatis expired dan hold dilepas
+	HoldID      *int       `json:"hold_id"`      // Hold yang menahan dana selama menunggu persetujuan
+	DecidedBy   *int       `json:"decided_by"`   // Checker: approver yang menyetujui/menolak
+	DecidedAt   *time.Time `json:"decided_at"`
+	Reason      string     `json:"reason,omitempty"` // Alasan penolakan
+}
+
+// RejectTransferRequest adalah body untuk POST /transfers/:id/reject.
+type RejectTransferRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
@@ -4,17 +4,27 @@ package models
 import "time"
 
 type User struct {
-	ID           int       `json:"id"`
-	Name         string    `json:"name" binding:"required"`
-	Email        string    `json:"email" binding:"required,email"`
-	PasswordHash string    `json:"-"` // "-" agar tidak disertakan dalam JSON response
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                int       `json:"id"`
+	Name              string    `json:"name" binding:"required"`
+	Email             string    `json:"email" binding:"required,email"`
+	PasswordHash      string    `json:"-"` // "-" agar tidak disertakan dalam JSON response
+	Role              string    `json:"role"`
+	Permissions       []string  `json:"permissions"`                  // Diturunkan dari Role, tidak disimpan di database
+	PreferredLanguage string    `json:"preferred_language,omitempty"` // "id" atau "en"; kosong berarti ikut Accept-Language
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 // Struct untuk request membuat user baru (tanpa ID dan timestamp)
 type CreateUserRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Name              string `json:"name" binding:"required"`
+	Email             string `json:"email" binding:"required,email"`
+	Password          string `json:"password" binding:"required,min=6"`
+	PreferredLanguage string `json:"preferred_language" binding:"omitempty,oneof=id en"`
+}
+
+// UpdatePreferredLanguageRequest adalah body untuk PUT /users/:id/language. String kosong menghapus
+// preferensi sehingga bahasa kembali mengikuti header Accept-Language.
+type UpdatePreferredLanguageRequest struct {
+	PreferredLanguage string `json:"preferred_language" binding:"omitempty,oneof=id en"`
 }
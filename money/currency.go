@@ -0,0 +1,64 @@
+// go-bank-app/money/currency.go
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedCurrency is returned for currency codes this package has no exponent for.
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// Currency is an ISO 4217 alphabetic currency code, e.g. "IDR" or "USD".
+type Currency string
+
+// Commonly used currencies.
+const (
+	IDR Currency = "IDR"
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	SGD Currency = "SGD"
+	JPY Currency = "JPY"
+	KWD Currency = "KWD"
+)
+
+// DefaultCurrency is used whenever an amount arrives without an explicit currency.
+var DefaultCurrency = IDR
+
+// exponents maps each supported currency to its number of minor-unit digits (ISO 4217).
+var exponents = map[Currency]int{
+	IDR: 2,
+	USD: 2,
+	EUR: 2,
+	SGD: 2,
+	JPY: 0,
+	KWD: 3,
+}
+
+// ParseCurrency validates and normalizes a currency code.
+func ParseCurrency(code string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !c.IsValid() {
+		return "", fmt.Errorf("%q: %w", code, ErrUnsupportedCurrency)
+	}
+	return c, nil
+}
+
+// IsValid reports whether the currency is known to this package.
+func (c Currency) IsValid() bool {
+	_, ok := exponents[c]
+	return ok
+}
+
+// Exponent returns the number of fractional digits the currency allows.
+func (c Currency) Exponent() int {
+	if exp, ok := exponents[c]; ok {
+		return exp
+	}
+	return 2
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
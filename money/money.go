@@ -0,0 +1,362 @@
+// go-bank-app/money/money.go
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrTooPrecise is returned when an amount has more significant fractional digits than its currency allows.
+	ErrTooPrecise = errors.New("amount has more fractional digits than the currency allows")
+	// ErrCurrencyMismatch is returned when an amount is used with an account in a different currency.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Money is an exact monetary amount stored as an integer number of minor units
+// (e.g. cents) together with its currency. The zero value is zero in DefaultCurrency.
+//
+// An amount decoded from a bare JSON number carries no explicit currency; it behaves as
+// DefaultCurrency until In binds it to the currency of the account it is applied to.
+type Money struct {
+	amount   int64
+	currency Currency
+	literal  string // Decimal as decoded, kept for an amount without a currency until In binds one
+}
+
+// New creates a Money from a number of minor units.
+func New(minorUnits int64, currency Currency) Money {
+	return Money{amount: minorUnits, currency: currency}
+}
+
+// Zero returns a zero amount in the given currency.
+func Zero(currency Currency) Money {
+	return Money{currency: currency}
+}
+
+// Parse parses a plain decimal string such as "1500.25" into Money. Digits beyond the
+// currency's exponent are only accepted when they are zero; anything else is rejected
+// with ErrTooPrecise rather than silently rounded.
+func Parse(s string, currency Currency) (Money, error) {
+	r, err := parseDecimal(s)
+	if err != nil {
+		return Money{}, err
+	}
+	scaled := new(big.Rat).Mul(r, scaleOf(currency))
+	if !scaled.IsInt() {
+		return Money{}, fmt.Errorf("%w: %s %s", ErrTooPrecise, s, currency)
+	}
+	return fromInt(scaled.Num(), currency)
+}
+
+// ParseRounded parses a decimal string and rounds it to the currency's precision using mode.
+func ParseRounded(s string, currency Currency, mode RoundingMode) (Money, error) {
+	r, err := parseDecimal(s)
+	if err != nil {
+		return Money{}, err
+	}
+	return FromRat(r, currency, mode)
+}
+
+// FromRat converts an arbitrary-precision value in major units (e.g. 12.345 dollars) to Money,
+// rounding to the currency's minor unit with the given mode.
+func FromRat(r *big.Rat, currency Currency, mode RoundingMode) (Money, error) {
+	scaled := new(big.Rat).Mul(r, scaleOf(currency))
+	return fromInt(roundRat(scaled, mode), currency)
+}
+
+// MustParse is like Parse but panics on error. Intended for constants and configuration defaults.
+func MustParse(s string, currency Currency) Money {
+	m, err := Parse(s, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MinorUnits returns the amount in minor units (e.g. cents).
+func (m Money) MinorUnits() int64 {
+	return m.amount
+}
+
+// Currency returns the currency of the amount.
+func (m Money) Currency() Currency {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// Rat returns the amount in major units as an exact rational number.
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(m.amount), scaleOf(m.Currency()).Num())
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool { return m.amount == 0 }
+
+// IsPositive reports whether the amount is greater than zero.
+func (m Money) IsPositive() bool { return m.amount > 0 }
+
+// IsNegative reports whether the amount is less than zero.
+func (m Money) IsNegative() bool { return m.amount < 0 }
+
+// SameCurrency reports whether m and o are denominated in the same currency.
+func (m Money) SameCurrency(o Money) bool {
+	return m.Currency() == o.Currency()
+}
+
+// Add returns m + o. Both amounts must share a currency, and the sum must fit in int64 minor
+// units; a result out of range panics rather than wrapping around.
+func (m Money) Add(o Money) Money {
+	m.mustMatch(o)
+	sum := m.amount + o.amount
+	if (o.amount > 0 && sum < m.amount) || (o.amount < 0 && sum > m.amount) || sum == math.MinInt64 {
+		panic(fmt.Sprintf("money: overflow adding %s and %s %s", m, o, m.Currency()))
+	}
+	return Money{amount: sum, currency: m.Currency()}
+}
+
+// Sub returns m - o. Both amounts must share a currency, and the difference must fit in int64
+// minor units; a result out of range panics rather than wrapping around.
+func (m Money) Sub(o Money) Money {
+	m.mustMatch(o)
+	diff := m.amount - o.amount
+	if (o.amount > 0 && diff > m.amount) || (o.amount < 0 && diff < m.amount) || diff == math.MinInt64 {
+		panic(fmt.Sprintf("money: overflow subtracting %s from %s %s", o, m, m.Currency()))
+	}
+	return Money{amount: diff, currency: m.Currency()}
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	if m.amount == math.MinInt64 {
+		panic(fmt.Sprintf("money: overflow negating %s %s", m, m.Currency()))
+	}
+	return Money{amount: -m.amount, currency: m.Currency()}
+}
+
+// Abs returns |m|.
+func (m Money) Abs() Money {
+	if m.amount < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// Cmp compares m and o and returns -1, 0 or +1. Both amounts must share a currency.
+func (m Money) Cmp(o Money) int {
+	m.mustMatch(o)
+	switch {
+	case m.amount < o.amount:
+		return -1
+	case m.amount > o.amount:
+		return 1
+	}
+	return 0
+}
+
+// LessThan reports whether m < o.
+func (m Money) LessThan(o Money) bool {
+	return m.Cmp(o) < 0
+}
+
+// GreaterThan reports whether m > o.
+func (m Money) GreaterThan(o Money) bool {
+	return m.Cmp(o) > 0
+}
+
+// MulRat multiplies the amount by r (e.g. a percentage or an exchange rate) and rounds the
+// result back to minor units with the given mode.
+func (m Money) MulRat(r *big.Rat, mode RoundingMode) Money {
+	scaled := new(big.Rat).Mul(new(big.Rat).SetInt64(m.amount), r)
+	out, err := fromInt(roundRat(scaled, mode), m.Currency())
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// In binds m to currency. An amount with an explicit currency must already match it; an amount
+// decoded without one is parsed from its original literal at the target currency's precision.
+// No FX conversion is ever performed here.
+func (m Money) In(currency Currency) (Money, error) {
+	if m.currency == currency {
+		return m, nil
+	}
+	if m.currency != "" {
+		return Money{}, fmt.Errorf("%w: %s amount for %s account", ErrCurrencyMismatch, m.currency, currency)
+	}
+	if m.literal != "" {
+		return Parse(m.literal, currency)
+	}
+	return Parse(m.String(), currency)
+}
+
+// String formats the amount as a plain decimal string with exactly the currency's number
+// of fractional digits, e.g. "1500.25".
+func (m Money) String() string {
+	exp := m.Currency().Exponent()
+	neg := m.amount < 0
+	digits := new(big.Int).Abs(big.NewInt(m.amount)).String()
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	if neg {
+		return "-" + digits
+	}
+	return digits
+}
+
+// Value implements driver.Valuer so Money can be written straight into DECIMAL columns.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns. The currency already set on m is kept;
+// when none is set DefaultCurrency is assumed.
+func (m *Money) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = fmt.Sprintf("%d", v)
+	case float64:
+		// Some drivers hand back DECIMAL as float64; format with enough digits to be exact
+		// for any amount that fits a DECIMAL(20,4).
+		s = big.NewFloat(v).Text('f', 4)
+	case nil:
+		*m = Zero(m.Currency())
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	parsed, err := Parse(s, m.Currency())
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+type moneyJSON struct {
+	Amount   string   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// MarshalJSON renders Money as {"amount":"1500.25","currency":"IDR"}. The amount is a string
+// so that JSON clients never round-trip it through a binary float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.String(), Currency: m.Currency()})
+}
+
+// UnmarshalJSON accepts either the object form produced by MarshalJSON or a bare JSON
+// number/string without a currency. The literal is parsed as a decimal, never as a float.
+//
+// Without a currency the precision to check against is unknown until In binds the account's
+// currency, so the literal is kept as sent (a KWD amount may have three decimals). Until then
+// the amount reads as DefaultCurrency rounded away from zero, which keeps its sign and whether
+// it is zero exact for request validation.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "null" {
+		return nil
+	}
+
+	var literal string
+	var currency Currency
+
+	switch {
+	case strings.HasPrefix(trimmed, "{"):
+		var raw struct {
+			Amount   json.RawMessage `json:"amount"`
+			Currency string          `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		if raw.Currency != "" {
+			c, err := ParseCurrency(raw.Currency)
+			if err != nil {
+				return err
+			}
+			currency = c
+		}
+		literal = strings.Trim(strings.TrimSpace(string(raw.Amount)), `"`)
+	case strings.HasPrefix(trimmed, `"`):
+		if err := json.Unmarshal(data, &literal); err != nil {
+			return err
+		}
+	default:
+		literal = trimmed
+	}
+
+	if currency != "" {
+		parsed, err := Parse(literal, currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+	r, err := parseDecimal(literal)
+	if err != nil {
+		return err
+	}
+	unbound, err := FromRat(r, DefaultCurrency, RoundUp)
+	if err != nil {
+		return err
+	}
+	*m = Money{amount: unbound.amount, literal: literal}
+	return nil
+}
+
+func (m Money) mustMatch(o Money) {
+	if !m.SameCurrency(o) {
+		panic(fmt.Sprintf("money: currency mismatch %s vs %s", m.Currency(), o.Currency()))
+	}
+}
+
+// decimalPattern matches a plain decimal with an optional exponent. big.Rat alone would also
+// accept fractions ("1/3"), base prefixes ("0x10") and underscores.
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// parseDecimal parses a plain decimal literal. Exponent notation is accepted because JSON
+// numbers may use it, but the value is always kept exact.
+func parseDecimal(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("invalid amount: empty")
+	}
+	if !decimalPattern.MatchString(s) {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return r, nil
+}
+
+func scaleOf(currency Currency) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.Exponent())), nil))
+}
+
+func fromInt(i *big.Int, currency Currency) (Money, error) {
+	if !i.IsInt64() || i.Int64() == math.MinInt64 {
+		return Money{}, fmt.Errorf("amount out of range")
+	}
+	return Money{amount: i.Int64(), currency: currency}, nil
+}
@@ -0,0 +1,262 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency Currency
+		want     int64
+		wantErr  error
+	}{
+		{"1500.25", IDR, 150025, nil},
+		{"1500", IDR, 150000, nil},
+		{"0.10", USD, 10, nil},
+		{"1.2300", USD, 123, nil}, // Trailing zeros beyond the exponent are fine
+		{"-7.5", EUR, -750, nil},
+		{"1e3", USD, 100000, nil},
+		{"1.234", KWD, 1234, nil},
+		{"100", JPY, 100, nil},
+		{"1.005", USD, 0, ErrTooPrecise},
+		{"1.5", JPY, 0, ErrTooPrecise},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.currency)+" "+tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in, tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.in, err)
+			}
+			if got.MinorUnits() != tt.want || got.Currency() != tt.currency {
+				t.Errorf("Parse(%q) = %d %s, want %d %s", tt.in, got.MinorUnits(), got.Currency(), tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{"", "abc", "1/3", "1.2.3", "0x10", "1_000", "1e"} {
+		if _, err := Parse(in, USD); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{New(150025, IDR), "1500.25"},
+		{New(5, USD), "0.05"},
+		{New(-5, USD), "-0.05"},
+		{New(1234, KWD), "1.234"},
+		{New(100, JPY), "100"},
+		{Money{}, "0.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		in   string
+		mode RoundingMode
+		want int64
+	}{
+		{"1.005", RoundHalfEven, 100},
+		{"1.015", RoundHalfEven, 102},
+		{"1.005", RoundHalfUp, 101},
+		{"-1.005", RoundHalfUp, -101},
+		{"1.009", RoundDown, 100},
+		{"-1.009", RoundDown, -100},
+		{"1.001", RoundUp, 101},
+		{"-1.001", RoundUp, -101},
+		{"-1.001", RoundFloor, -101},
+		{"1.009", RoundFloor, 100},
+		{"1.001", RoundCeiling, 101},
+		{"-1.009", RoundCeiling, -100},
+		{"1.004", RoundHalfEven, 100},
+		{"1.006", RoundHalfEven, 101},
+	}
+	for _, tt := range tests {
+		got, err := ParseRounded(tt.in, USD, tt.mode)
+		if err != nil {
+			t.Fatalf("ParseRounded(%q, %d): %v", tt.in, tt.mode, err)
+		}
+		if got.MinorUnits() != tt.want {
+			t.Errorf("ParseRounded(%q, %d) = %d, want %d", tt.in, tt.mode, got.MinorUnits(), tt.want)
+		}
+	}
+}
+
+func TestMulRat(t *testing.T) {
+	// 2.5% of 10.01 USD is 0.25025, which rounds to 0.25 half-even and 0.26 up
+	m := New(1001, USD)
+	rate := big.NewRat(25, 1000)
+	if got := m.MulRat(rate, RoundHalfEven); got.MinorUnits() != 25 {
+		t.Errorf("MulRat half-even = %s, want 0.25", got)
+	}
+	if got := m.MulRat(rate, RoundUp); got.MinorUnits() != 26 {
+		t.Errorf("MulRat up = %s, want 0.26", got)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := New(1000, USD), New(250, USD)
+	if got := a.Add(b); got.MinorUnits() != 1250 {
+		t.Errorf("Add = %s", got)
+	}
+	if got := b.Sub(a); got.MinorUnits() != -750 || !got.IsNegative() {
+		t.Errorf("Sub = %s", got)
+	}
+	if got := b.Sub(a).Abs(); got.MinorUnits() != 750 {
+		t.Errorf("Abs = %s", got)
+	}
+	if !b.LessThan(a) || !a.GreaterThan(b) || a.Cmp(a) != 0 {
+		t.Error("comparisons are inconsistent")
+	}
+}
+
+func TestCurrencyMismatchPanics(t *testing.T) {
+	usd, eur := New(100, USD), New(100, EUR)
+	for name, op := range map[string]func(){
+		"Add": func() { usd.Add(eur) },
+		"Sub": func() { usd.Sub(eur) },
+		"Cmp": func() { usd.Cmp(eur) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s with different currencies did not panic", name)
+				}
+			}()
+			op()
+		})
+	}
+}
+
+func TestOverflowPanics(t *testing.T) {
+	top, bottom := New(math.MaxInt64, IDR), New(-math.MaxInt64, IDR)
+	one := New(1, IDR)
+	for name, op := range map[string]func(){
+		"Add past the maximum":      func() { top.Add(one) },
+		"Add past the minimum":      func() { bottom.Add(one.Neg()) },
+		"Sub past the maximum":      func() { top.Sub(one.Neg()) },
+		"Sub past the minimum":      func() { bottom.Sub(one) },
+		"Sub of opposite extremes":  func() { top.Sub(bottom) },
+		"Neg of the smallest int64": func() { New(math.MinInt64, IDR).Neg() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			op()
+		})
+	}
+
+	// The extremes themselves are still reachable
+	if got := top.Sub(one).Add(one); got.MinorUnits() != math.MaxInt64 {
+		t.Errorf("MaxInt64 - 1 + 1 = %d", got.MinorUnits())
+	}
+	if got := bottom.Add(one).Sub(one); got.MinorUnits() != -math.MaxInt64 {
+		t.Errorf("-MaxInt64 + 1 - 1 = %d", got.MinorUnits())
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, m := range []Money{New(150025, IDR), New(-5, USD), New(1234, KWD), New(100, JPY)} {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Money
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got != m {
+			t.Errorf("round trip of %s gave %#v, want %#v", data, got, m)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		bindTo  Currency
+		want    Money
+		wantErr bool
+	}{
+		{`"1500.25"`, IDR, New(150025, IDR), false},
+		{`1500.25`, USD, New(150025, USD), false},
+		{`{"amount":"10.5","currency":"usd"}`, USD, New(1050, USD), false},
+		// Bare literals are only checked against the account currency's precision when bound
+		{`1.234`, KWD, New(1234, KWD), false},
+		{`"0.001"`, KWD, New(1, KWD), false},
+		{`1.234`, IDR, Money{}, true},
+		{`{"amount":"1","currency":"EUR"}`, USD, Money{}, true},
+		{`{"amount":"1.234","currency":"USD"}`, USD, Money{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var m Money
+			err := json.Unmarshal([]byte(tt.in), &m)
+			if err == nil {
+				m, err = m.In(tt.bindTo)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %s, want error", m)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m != tt.want {
+				t.Errorf("got %#v, want %#v", m, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnboundKeepsSign(t *testing.T) {
+	// Validation (gt=0) reads MinorUnits before the currency is bound
+	for in, positive := range map[string]bool{`0.001`: true, `-0.001`: false, `0`: false} {
+		var m Money
+		if err := json.Unmarshal([]byte(in), &m); err != nil {
+			t.Fatal(err)
+		}
+		if m.IsPositive() != positive {
+			t.Errorf("%s: IsPositive = %v, want %v", in, m.IsPositive(), positive)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	for _, src := range []interface{}{"12.34", []byte("12.3400"), 12.34} {
+		m := Zero(USD)
+		if err := m.Scan(src); err != nil {
+			t.Fatalf("Scan(%v): %v", src, err)
+		}
+		if m.MinorUnits() != 1234 || m.Currency() != USD {
+			t.Errorf("Scan(%v) = %#v", src, m)
+		}
+	}
+}
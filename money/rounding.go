@@ -0,0 +1,64 @@
+// go-bank-app/money/rounding.go
+package money
+
+import "math/big"
+
+// RoundingMode controls how a value with more precision than a currency allows is brought
+// back to whole minor units.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest minor unit, ties to the even neighbour (banker's rounding).
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest minor unit, ties away from zero.
+	RoundHalfUp
+	// RoundDown truncates toward zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+)
+
+// roundRat converts r to an integer using the given mode.
+func roundRat(r *big.Rat, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	negative := r.Sign() < 0
+	awayFromZero := false
+
+	switch mode {
+	case RoundDown:
+		awayFromZero = false
+	case RoundUp:
+		awayFromZero = true
+	case RoundFloor:
+		awayFromZero = negative
+	case RoundCeiling:
+		awayFromZero = !negative
+	case RoundHalfUp, RoundHalfEven:
+		// Compare 2*|rem| with the denominator to find which side of the half we are on.
+		twiceRem := new(big.Int).Abs(rem)
+		twiceRem.Lsh(twiceRem, 1)
+		switch twiceRem.Cmp(r.Denom()) {
+		case 1:
+			awayFromZero = true
+		case 0:
+			awayFromZero = mode == RoundHalfUp || quo.Bit(0) == 1
+		}
+	}
+
+	if awayFromZero {
+		if negative {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
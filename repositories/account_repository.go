@@ -3,76 +3,367 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"go-bank-app/dialect"
+	"go-bank-app/interest"
 	"go-bank-app/models"
+	"go-bank-app/money"
 )
 
 // AccountRepository defines the interface for account operations in the database.
 type AccountRepository interface {
 	CreateAccount(account *models.Account) (int64, error)
+	NextAccountSequence(branchPrefix string) (int64, error) // Allocates the next account number sequence for a branch
 	GetAccountByID(id int) (*models.Account, error)
 	GetAccountByNumber(accountNumber string) (*models.Account, error)
-	UpdateAccountBalance(tx *sql.Tx, accountID int, amount float64) error // Accepts *sql.Tx
+	AccountNumberExists(accountNumber string) (bool, error)
+	GetAccountByIDForUpdate(tx *sql.Tx, id int) (*models.Account, error)                   // Locks the row until tx ends
+	GetAccountByNumberForUpdate(tx *sql.Tx, accountNumber string) (*models.Account, error) // Locks the row until tx ends
+	UpdateAccountBalance(tx *sql.Tx, accountID int, amount money.Money) error              // Accepts *sql.Tx
+	UpdateAccountHeldAmount(tx *sql.Tx, accountID int, amount money.Money) error           // Adds amount (negative to release) to held_amount
+	UpdateAccountStatus(tx *sql.Tx, accountID int, status models.AccountStatus) error
+	UpdateInterestPlan(accountID int, plan interest.Plan) error
+	UpdateOverdraft(tx *sql.Tx, accountID int, limit money.Money, interestRate string) error // interestRate in percent a year
+	CreateStatusChange(tx *sql.Tx, change *models.AccountStatusChange) (int64, error)
+	GetStatusHistory(accountID int) ([]models.AccountStatusChange, error)
+	AddApprover(approver *models.AccountApprover) error // No-op if the user already approves for the account
+	RemoveApprover(accountID, userID int) error
+	GetApprovers(accountID int) ([]models.AccountApprover, error)
+	IsApprover(accountID, userID int) (bool, error)
 }
 
 // accountRepositoryImpl is the concrete implementation of AccountRepository.
 type accountRepositoryImpl struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect.Dialect
 }
 
-// NewAccountRepository creates a new instance of AccountRepository.
-func NewAccountRepository(db *sql.DB) AccountRepository {
-	return &accountRepositoryImpl{db: db}
+// NewAccountRepository creates a new instance of AccountRepository that writes its queries for d.
+func NewAccountRepository(db *sql.DB, d dialect.Dialect) AccountRepository {
+	return &accountRepositoryImpl{db: db, dialect: d}
+}
+
+const selectAccountColumns = "SELECT id, user_id, account_number, type, currency, balance, held_amount, status, interest_rate, day_count, overdraft_limit, overdraft_interest_rate, created_at, updated_at FROM accounts"
+
+// scanAccount reads a single account row produced by a query built on selectAccountColumns.
+func scanAccount(row rowScanner) (*models.Account, error) {
+	var account models.Account
+	var balance, held, overdraftLimit string
+	err := row.Scan(&account.ID, &account.UserID, &account.AccountNumber, &account.Type, &account.Currency, &balance, &held, &account.Status,
+		&account.InterestRate, &account.DayCount, &overdraftLimit, &account.OverdraftInterestRate, &account.CreatedAt, &account.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if account.Balance, err = money.Parse(balance, account.Currency); err != nil {
+		return nil, fmt.Errorf("invalid balance for account %d: %w", account.ID, err)
+	}
+	if account.HeldAmount, err = money.Parse(held, account.Currency); err != nil {
+		return nil, fmt.Errorf("invalid held amount for account %d: %w", account.ID, err)
+	}
+	if account.OverdraftLimit, err = money.Parse(overdraftLimit, account.Currency); err != nil {
+		return nil, fmt.Errorf("invalid overdraft limit for account %d: %w", account.ID, err)
+	}
+	account.AvailableBalance = account.Balance.Sub(account.HeldAmount).Add(account.OverdraftLimit)
+	account.OverdraftUsed = money.Zero(account.Currency)
+	if account.Balance.IsNegative() {
+		account.OverdraftUsed = account.Balance.Neg()
+	}
+	// Normalize the DECIMAL rates ("2.500000") to the form clients send ("2.5")
+	plan, err := account.InterestPlan()
+	if err != nil {
+		return nil, fmt.Errorf("invalid interest plan for account %d: %w", account.ID, err)
+	}
+	account.InterestRate = plan.RatePercent()
+	overdraftPlan, err := account.OverdraftInterestPlan()
+	if err != nil {
+		return nil, fmt.Errorf("invalid overdraft interest rate for account %d: %w", account.ID, err)
+	}
+	account.OverdraftInterestRate = overdraftPlan.RatePercent()
+	return &account, nil
 }
 
 // CreateAccount inserts a new account into the database.
 func (r *accountRepositoryImpl) CreateAccount(account *models.Account) (int64, error) {
-	query := "INSERT INTO accounts (user_id, account_number, balance) VALUES (?, ?, ?)"
-	result, err := r.db.Exec(query, account.UserID, account.AccountNumber, account.Balance)
-	if err != nil {
-		return 0, fmt.Errorf("failed to create account in database: %w", err)
+	if account.Status == "" {
+		account.Status = models.AccountActive
+	}
+	if account.Currency == "" {
+		account.Currency = account.Balance.Currency()
+	}
+	if account.Type == "" {
+		account.Type = models.AccountTypeChecking
 	}
-	id, err := result.LastInsertId()
+	if account.InterestRate == "" {
+		account.InterestRate = "0"
+	}
+	if account.DayCount == "" {
+		account.DayCount = interest.ACT365
+	}
+	if account.OverdraftInterestRate == "" {
+		account.OverdraftInterestRate = "0"
+	}
+	query := `INSERT INTO accounts (user_id, account_number, type, currency, balance, status, interest_rate, day_count, overdraft_interest_rate)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.dialect.InsertID(r.db, query, account.UserID, account.AccountNumber, account.Type, account.Currency, account.Balance, account.Status,
+		account.InterestRate, account.DayCount, account.OverdraftInterestRate)
 	if err != nil {
-		return 0, fmt.Errorf("failed to retrieve new account ID: %w", err)
+		if r.dialect.IsDuplicateKey(err) {
+			return 0, ErrDuplicateAccountNumber
+		}
+		return 0, fmt.Errorf("failed to create account in database: %w", err)
 	}
 	return id, nil
 }
 
 // GetAccountByID retrieves an account from the database using its ID.
 func (r *accountRepositoryImpl) GetAccountByID(id int) (*models.Account, error) {
-	var account models.Account
-	query := "SELECT id, user_id, account_number, balance, created_at, updated_at FROM accounts WHERE id = ?"
-	err := r.db.QueryRow(query, id).
-		Scan(&account.ID, &account.UserID, &account.AccountNumber, &account.Balance, &account.CreatedAt, &account.UpdatedAt)
+	account, err := scanAccount(r.db.QueryRow(r.dialect.Rebind(selectAccountColumns+" WHERE id = ?"), id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("account not found")
+			return nil, ErrAccountNotFound
 		}
 		return nil, fmt.Errorf("failed to retrieve account by ID: %w", err)
 	}
-	return &account, nil
+	return account, nil
+}
+
+// AccountNumberExists reports whether an account, open or closed, has the given account number.
+func (r *accountRepositoryImpl) AccountNumberExists(accountNumber string) (bool, error) {
+	var n int
+	err := r.db.QueryRow(r.dialect.Rebind("SELECT COUNT(*) FROM accounts WHERE account_number = ?"), accountNumber).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up account number: %w", err)
+	}
+	return n > 0, nil
 }
 
 // GetAccountByNumber retrieves an account from the database using its account number.
 func (r *accountRepositoryImpl) GetAccountByNumber(accountNumber string) (*models.Account, error) {
-	var account models.Account
-	query := "SELECT id, user_id, account_number, balance, created_at, updated_at FROM accounts WHERE account_number = ?"
-	err := r.db.QueryRow(query, accountNumber).
-		Scan(&account.ID, &account.UserID, &account.AccountNumber, &account.Balance, &account.CreatedAt, &account.UpdatedAt)
+	account, err := scanAccount(r.db.QueryRow(r.dialect.Rebind(selectAccountColumns+" WHERE account_number = ?"), accountNumber))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("account not found")
+			return nil, ErrAccountNotFound
 		}
 		return nil, fmt.Errorf("failed to retrieve account by number: %w", err)
 	}
-	return &account, nil
+	return account, nil
+}
+
+// GetAccountByIDForUpdate reads an account inside tx with SELECT ... FOR UPDATE (or under SQLite's
+// database write lock), so no other transaction can change its balance until tx commits or rolls back.
+func (r *accountRepositoryImpl) GetAccountByIDForUpdate(tx *sql.Tx, id int) (*models.Account, error) {
+	account, err := scanAccount(tx.QueryRow(r.dialect.Rebind(selectAccountColumns+" WHERE id = ?"+r.dialect.ForUpdate()), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to lock account by ID: %w", err)
+	}
+	return account, nil
 }
 
-// UpdateAccountBalance updates the balance of an account within the given transaction.
-func (r *accountRepositoryImpl) UpdateAccountBalance(tx *sql.Tx, accountID int, amount float64) error {
-	_, err := tx.Exec("UPDATE accounts SET balance = balance + ? WHERE id = ?", amount, accountID)
+// GetAccountByNumberForUpdate is the account-number variant of GetAccountByIDForUpdate.
+// Callers locking more than one account should lock by ID in ascending order instead.
+func (r *accountRepositoryImpl) GetAccountByNumberForUpdate(tx *sql.Tx, accountNumber string) (*models.Account, error) {
+	account, err := scanAccount(tx.QueryRow(r.dialect.Rebind(selectAccountColumns+" WHERE account_number = ?"+r.dialect.ForUpdate()), accountNumber))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to lock account by number: %w", err)
+	}
+	return account, nil
+}
+
+// UpdateAccountBalance adds amount (negative for debits) to the balance of an account within the given transaction.
+// The amount is sent as an exact decimal string so the DECIMAL column never sees a binary float.
+// ROUND to the column's scale changes nothing on MySQL and PostgreSQL; SQLite stores DECIMAL as a
+// binary float, and without it the residue of each addition would pile up in the balance.
+func (r *accountRepositoryImpl) UpdateAccountBalance(tx *sql.Tx, accountID int, amount money.Money) error {
+	_, err := tx.Exec(r.dialect.Rebind("UPDATE accounts SET balance = ROUND(balance + ?, 4) WHERE id = ?"), amount, accountID)
 	if err != nil {
 		return fmt.Errorf("failed to update account balance: %w", err)
 	}
 	return nil
 }
+
+// UpdateAccountHeldAmount adds amount (negative when a hold ends) to the cached total of active
+// holds. Like the balance, it only changes while the account row is locked, and is rounded the
+// same way.
+func (r *accountRepositoryImpl) UpdateAccountHeldAmount(tx *sql.Tx, accountID int, amount money.Money) error {
+	_, err := tx.Exec(r.dialect.Rebind("UPDATE accounts SET held_amount = ROUND(held_amount + ?, 4) WHERE id = ?"), amount, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to update held amount: %w", err)
+	}
+	return nil
+}
+
+// UpdateAccountStatus sets the lifecycle status of an account within the given transaction.
+// Transition rules are enforced by the service layer (see models.AccountStatus.CanTransitionTo).
+func (r *accountRepositoryImpl) UpdateAccountStatus(tx *sql.Tx, accountID int, status models.AccountStatus) error {
+	_, err := tx.Exec(r.dialect.Rebind("UPDATE accounts SET status = ? WHERE id = ?"), status, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to update account status: %w", err)
+	}
+	return nil
+}
+
+// UpdateInterestPlan sets the annual rate and day-count convention an account accrues interest under.
+func (r *accountRepositoryImpl) UpdateInterestPlan(accountID int, plan interest.Plan) error {
+	_, err := r.db.Exec(r.dialect.Rebind("UPDATE accounts SET interest_rate = ?, day_count = ? WHERE id = ?"), plan.RatePercent(), plan.DayCount, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to update interest plan: %w", err)
+	}
+	return nil
+}
+
+// UpdateOverdraft sets the approved overdraft limit of an account and the annual debit interest
+// rate charged on a negative balance, within the given transaction.
+func (r *accountRepositoryImpl) UpdateOverdraft(tx *sql.Tx, accountID int, limit money.Money, interestRate string) error {
+	_, err := tx.Exec(r.dialect.Rebind("UPDATE accounts SET overdraft_limit = ?, overdraft_interest_rate = ? WHERE id = ?"), limit, interestRate, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to update overdraft: %w", err)
+	}
+	return nil
+}
+
+// CreateStatusChange records a status transition with its reason and actor.
+func (r *accountRepositoryImpl) CreateStatusChange(tx *sql.Tx, change *models.AccountStatusChange) (int64, error) {
+	query := "INSERT INTO account_status_history (account_id, from_status, to_status, reason, actor_user_id) VALUES (?, ?, ?, ?, ?)"
+	id, err := r.dialect.InsertID(tx, query, change.AccountID, change.FromStatus, change.ToStatus, change.Reason, change.ActorUserID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record account status change: %w", err)
+	}
+	return id, nil
+}
+
+// GetStatusHistory retrieves every status change of an account, oldest first.
+func (r *accountRepositoryImpl) GetStatusHistory(accountID int) ([]models.AccountStatusChange, error) {
+	var history []models.AccountStatusChange
+	query := `SELECT id, account_id, from_status, to_status, reason, actor_user_id, created_at
+		FROM account_status_history WHERE account_id = ? ORDER BY created_at, id`
+	rows, err := r.db.Query(r.dialect.Rebind(query), accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account status history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var h models.AccountStatusChange
+		if err := rows.Scan(&h.ID, &h.AccountID, &h.FromStatus, &h.ToStatus, &h.Reason, &h.ActorUserID, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account status history row: %w", err)
+		}
+		history = append(history, h)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating account status history rows: %w", err)
+	}
+	return history, nil
+}
+
+// AddApprover allows a user to approve transfers from an account.
+func (r *accountRepositoryImpl) AddApprover(approver *models.AccountApprover) error {
+	_, err := r.db.Exec(r.dialect.Rebind("INSERT INTO account_approvers (account_id, user_id, added_by) VALUES (?, ?, ?)"),
+		approver.AccountID, approver.UserID, approver.AddedBy)
+	if r.dialect.IsDuplicateKey(err) {
+		return nil // Already an approver
+	}
+	if r.dialect.IsForeignKeyViolation(err) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add account approver: %w", err)
+	}
+	return nil
+}
+
+// RemoveApprover revokes a user's right to approve transfers from an account.
+func (r *accountRepositoryImpl) RemoveApprover(accountID, userID int) error {
+	result, err := r.db.Exec(r.dialect.Rebind("DELETE FROM account_approvers WHERE account_id = ? AND user_id = ?"), accountID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove account approver: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrApproverNotFound
+	}
+	return nil
+}
+
+// GetApprovers retrieves every approver of an account, oldest first.
+func (r *accountRepositoryImpl) GetApprovers(accountID int) ([]models.AccountApprover, error) {
+	var approvers []models.AccountApprover
+	query := "SELECT account_id, user_id, added_by, created_at FROM account_approvers WHERE account_id = ? ORDER BY created_at, user_id"
+	rows, err := r.db.Query(r.dialect.Rebind(query), accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account approvers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a models.AccountApprover
+		if err := rows.Scan(&a.AccountID, &a.UserID, &a.AddedBy, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account approver row: %w", err)
+		}
+		approvers = append(approvers, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating account approver rows: %w", err)
+	}
+	return approvers, nil
+}
+
+// IsApprover reports whether userID may approve transfers from accountID.
+func (r *accountRepositoryImpl) IsApprover(accountID, userID int) (bool, error) {
+	var n int
+	err := r.db.QueryRow(r.dialect.Rebind("SELECT COUNT(*) FROM account_approvers WHERE account_id = ? AND user_id = ?"), accountID, userID).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to check account approver: %w", err)
+	}
+	return n > 0, nil
+}
+
+// NextAccountSequence atomically allocates the next sequence number for branchPrefix, starting at 1.
+// The counter row is locked only for this short transaction, so concurrent account openings get
+// distinct numbers without holding a lock across the account insert. A failed insert leaves a gap,
+// which is harmless.
+func (r *accountRepositoryImpl) NextAccountSequence(branchPrefix string) (int64, error) {
+	seq, err := r.nextAccountSequence(branchPrefix)
+	if r.dialect.IsDuplicateKey(err) || r.dialect.IsRetryable(err) {
+		// Another opening created the branch's counter row at the same time; it exists now
+		seq, err = r.nextAccountSequence(branchPrefix)
+	}
+	return seq, err
+}
+
+// nextAccountSequence increments the counter row of branchPrefix, creating it on the branch's first
+// account, and reads the new value back in the same transaction. It sticks to plain UPDATE and
+// INSERT because Dialect.OnConflict can only copy the inserted values, not increment a column.
+func (r *accountRepositoryImpl) nextAccountSequence(branchPrefix string) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin sequence transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(r.dialect.Rebind("UPDATE account_number_sequences SET last_value = last_value + 1 WHERE branch_prefix = ?"), branchPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate account number sequence: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("failed to allocate account number sequence: %w", err)
+	} else if n == 0 {
+		_, err = tx.Exec(r.dialect.Rebind("INSERT INTO account_number_sequences (branch_prefix, last_value) VALUES (?, 1)"), branchPrefix)
+		if err != nil {
+			return 0, fmt.Errorf("failed to allocate account number sequence: %w", err)
+		}
+	}
+	var seq int64
+	if err := tx.QueryRow(r.dialect.Rebind("SELECT last_value FROM account_number_sequences WHERE branch_prefix = ?"), branchPrefix).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("failed to read account number sequence: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit account number sequence: %w", err)
+	}
+	return seq, nil
+}
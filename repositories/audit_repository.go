@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+)
+
+// AuditRepository defines the interface for the audit log in the database.
+type AuditRepository interface {
+	CreateAuditLog(entry *models.AuditLog) (int64, error)
+}
+
+// auditRepositoryImpl is the concrete implementation of AuditRepository.
+type auditRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewAuditRepository creates a new instance of AuditRepository that writes its queries for d.
+func NewAuditRepository(db *sql.DB, d dialect.Dialect) AuditRepository {
+	return &auditRepositoryImpl{db: db, dialect: d}
+}
+
+// CreateAuditLog appends an entry to the audit log.
+func (r *auditRepositoryImpl) CreateAuditLog(entry *models.AuditLog) (int64, error) {
+	query := `INSERT INTO audit_logs (actor_user_id, actor_role, action, method, path, status_code, client_ip)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.dialect.InsertID(r.db, query, entry.ActorUserID, entry.ActorRole, entry.Action, entry.Method, entry.Path, entry.StatusCode, entry.ClientIP)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import "errors"
+
+// Errors returned when a lookup finds no row or an insert hits a unique key (as reported by
+// dialect.Dialect.IsDuplicateKey). The services re-export them (see services/errors.go), so callers
+// above the repositories match them with errors.Is instead of comparing messages.
+var (
+	ErrUserNotFound           = errors.New("user not found")
+	ErrAccountNotFound        = errors.New("account not found")
+	ErrTransactionNotFound    = errors.New("transaction not found")
+	ErrTransferNotFound       = errors.New("transfer not found")
+	ErrHoldNotFound           = errors.New("hold not found")
+	ErrStandingOrderNotFound  = errors.New("standing order not found")
+	ErrApproverNotFound       = errors.New("approver not found")
+	ErrRefreshTokenNotFound   = errors.New("refresh token not found")
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+	ErrDuplicateEmail         = errors.New("email already registered")
+	ErrDuplicateAccountNumber = errors.New("account number already exists")
+)
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"go-bank-app/dialect"
+	"go-bank-app/fx"
+	"go-bank-app/money"
+)
+
+// FXRateRepository is an fx.RateProvider backed by the fx_rates table. Rates are loaded into the
+// table by treasury (or a feed importer); the latest row per pair wins.
+type FXRateRepository interface {
+	fx.RateProvider
+}
+
+// fxRateRepositoryImpl is the concrete implementation of FXRateRepository.
+type fxRateRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewFXRateRepository creates a new instance of FXRateRepository that writes its queries for d.
+func NewFXRateRepository(db *sql.DB, d dialect.Dialect) FXRateRepository {
+	return &fxRateRepositoryImpl{db: db, dialect: d}
+}
+
+// GetRate returns the most recent base/quote rate, deriving it from the quote/base row if only
+// the opposite direction is stored.
+func (r *fxRateRepositoryImpl) GetRate(base, quote money.Currency) (*fx.Rate, error) {
+	query := `SELECT base_currency, quote_currency, rate, source, as_of FROM fx_rates
+		WHERE (base_currency = ? AND quote_currency = ?) OR (base_currency = ? AND quote_currency = ?)
+		ORDER BY as_of DESC, id DESC LIMIT 1`
+	var rate fx.Rate
+	var value string
+	err := r.db.QueryRow(r.dialect.Rebind(query), base, quote, quote, base).Scan(&rate.Base, &rate.Quote, &value, &rate.Source, &rate.AsOf)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s/%s: %w", base, quote, fx.ErrRateNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch exchange rate: %w", err)
+	}
+	if rate.Value, err = fx.ParseRate(value); err != nil {
+		return nil, err
+	}
+	if rate.Base != base {
+		return rate.Invert(), nil
+	}
+	return &rate, nil
+}
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
+
+// HoldRepository defines the interface for funds hold operations in the database.
+// accounts.held_amount caches the sum of active holds; services keep it in step (see services/holds.go).
+type HoldRepository interface {
+	CreateHold(tx *sql.Tx, hold *models.Hold) (int64, error)
+	GetHoldByID(id int) (*models.Hold, error)
+	GetHoldByIDForUpdate(tx *sql.Tx, id int) (*models.Hold, error)           // Locks the row until tx ends
+	GetHoldsByAccountID(accountID int, status string) ([]models.Hold, error) // Empty status returns every hold
+	UpdateHold(tx *sql.Tx, hold *models.Hold) error                          // Stores status, captured amount and capture transaction
+	GetExpiredHoldIDs(now time.Time, limit int) ([]int, error)
+}
+
+// holdRepositoryImpl is the concrete implementation of HoldRepository.
+type holdRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewHoldRepository creates a new instance of HoldRepository that writes its queries for d.
+func NewHoldRepository(db *sql.DB, d dialect.Dialect) HoldRepository {
+	return &holdRepositoryImpl{db: db, dialect: d}
+}
+
+const selectHoldColumns = `SELECT h.id, h.account_id, h.type, a.currency, h.amount, h.captured_amount, h.reason, h.reference,
+		h.status, h.expires_at, h.transaction_id, h.created_by, h.created_at, h.updated_at
+	FROM holds h
+	JOIN accounts a ON a.id = h.account_id`
+
+func scanHold(row rowScanner) (*models.Hold, error) {
+	var h models.Hold
+	var currency money.Currency
+	var amount string
+	var capturedAmount sql.NullString
+	var expiresAt sql.NullTime
+	var transactionID sql.NullInt64
+	err := row.Scan(&h.ID, &h.AccountID, &h.Type, &currency, &amount, &capturedAmount, &h.Reason, &h.Reference,
+		&h.Status, &expiresAt, &transactionID, &h.CreatedBy, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if h.Amount, err = money.Parse(amount, currency); err != nil {
+		return nil, fmt.Errorf("invalid amount for hold %d: %w", h.ID, err)
+	}
+	if capturedAmount.Valid {
+		captured, err := money.Parse(capturedAmount.String, currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid captured amount for hold %d: %w", h.ID, err)
+		}
+		h.CapturedAmount = &captured
+	}
+	h.ExpiresAt = nullTimePtr(expiresAt)
+	h.TransactionID = nullIntPtr(transactionID)
+	return &h, nil
+}
+
+// CreateHold inserts a new hold within the given transaction.
+func (r *holdRepositoryImpl) CreateHold(tx *sql.Tx, hold *models.Hold) (int64, error) {
+	query := `INSERT INTO holds (account_id, type, amount, reason, reference, status, expires_at, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.dialect.InsertID(tx, query, hold.AccountID, hold.Type, hold.Amount, hold.Reason, hold.Reference, hold.Status,
+		timePtrArg(hold.ExpiresAt), hold.CreatedBy)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create hold in database: %w", err)
+	}
+	return id, nil
+}
+
+// GetHoldByID retrieves a hold using its ID.
+func (r *holdRepositoryImpl) GetHoldByID(id int) (*models.Hold, error) {
+	hold, err := scanHold(r.db.QueryRow(r.dialect.Rebind(selectHoldColumns+" WHERE h.id = ?"), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve hold by ID: %w", err)
+	}
+	return hold, nil
+}
+
+// GetHoldByIDForUpdate reads a hold inside tx with SELECT ... FOR UPDATE. Callers lock the
+// account row first, matching the order used everywhere holds change.
+func (r *holdRepositoryImpl) GetHoldByIDForUpdate(tx *sql.Tx, id int) (*models.Hold, error) {
+	hold, err := scanHold(tx.QueryRow(r.dialect.Rebind(selectHoldColumns+" WHERE h.id = ?"+r.dialect.ForUpdate()), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("failed to lock hold: %w", err)
+	}
+	return hold, nil
+}
+
+// GetHoldsByAccountID retrieves the holds of an account, newest first, optionally filtered by status.
+func (r *holdRepositoryImpl) GetHoldsByAccountID(accountID int, status string) ([]models.Hold, error) {
+	var where whereBuilder
+	where.add("h.account_id = ?", accountID)
+	if status != "" {
+		where.add("h.status = ?", status)
+	}
+	rows, err := r.db.Query(r.dialect.Rebind(selectHoldColumns+where.sql()+" ORDER BY h.created_at DESC, h.id DESC"), where.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holds: %w", err)
+	}
+	defer rows.Close()
+
+	var holds []models.Hold
+	for rows.Next() {
+		h, err := scanHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hold row: %w", err)
+		}
+		holds = append(holds, *h)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating hold rows: %w", err)
+	}
+	return holds, nil
+}
+
+// UpdateHold stores the outcome of a capture, release or expiry within the given transaction.
+func (r *holdRepositoryImpl) UpdateHold(tx *sql.Tx, hold *models.Hold) error {
+	var capturedAmount interface{}
+	if hold.CapturedAmount != nil {
+		capturedAmount = *hold.CapturedAmount
+	}
+	_, err := tx.Exec(r.dialect.Rebind("UPDATE holds SET status = ?, captured_amount = ?, transaction_id = ? WHERE id = ?"),
+		hold.Status, capturedAmount, intPtrArg(hold.TransactionID), hold.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update hold: %w", err)
+	}
+	return nil
+}
+
+// GetExpiredHoldIDs returns up to limit active holds whose expiry has passed.
+func (r *holdRepositoryImpl) GetExpiredHoldIDs(now time.Time, limit int) ([]int, error) {
+	query := "SELECT id FROM holds WHERE status = ? AND expires_at < ? ORDER BY expires_at, id LIMIT ?"
+	rows, err := r.db.Query(r.dialect.Rebind(query), models.HoldActive, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch expired holds: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan expired hold row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating expired holds: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+	"time"
+)
+
+// IdempotencyRepository defines the interface for storing Idempotency-Key records in the database.
+type IdempotencyRepository interface {
+	Reserve(record *models.IdempotencyRecord, lockTimeout time.Duration) (bool, error) // false if the key is held or finished
+	GetByKey(userID int, key string) (*models.IdempotencyRecord, error)
+	Finish(userID int, key string, status string, responseCode int, responseBody []byte, ttl time.Duration) error
+	Delete(userID int, key string) error
+	DeleteExpired() (int64, error)
+}
+
+// idempotencyRepositoryImpl is the concrete implementation of IdempotencyRepository.
+type idempotencyRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository that writes its queries for d.
+func NewIdempotencyRepository(db *sql.DB, d dialect.Dialect) IdempotencyRepository {
+	return &idempotencyRepositoryImpl{db: db, dialect: d}
+}
+
+// Reserve inserts an in-progress record for the key. The unique (user_id, idempotency_key)
+// index makes this the single point where concurrent duplicates are told apart: exactly one
+// insert wins, every other one gets false. An existing key is taken over instead if it has
+// expired, or if it is still in progress for the same request but its lock is older than
+// lockTimeout, i.e. the request holding it died before finishing.
+func (r *idempotencyRepositoryImpl) Reserve(record *models.IdempotencyRecord, lockTimeout time.Duration) (bool, error) {
+	query := "INSERT INTO idempotency_keys (user_id, idempotency_key, method, path, request_hash, status, locked_at) VALUES (?, ?, ?, ?, ?, ?, " + r.dialect.Now() + ")"
+	id, err := r.dialect.InsertID(r.db, query, record.UserID, record.Key, record.Method, record.Path, record.RequestHash, models.IdempotencyInProgress)
+	if err == nil {
+		record.ID = int(id)
+		record.Status = models.IdempotencyInProgress
+		return true, nil
+	}
+	if !r.dialect.IsDuplicateKey(err) {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	// locked_at changes on every takeover, so RowsAffected reliably tells whether this one won
+	result, err := r.db.Exec(r.dialect.Rebind(`UPDATE idempotency_keys
+		SET method = ?, path = ?, request_hash = ?, status = ?, response_code = NULL, response_body = NULL,
+			locked_at = `+r.dialect.Now()+`, expires_at = NULL
+		WHERE user_id = ? AND idempotency_key = ? AND (expires_at < `+r.dialect.Now()+`
+			OR (status = ? AND request_hash = ? AND (locked_at IS NULL OR locked_at < `+r.dialect.NowPlus()+`)))`),
+		record.Method, record.Path, record.RequestHash, models.IdempotencyInProgress,
+		record.UserID, record.Key, models.IdempotencyInProgress, record.RequestHash, -lockTimeout.Microseconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to take over idempotency key: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to take over idempotency key: %w", err)
+	}
+	if n != 1 {
+		return false, nil
+	}
+	record.Status = models.IdempotencyInProgress
+	return true, nil
+}
+
+// GetByKey retrieves the record stored for a user's idempotency key.
+func (r *idempotencyRepositoryImpl) GetByKey(userID int, key string) (*models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	var responseCode sql.NullInt64
+	query := `SELECT id, user_id, idempotency_key, method, path, request_hash, status, response_code, response_body, created_at, updated_at
+		FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ?`
+	err := r.db.QueryRow(r.dialect.Rebind(query), userID, key).
+		Scan(&record.ID, &record.UserID, &record.Key, &record.Method, &record.Path, &record.RequestHash, &record.Status,
+			&responseCode, &record.ResponseBody, &record.CreatedAt, &record.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrIdempotencyKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve idempotency key: %w", err)
+	}
+	record.ResponseCode = int(responseCode.Int64)
+	return &record, nil
+}
+
+// Finish stores the final status and response of an in-progress key. The record is kept for ttl
+// so later replays can return it, then DeleteExpired removes it.
+func (r *idempotencyRepositoryImpl) Finish(userID int, key string, status string, responseCode int, responseBody []byte, ttl time.Duration) error {
+	query := "UPDATE idempotency_keys SET status = ?, response_code = ?, response_body = ?, expires_at = " + r.dialect.NowPlus() +
+		" WHERE user_id = ? AND idempotency_key = ?"
+	_, err := r.db.Exec(r.dialect.Rebind(query), status, responseCode, responseBody, ttl.Microseconds(), userID, key)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a key, e.g. when the request failed in a way the client should be allowed to retry.
+func (r *idempotencyRepositoryImpl) Delete(userID int, key string) error {
+	_, err := r.db.Exec(r.dialect.Rebind("DELETE FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ?"), userID, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes every finished key whose retention has passed and returns how many.
+func (r *idempotencyRepositoryImpl) DeleteExpired() (int64, error) {
+	result, err := r.db.Exec("DELETE FROM idempotency_keys WHERE expires_at < " + r.dialect.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,306 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
+
+// InterestRepository defines the interface for daily interest accruals, monthly capitalizations
+// and the record of which dates the interest job has completed.
+type InterestRepository interface {
+	GetAccruingAccountIDs(day time.Time) ([]int, error)          // Open interest-bearing or overdrawn accounts that existed at the end of day
+	CreateAccrual(accrual *models.InterestAccrual) (bool, error) // False if the account already has an accrual for that date
+	GetAccruals(accountID int, from, to time.Time) ([]models.InterestAccrual, error)
+	GetUncapitalizedAccountIDs(periodStart, periodEnd time.Time) ([]int, error)
+	SumUncapitalizedAccruals(tx *sql.Tx, accountID int, periodStart, periodEnd time.Time) (credit, debit *big.Rat, count int, err error) // Locks the accrual rows until tx ends
+	CreateCapitalization(tx *sql.Tx, c *models.InterestCapitalization) (bool, error)                                                     // False if the period is already capitalized
+	MarkAccrualsCapitalized(tx *sql.Tx, accountID int, periodStart, periodEnd time.Time, capitalizationID int) error
+	GetOldestUncapitalizedAccrualDate(tx *sql.Tx, accountID int) (*time.Time, error) // Nil if every accrual is capitalized
+	GetCapitalizations(accountID int) ([]models.InterestCapitalization, error)
+	GetLastCompletedRunDate() (*time.Time, error) // Nil if the job has never completed a date
+	MarkRunCompleted(result *models.InterestRunResult) error
+}
+
+// interestRepositoryImpl is the concrete implementation of InterestRepository.
+type interestRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewInterestRepository creates a new instance of InterestRepository that writes its queries for d.
+func NewInterestRepository(db *sql.DB, d dialect.Dialect) InterestRepository {
+	return &interestRepositoryImpl{db: db, dialect: d}
+}
+
+// GetAccruingAccountIDs lists the accounts that were opened before the end of day, are not closed,
+// and either earn interest or may be overdrawn. An overdrawn account whose facility was withdrawn
+// since keeps accruing debit interest until it is back in credit.
+func (r *interestRepositoryImpl) GetAccruingAccountIDs(day time.Time) ([]int, error) {
+	query := `SELECT id FROM accounts
+		WHERE (interest_rate > 0 OR overdraft_limit > 0 OR balance < 0) AND status <> ? AND created_at < ?
+		ORDER BY id`
+	return r.queryIDs(query, models.AccountClosed, day.AddDate(0, 0, 1))
+}
+
+// CreateAccrual stores the interest of one account for one day. The (account_id, accrual_date)
+// primary key makes this idempotent: re-running a date leaves the first accrual in place.
+func (r *interestRepositoryImpl) CreateAccrual(accrual *models.InterestAccrual) (bool, error) {
+	query := `INSERT INTO interest_accruals (account_id, accrual_date, end_of_day_balance, interest_rate, day_count, amount)
+		VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.Exec(r.dialect.Rebind(query), accrual.AccountID, dateArg(accrual.AccrualDate), accrual.EndOfDayBalance,
+		accrual.InterestRate, accrual.DayCount, accrual.Amount)
+	if err != nil {
+		if r.dialect.IsDuplicateKey(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to record interest accrual: %w", err)
+	}
+	return true, nil
+}
+
+// GetAccruals retrieves the accruals of an account between from and to inclusive, oldest first.
+func (r *interestRepositoryImpl) GetAccruals(accountID int, from, to time.Time) ([]models.InterestAccrual, error) {
+	var accruals []models.InterestAccrual
+	query := `SELECT ia.account_id, ia.accrual_date, a.currency, ia.end_of_day_balance, ia.interest_rate, ia.day_count,
+			ia.amount, ia.capitalization_id, ia.created_at
+		FROM interest_accruals ia
+		JOIN accounts a ON a.id = ia.account_id
+		WHERE ia.account_id = ? AND ia.accrual_date BETWEEN ? AND ?
+		ORDER BY ia.accrual_date`
+	rows, err := r.db.Query(r.dialect.Rebind(query), accountID, dateArg(from), dateArg(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch interest accruals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a models.InterestAccrual
+		var currency money.Currency
+		var balance, rate, amount string
+		var capitalizationID sql.NullInt64
+		if err := rows.Scan(&a.AccountID, &a.AccrualDate, &currency, &balance, &rate, &a.DayCount,
+			&amount, &capitalizationID, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan interest accrual row: %w", err)
+		}
+		a.AccrualDate = localDate(a.AccrualDate)
+		if a.EndOfDayBalance, err = money.Parse(balance, currency); err != nil {
+			return nil, fmt.Errorf("invalid end-of-day balance for account %d: %w", a.AccountID, err)
+		}
+		a.InterestRate = trimDecimal(rate)
+		a.Amount = trimDecimal(amount)
+		a.CapitalizationID = nullIntPtr(capitalizationID)
+		accruals = append(accruals, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating interest accrual rows: %w", err)
+	}
+	return accruals, nil
+}
+
+// GetUncapitalizedAccountIDs lists the open accounts with accruals in the period that have not
+// been capitalized yet. A closed account is left out: closing it already capitalized everything it
+// had accrued.
+func (r *interestRepositoryImpl) GetUncapitalizedAccountIDs(periodStart, periodEnd time.Time) ([]int, error) {
+	query := `SELECT DISTINCT ia.account_id
+		FROM interest_accruals ia
+		JOIN accounts a ON a.id = ia.account_id
+		WHERE ia.accrual_date BETWEEN ? AND ? AND ia.capitalization_id IS NULL AND a.status <> ?
+		ORDER BY ia.account_id`
+	return r.queryIDs(query, dateArg(periodStart), dateArg(periodEnd), models.AccountClosed)
+}
+
+// SumUncapitalizedAccruals totals the uncapitalized accruals of an account in the period, exactly,
+// and counts them. Credit interest (positive accruals) and debit interest (negative accruals, returned
+// as a positive total) are summed separately because they are booked separately. The rows stay
+// locked until tx ends, so two runs cannot capitalize them both.
+func (r *interestRepositoryImpl) SumUncapitalizedAccruals(tx *sql.Tx, accountID int, periodStart, periodEnd time.Time) (*big.Rat, *big.Rat, int, error) {
+	query := `SELECT amount FROM interest_accruals
+		WHERE account_id = ? AND accrual_date BETWEEN ? AND ? AND capitalization_id IS NULL` + r.dialect.ForUpdate()
+	rows, err := tx.Query(r.dialect.Rebind(query), accountID, dateArg(periodStart), dateArg(periodEnd))
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to fetch interest accruals: %w", err)
+	}
+	defer rows.Close()
+
+	credit, debit, count := new(big.Rat), new(big.Rat), 0
+	for rows.Next() {
+		var amount string
+		if err := rows.Scan(&amount); err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to scan interest accrual row: %w", err)
+		}
+		v, ok := new(big.Rat).SetString(amount)
+		if !ok {
+			return nil, nil, 0, fmt.Errorf("invalid interest accrual %q for account %d", amount, accountID)
+		}
+		if v.Sign() < 0 {
+			debit.Sub(debit, v)
+		} else {
+			credit.Add(credit, v)
+		}
+		count++
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, 0, fmt.Errorf("error occurred while iterating interest accrual rows: %w", err)
+	}
+	return credit, debit, count, nil
+}
+
+// CreateCapitalization records the interest paid to an account for one month. The unique
+// (account_id, period_start) key guarantees a month is never paid twice.
+func (r *interestRepositoryImpl) CreateCapitalization(tx *sql.Tx, c *models.InterestCapitalization) (bool, error) {
+	query := `INSERT INTO interest_capitalizations (account_id, period_start, period_end, accrued, amount, transaction_id,
+			debit_accrued, debit_amount, debit_transaction_id, journal_entry_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.dialect.InsertID(tx, query, c.AccountID, dateArg(c.PeriodStart), dateArg(c.PeriodEnd), c.Accrued, c.Amount,
+		intPtrArg(c.TransactionID), c.DebitAccrued, c.DebitAmount, intPtrArg(c.DebitTransactionID), intPtrArg(c.JournalEntryID))
+	if err != nil {
+		if r.dialect.IsDuplicateKey(err) {
+			return false, nil // The caller rolls tx back
+		}
+		return false, fmt.Errorf("failed to record interest capitalization: %w", err)
+	}
+	c.ID = int(id)
+	return true, nil
+}
+
+// MarkAccrualsCapitalized links the uncapitalized accruals of an account in the period to the
+// capitalization that paid them.
+func (r *interestRepositoryImpl) MarkAccrualsCapitalized(tx *sql.Tx, accountID int, periodStart, periodEnd time.Time, capitalizationID int) error {
+	query := `UPDATE interest_accruals SET capitalization_id = ?
+		WHERE account_id = ? AND accrual_date BETWEEN ? AND ? AND capitalization_id IS NULL`
+	if _, err := tx.Exec(r.dialect.Rebind(query), capitalizationID, accountID, dateArg(periodStart), dateArg(periodEnd)); err != nil {
+		return fmt.Errorf("failed to mark interest accruals capitalized: %w", err)
+	}
+	return nil
+}
+
+// GetOldestUncapitalizedAccrualDate returns the date of the oldest accrual of an account that has
+// not been capitalized yet.
+func (r *interestRepositoryImpl) GetOldestUncapitalizedAccrualDate(tx *sql.Tx, accountID int) (*time.Time, error) {
+	// ORDER BY rather than MIN keeps the column's DATE type, which SQLite loses on aggregates
+	query := `SELECT accrual_date FROM interest_accruals
+		WHERE account_id = ? AND capitalization_id IS NULL
+		ORDER BY accrual_date LIMIT 1`
+	var oldest time.Time
+	err := tx.QueryRow(r.dialect.Rebind(query), accountID).Scan(&oldest)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oldest interest accrual: %w", err)
+	}
+	day := localDate(oldest)
+	return &day, nil
+}
+
+// GetCapitalizations retrieves every capitalization of an account, newest first.
+func (r *interestRepositoryImpl) GetCapitalizations(accountID int) ([]models.InterestCapitalization, error) {
+	var capitalizations []models.InterestCapitalization
+	query := `SELECT ic.id, ic.account_id, ic.period_start, ic.period_end, a.currency, ic.accrued, ic.amount,
+			ic.transaction_id, ic.debit_accrued, ic.debit_amount, ic.debit_transaction_id, ic.journal_entry_id, ic.created_at
+		FROM interest_capitalizations ic
+		JOIN accounts a ON a.id = ic.account_id
+		WHERE ic.account_id = ?
+		ORDER BY ic.period_start DESC`
+	rows, err := r.db.Query(r.dialect.Rebind(query), accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch interest capitalizations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.InterestCapitalization
+		var currency money.Currency
+		var accrued, amount, debitAccrued, debitAmount string
+		var transactionID, debitTransactionID, journalEntryID sql.NullInt64
+		if err := rows.Scan(&c.ID, &c.AccountID, &c.PeriodStart, &c.PeriodEnd, &currency, &accrued, &amount,
+			&transactionID, &debitAccrued, &debitAmount, &debitTransactionID, &journalEntryID, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan interest capitalization row: %w", err)
+		}
+		c.PeriodStart, c.PeriodEnd = localDate(c.PeriodStart), localDate(c.PeriodEnd)
+		c.Accrued = trimDecimal(accrued)
+		if c.Amount, err = money.Parse(amount, currency); err != nil {
+			return nil, fmt.Errorf("invalid interest capitalization amount for account %d: %w", c.AccountID, err)
+		}
+		c.DebitAccrued = trimDecimal(debitAccrued)
+		if c.DebitAmount, err = money.Parse(debitAmount, currency); err != nil {
+			return nil, fmt.Errorf("invalid debit interest capitalization amount for account %d: %w", c.AccountID, err)
+		}
+		c.TransactionID = nullIntPtr(transactionID)
+		c.DebitTransactionID = nullIntPtr(debitTransactionID)
+		c.JournalEntryID = nullIntPtr(journalEntryID)
+		capitalizations = append(capitalizations, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating interest capitalization rows: %w", err)
+	}
+	return capitalizations, nil
+}
+
+// GetLastCompletedRunDate returns the latest date the interest job finished for every account.
+func (r *interestRepositoryImpl) GetLastCompletedRunDate() (*time.Time, error) {
+	// ORDER BY rather than MAX keeps the column's DATE type, which SQLite loses on aggregates
+	var last time.Time
+	err := r.db.QueryRow("SELECT run_date FROM interest_runs ORDER BY run_date DESC LIMIT 1").Scan(&last)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last interest run: %w", err)
+	}
+	day := localDate(last)
+	return &day, nil
+}
+
+// MarkRunCompleted records that the interest job finished a date. Re-running a date overwrites
+// the counts of the earlier run.
+func (r *interestRepositoryImpl) MarkRunCompleted(result *models.InterestRunResult) error {
+	query := "INSERT INTO interest_runs (run_date, accrued, skipped, capitalized, completed_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)" +
+		r.dialect.OnConflict([]string{"run_date"}, "accrued", "skipped", "capitalized", "completed_at")
+	if _, err := r.db.Exec(r.dialect.Rebind(query), dateArg(result.Date), result.Accrued, result.Skipped, result.Capitalized); err != nil {
+		return fmt.Errorf("failed to record interest run: %w", err)
+	}
+	return nil
+}
+
+// queryIDs runs a query returning a single integer column.
+func (r *interestRepositoryImpl) queryIDs(query string, args ...interface{}) ([]int, error) {
+	rows, err := r.db.Query(r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch interest accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan interest account row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating interest account rows: %w", err)
+	}
+	return ids, nil
+}
+
+// trimDecimal drops the trailing zeros a DECIMAL column pads its value with ("0.0123400000" -> "0.01234").
+func trimDecimal(s string) string {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return s
+	}
+	return strings.TrimRight(strings.TrimRight(r.FloatString(models.InterestAccrualScale), "0"), ".")
+}
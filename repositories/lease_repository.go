@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-bank-app/dialect"
+)
+
+// LeaseRepository manages named, expiring leases so that only one app instance at a time runs
+// a given background job. Lease expiry is judged by the database clock, so instances with skewed
+// clocks still agree on who holds a lease.
+type LeaseRepository interface {
+	TryAcquireLease(name, owner, token string, ttl time.Duration) (bool, error)
+	ReleaseLease(name, owner string) error
+}
+
+// leaseRepositoryImpl is the concrete implementation of LeaseRepository.
+type leaseRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewLeaseRepository creates a new instance of LeaseRepository that writes its queries for d.
+func NewLeaseRepository(db *sql.DB, d dialect.Dialect) LeaseRepository {
+	return &leaseRepositoryImpl{db: db, dialect: d}
+}
+
+// TryAcquireLease takes (or renews) the lease called name for owner if it is free, expired or
+// already held by owner. token must be unique per call: it guarantees the UPDATE changes the row,
+// so RowsAffected reliably tells whether the lease was obtained.
+func (r *leaseRepositoryImpl) TryAcquireLease(name, owner, token string, ttl time.Duration) (bool, error) {
+	// A new lease starts out expired, written by NowPlus so it compares with the database clock
+	insert := "INSERT INTO scheduler_leases (name, owner, token, expires_at) VALUES (?, '', '', " + r.dialect.NowPlus() + ")" +
+		r.dialect.OnConflict([]string{"name"})
+	if _, err := r.db.Exec(r.dialect.Rebind(insert), name, -time.Second.Microseconds()); err != nil {
+		return false, fmt.Errorf("failed to initialise lease %s: %w", name, err)
+	}
+	result, err := r.db.Exec(r.dialect.Rebind(`UPDATE scheduler_leases
+		SET owner = ?, token = ?, expires_at = `+r.dialect.NowPlus()+`
+		WHERE name = ? AND (owner = ? OR expires_at < `+r.dialect.Now()+`)`),
+		owner, token, ttl.Microseconds(), name, owner)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+	return n == 1, nil
+}
+
+// ReleaseLease gives up the lease if owner still holds it, letting another instance take it immediately.
+func (r *leaseRepositoryImpl) ReleaseLease(name, owner string) error {
+	query := "UPDATE scheduler_leases SET expires_at = " + r.dialect.NowPlus() + " WHERE name = ? AND owner = ?"
+	_, err := r.db.Exec(r.dialect.Rebind(query), -time.Second.Microseconds(), name, owner)
+	if err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"strconv"
+)
+
+// LedgerRepository defines the interface for general-ledger operations in the database.
+type LedgerRepository interface {
+	GetOrCreateCustomerAccount(tx *sql.Tx, account *models.Account) (*models.LedgerAccount, error)
+	GetOrCreateSystemAccount(tx *sql.Tx, code string, currency money.Currency) (*models.LedgerAccount, error)
+	CreateJournalEntry(tx *sql.Tx, entry *models.JournalEntry) (int64, error) // Inserts the entry and all its postings
+	GetPostings(tx *sql.Tx, journalEntryID int) ([]models.Posting, error)
+	GetTrialBalance() ([]models.TrialBalanceLine, error)
+	GetBalanceDiscrepancies() ([]models.BalanceDiscrepancy, error)
+}
+
+// ledgerRepositoryImpl is the concrete implementation of LedgerRepository.
+type ledgerRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewLedgerRepository creates a new instance of LedgerRepository that writes its queries for d.
+func NewLedgerRepository(db *sql.DB, d dialect.Dialect) LedgerRepository {
+	return &ledgerRepositoryImpl{db: db, dialect: d}
+}
+
+const selectLedgerAccountColumns = "SELECT id, code, name, type, account_id, currency, created_at FROM ledger_accounts"
+
+func scanLedgerAccount(row rowScanner) (*models.LedgerAccount, error) {
+	var la models.LedgerAccount
+	var accountID sql.NullInt64
+	err := row.Scan(&la.ID, &la.Code, &la.Name, &la.Type, &accountID, &la.Currency, &la.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if accountID.Valid {
+		id := int(accountID.Int64)
+		la.AccountID = &id
+	}
+	return &la, nil
+}
+
+// GetOrCreateCustomerAccount returns the liability ledger account that mirrors a customer
+// account, creating it on first use.
+func (r *ledgerRepositoryImpl) GetOrCreateCustomerAccount(tx *sql.Tx, account *models.Account) (*models.LedgerAccount, error) {
+	return r.getOrCreate(tx,
+		selectLedgerAccountColumns+" WHERE account_id = ?", []interface{}{account.ID},
+		"INSERT INTO ledger_accounts (code, name, type, account_id, currency) VALUES (?, ?, ?, ?, ?)"+r.dialect.OnConflict([]string{"account_id"}),
+		[]interface{}{"CUST-" + strconv.Itoa(account.ID), "Customer " + account.AccountNumber, models.LedgerLiability, account.ID, account.Currency},
+	)
+}
+
+// GetOrCreateSystemAccount returns the system ledger account with the given code in the given
+// currency, creating it on first use.
+func (r *ledgerRepositoryImpl) GetOrCreateSystemAccount(tx *sql.Tx, code string, currency money.Currency) (*models.LedgerAccount, error) {
+	def, ok := models.SystemLedgerAccounts[code]
+	if !ok {
+		return nil, fmt.Errorf("unknown system ledger account %q", code)
+	}
+	return r.getOrCreate(tx,
+		selectLedgerAccountColumns+" WHERE code = ? AND currency = ? AND account_id IS NULL", []interface{}{code, currency},
+		"INSERT INTO ledger_accounts (code, name, type, currency) VALUES (?, ?, ?, ?)"+r.dialect.OnConflict([]string{"code", "currency"}),
+		[]interface{}{code, def.Name, def.Type, currency},
+	)
+}
+
+// getOrCreate looks a ledger account up and inserts it if missing. The insert skips a row that a
+// concurrent transaction created first, so tx stays usable (PostgreSQL aborts a transaction on a
+// failed statement), and the winner's row is read back with a locking read, which unlike a plain
+// read sees rows committed after tx began.
+func (r *ledgerRepositoryImpl) getOrCreate(tx *sql.Tx, selectQuery string, selectArgs []interface{}, insertQuery string, insertArgs []interface{}) (*models.LedgerAccount, error) {
+	la, err := scanLedgerAccount(tx.QueryRow(r.dialect.Rebind(selectQuery), selectArgs...))
+	if err == nil {
+		return la, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to retrieve ledger account: %w", err)
+	}
+
+	if _, err := tx.Exec(r.dialect.Rebind(insertQuery), insertArgs...); err != nil {
+		return nil, fmt.Errorf("failed to create ledger account: %w", err)
+	}
+
+	la, err = scanLedgerAccount(tx.QueryRow(r.dialect.Rebind(selectQuery+r.dialect.ForUpdate()), selectArgs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve new ledger account: %w", err)
+	}
+	return la, nil
+}
+
+// CreateJournalEntry inserts a journal entry and its postings within the given transaction.
+// Balancing is the caller's responsibility (see services.LedgerService); this only persists.
+func (r *ledgerRepositoryImpl) CreateJournalEntry(tx *sql.Tx, entry *models.JournalEntry) (int64, error) {
+	entryID, err := r.dialect.InsertID(tx, "INSERT INTO journal_entries (reference, description) VALUES (?, ?)", entry.Reference, entry.Description)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create journal entry: %w", err)
+	}
+
+	for _, p := range entry.Postings {
+		_, err := tx.Exec(r.dialect.Rebind("INSERT INTO postings (journal_entry_id, ledger_account_id, amount, currency) VALUES (?, ?, ?, ?)"),
+			entryID, p.LedgerAccountID, p.Amount, p.Amount.Currency())
+		if err != nil {
+			return 0, fmt.Errorf("failed to create posting: %w", err)
+		}
+	}
+	return entryID, nil
+}
+
+// GetPostings retrieves the postings of a journal entry within the given transaction, with
+// AccountID set for postings on customer ledger accounts.
+func (r *ledgerRepositoryImpl) GetPostings(tx *sql.Tx, journalEntryID int) ([]models.Posting, error) {
+	query := `SELECT p.id, p.journal_entry_id, p.ledger_account_id, p.amount, p.currency, la.account_id
+		FROM postings p
+		JOIN ledger_accounts la ON la.id = p.ledger_account_id
+		WHERE p.journal_entry_id = ?
+		ORDER BY p.id`
+	rows, err := tx.Query(r.dialect.Rebind(query), journalEntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch postings: %w", err)
+	}
+	defer rows.Close()
+
+	var postings []models.Posting
+	for rows.Next() {
+		var p models.Posting
+		var amount string
+		var currency money.Currency
+		var accountID sql.NullInt64
+		if err := rows.Scan(&p.ID, &p.JournalEntryID, &p.LedgerAccountID, &amount, &currency, &accountID); err != nil {
+			return nil, fmt.Errorf("failed to scan posting row: %w", err)
+		}
+		if p.Amount, err = money.Parse(amount, currency); err != nil {
+			return nil, fmt.Errorf("invalid amount for posting %d: %w", p.ID, err)
+		}
+		p.AccountID = int(accountID.Int64)
+		postings = append(postings, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating posting rows: %w", err)
+	}
+	return postings, nil
+}
+
+// GetTrialBalance sums the debit and credit postings of every ledger account.
+func (r *ledgerRepositoryImpl) GetTrialBalance() ([]models.TrialBalanceLine, error) {
+	var lines []models.TrialBalanceLine
+	query := `SELECT la.id, la.code, la.name, la.type, la.currency,
+			COALESCE(SUM(CASE WHEN p.amount > 0 THEN p.amount ELSE 0 END), 0) AS debit,
+			COALESCE(SUM(CASE WHEN p.amount < 0 THEN -p.amount ELSE 0 END), 0) AS credit
+		FROM ledger_accounts la
+		LEFT JOIN postings p ON p.ledger_account_id = la.id
+		GROUP BY la.id, la.code, la.name, la.type, la.currency
+		ORDER BY la.currency, la.type, la.code`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trial balance: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.TrialBalanceLine
+		var debit, credit string
+		err := rows.Scan(&line.LedgerAccountID, &line.Code, &line.Name, &line.Type, &line.Currency, &debit, &credit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trial balance row: %w", err)
+		}
+		if line.Debit, err = money.Parse(debit, line.Currency); err != nil {
+			return nil, fmt.Errorf("invalid debit total for ledger account %d: %w", line.LedgerAccountID, err)
+		}
+		if line.Credit, err = money.Parse(credit, line.Currency); err != nil {
+			return nil, fmt.Errorf("invalid credit total for ledger account %d: %w", line.LedgerAccountID, err)
+		}
+		lines = append(lines, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating trial balance rows: %w", err)
+	}
+	return lines, nil
+}
+
+// GetBalanceDiscrepancies returns every customer account whose stored balance does not equal
+// the balance derived from its postings. The sum is rounded like UpdateAccountBalance rounds the
+// balance, so SQLite's binary floats compare equal when the decimals do.
+func (r *ledgerRepositoryImpl) GetBalanceDiscrepancies() ([]models.BalanceDiscrepancy, error) {
+	var discrepancies []models.BalanceDiscrepancy
+	query := `SELECT a.id, a.account_number, a.currency, a.balance, COALESCE(-SUM(p.amount), 0) AS ledger_balance
+		FROM accounts a
+		LEFT JOIN ledger_accounts la ON la.account_id = a.id
+		LEFT JOIN postings p ON p.ledger_account_id = la.id
+		GROUP BY a.id, a.account_number, a.currency, a.balance
+		HAVING a.balance <> ROUND(COALESCE(-SUM(p.amount), 0), 4)
+		ORDER BY a.id`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch balance discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.BalanceDiscrepancy
+		var currency money.Currency
+		var storedBalance, ledgerBalance string
+		if err := rows.Scan(&d.AccountID, &d.AccountNumber, &currency, &storedBalance, &ledgerBalance); err != nil {
+			return nil, fmt.Errorf("failed to scan balance discrepancy row: %w", err)
+		}
+		if d.StoredBalance, err = money.Parse(storedBalance, currency); err != nil {
+			return nil, fmt.Errorf("invalid stored balance for account %d: %w", d.AccountID, err)
+		}
+		if d.LedgerBalance, err = money.Parse(ledgerBalance, currency); err != nil {
+			return nil, fmt.Errorf("invalid ledger balance for account %d: %w", d.AccountID, err)
+		}
+		discrepancies = append(discrepancies, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating balance discrepancy rows: %w", err)
+	}
+	return discrepancies, nil
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
+
+// LimitRepository defines the interface for per-account limit overrides and limit usage.
+// Defaults per account type come from config (see config.AccountLimitDefaults).
+type LimitRepository interface {
+	GetOverrides(tx *sql.Tx, accountID int, currency money.Currency) (*models.AccountLimits, error) // Empty limits if none are set
+	SetOverrides(accountID int, limits *models.AccountLimits) error                                 // Replaces every override of the account
+	GetDebitUsage(tx *sql.Tx, accountID int, dayStart, monthStart time.Time, currency money.Currency) (*models.LimitUsage, error)
+}
+
+// limitRepositoryImpl is the concrete implementation of LimitRepository.
+type limitRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewLimitRepository creates a new instance of LimitRepository that writes its queries for d.
+func NewLimitRepository(db *sql.DB, d dialect.Dialect) LimitRepository {
+	return &limitRepositoryImpl{db: db, dialect: d}
+}
+
+// GetOverrides reads the limits set on one account within the given transaction. Amounts are
+// stored in the account's currency.
+func (r *limitRepositoryImpl) GetOverrides(tx *sql.Tx, accountID int, currency money.Currency) (*models.AccountLimits, error) {
+	var limits models.AccountLimits
+	var perTransaction, dailyDebit, monthlyDebit sql.NullString
+	var dailyTransfers sql.NullInt64
+	query := "SELECT per_transaction, daily_debit, monthly_debit, daily_transfers FROM account_limits WHERE account_id = ?"
+	err := tx.QueryRow(r.dialect.Rebind(query), accountID).Scan(&perTransaction, &dailyDebit, &monthlyDebit, &dailyTransfers)
+	if err == sql.ErrNoRows {
+		return &limits, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account limits: %w", err)
+	}
+
+	if limits.PerTransaction, err = nullMoneyPtr(perTransaction, currency); err != nil {
+		return nil, fmt.Errorf("invalid per-transaction limit for account %d: %w", accountID, err)
+	}
+	if limits.DailyDebit, err = nullMoneyPtr(dailyDebit, currency); err != nil {
+		return nil, fmt.Errorf("invalid daily debit limit for account %d: %w", accountID, err)
+	}
+	if limits.MonthlyDebit, err = nullMoneyPtr(monthlyDebit, currency); err != nil {
+		return nil, fmt.Errorf("invalid monthly debit limit for account %d: %w", accountID, err)
+	}
+	limits.DailyTransfers = nullIntPtr(dailyTransfers)
+	return &limits, nil
+}
+
+// SetOverrides stores the limits of one account, replacing any earlier overrides. Nil fields are
+// stored as NULL, i.e. the account type default applies.
+func (r *limitRepositoryImpl) SetOverrides(accountID int, limits *models.AccountLimits) error {
+	query := `INSERT INTO account_limits (account_id, per_transaction, daily_debit, monthly_debit, daily_transfers)
+		VALUES (?, ?, ?, ?, ?)` +
+		r.dialect.OnConflict([]string{"account_id"}, "per_transaction", "daily_debit", "monthly_debit", "daily_transfers")
+	_, err := r.db.Exec(r.dialect.Rebind(query), accountID, moneyPtrArg(limits.PerTransaction), moneyPtrArg(limits.DailyDebit),
+		moneyPtrArg(limits.MonthlyDebit), intPtrArg(limits.DailyTransfers))
+	if err != nil {
+		if r.dialect.IsForeignKeyViolation(err) {
+			return ErrAccountNotFound
+		}
+		return fmt.Errorf("failed to store account limits: %w", err)
+	}
+	return nil
+}
+
+// GetDebitUsage sums the limited debits (see models.LimitedDebitTypes) of an account since
+// monthStart and since dayStart, and counts its transfers since dayStart. Reversed transactions
+// are not counted. Run it after locking the account row so no concurrent debit can slip past.
+func (r *limitRepositoryImpl) GetDebitUsage(tx *sql.Tx, accountID int, dayStart, monthStart time.Time, currency money.Currency) (*models.LimitUsage, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(models.LimitedDebitTypes)), ", ")
+	query := `SELECT COALESCE(SUM(CASE WHEN transaction_date >= ? THEN amount ELSE 0 END), 0),
+			COALESCE(SUM(amount), 0),
+			COUNT(CASE WHEN transaction_date >= ? AND transaction_type = ? THEN 1 END)
+		FROM transactions
+		WHERE account_id = ? AND transaction_date >= ? AND reversed_by_transaction_id IS NULL
+			AND transaction_type IN (` + placeholders + `)`
+	args := []interface{}{dayStart, dayStart, models.TransactionTransferOut, accountID, monthStart}
+	for _, t := range models.LimitedDebitTypes {
+		args = append(args, t)
+	}
+
+	var daily, monthly string
+	usage := &models.LimitUsage{AsOf: time.Now()}
+	if err := tx.QueryRow(r.dialect.Rebind(query), args...).Scan(&daily, &monthly, &usage.DailyTransfers); err != nil {
+		return nil, fmt.Errorf("failed to compute limit usage: %w", err)
+	}
+	var err error
+	if usage.DailyDebit, err = money.Parse(daily, currency); err != nil {
+		return nil, fmt.Errorf("invalid daily debit total for account %d: %w", accountID, err)
+	}
+	if usage.MonthlyDebit, err = money.Parse(monthly, currency); err != nil {
+		return nil, fmt.Errorf("invalid monthly debit total for account %d: %w", accountID, err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"strings"
+)
+
+// whereBuilder collects optional SQL conditions and their arguments, so list queries with many
+// optional filters can be assembled without string-concatenating user input.
+type whereBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add appends a condition with its placeholder arguments.
+func (b *whereBuilder) add(condition string, args ...interface{}) {
+	b.conditions = append(b.conditions, condition)
+	b.args = append(b.args, args...)
+}
+
+// in appends "column IN (?, ?, ...)" for the given values. An empty list adds nothing.
+func (b *whereBuilder) in(column string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	b.add(column+" IN ("+placeholders+")", args...)
+}
+
+// contains appends a case-insensitive substring match on column, escaping LIKE wildcards in s with
+// a backslash. escapeClause is the dialect's LikeEscape, for databases without a default escape.
+func (b *whereBuilder) contains(column, s, escapeClause string) {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(s))
+	b.add("LOWER("+column+") LIKE ?"+escapeClause, "%"+escaped+"%")
+}
+
+// sql renders the collected conditions as a WHERE clause (empty if there are none).
+func (b *whereBuilder) sql() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
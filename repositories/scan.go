@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"go-bank-app/money"
+)
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows, so one scan helper serves
+// single-row lookups and list queries alike.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// nullIntPtr converts a nullable integer column into *int (nil for NULL).
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+// intPtrArg converts *int into a value suitable for a nullable integer column.
+func intPtrArg(p *int) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// nullTimePtr converts a nullable time column into *time.Time (nil for NULL).
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
+
+// timePtrArg converts *time.Time into a value suitable for a nullable time column.
+func timePtrArg(p *time.Time) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// moneyPtrArg converts *money.Money into a value suitable for a nullable DECIMAL column.
+func moneyPtrArg(p *money.Money) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// nullMoneyPtr parses a nullable DECIMAL column into *money.Money (nil for NULL).
+func nullMoneyPtr(s sql.NullString, currency money.Currency) (*money.Money, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	m, err := money.Parse(s.String, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// dateArg formats t as a DATE column value. Passing the calendar date as a string keeps the
+// driver from converting a local midnight to UTC, which would shift it to the previous day.
+func dateArg(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// localDate converts a scanned DATE column (midnight UTC) to midnight of the same calendar
+// date in server-local time.
+func localDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
+
+// StandingOrderRepository defines the interface for standing order operations in the database.
+type StandingOrderRepository interface {
+	CreateStandingOrder(order *models.StandingOrder) (int64, error)
+	GetStandingOrderByID(id int) (*models.StandingOrder, error)
+	GetStandingOrderByIDForUpdate(tx *sql.Tx, id int) (*models.StandingOrder, error) // Locks the row until tx ends
+	GetStandingOrdersByAccountID(accountID int) ([]models.StandingOrder, error)
+	UpdateStandingOrder(tx *sql.Tx, order *models.StandingOrder) error
+	GetDueStandingOrderIDs(now time.Time, limit int) ([]int, error)
+	CreateExecution(tx *sql.Tx, execution *models.StandingOrderExecution) (int64, error)
+	GetExecutionsByStandingOrderID(orderID int) ([]models.StandingOrderExecution, error)
+}
+
+// standingOrderRepositoryImpl is the concrete implementation of StandingOrderRepository.
+type standingOrderRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewStandingOrderRepository creates a new instance of StandingOrderRepository that writes its queries for d.
+func NewStandingOrderRepository(db *sql.DB, d dialect.Dialect) StandingOrderRepository {
+	return &standingOrderRepositoryImpl{db: db, dialect: d}
+}
+
+const selectStandingOrderColumns = `SELECT so.id, so.account_id, so.to_account_number, a.currency, so.amount, so.description,
+		so.frequency, so.day_of_month, so.start_date, so.end_date, so.max_runs, so.runs_completed,
+		so.insufficient_funds_policy, so.max_retries, so.retry_count, so.scheduled_for, so.next_run_at,
+		so.status, so.created_by, so.created_at, so.updated_at
+	FROM standing_orders so
+	JOIN accounts a ON a.id = so.account_id`
+
+func scanStandingOrder(row rowScanner) (*models.StandingOrder, error) {
+	var o models.StandingOrder
+	var currency money.Currency
+	var amount string
+	var dayOfMonth, maxRuns sql.NullInt64
+	var endDate, scheduledFor, nextRunAt sql.NullTime
+	err := row.Scan(&o.ID, &o.AccountID, &o.ToAccountNumber, &currency, &amount, &o.Description,
+		&o.Frequency, &dayOfMonth, &o.StartDate, &endDate, &maxRuns, &o.RunsCompleted,
+		&o.InsufficientFundsPolicy, &o.MaxRetries, &o.RetryCount, &scheduledFor, &nextRunAt,
+		&o.Status, &o.CreatedBy, &o.CreatedAt, &o.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if o.Amount, err = money.Parse(amount, currency); err != nil {
+		return nil, fmt.Errorf("invalid amount for standing order %d: %w", o.ID, err)
+	}
+	o.DayOfMonth = nullIntPtr(dayOfMonth)
+	o.MaxRuns = nullIntPtr(maxRuns)
+	o.EndDate = nullTimePtr(endDate)
+	o.ScheduledFor = nullTimePtr(scheduledFor)
+	o.NextRunAt = nullTimePtr(nextRunAt)
+	return &o, nil
+}
+
+// CreateStandingOrder inserts a new standing order.
+func (r *standingOrderRepositoryImpl) CreateStandingOrder(order *models.StandingOrder) (int64, error) {
+	query := `INSERT INTO standing_orders (account_id, to_account_number, amount, description, frequency, day_of_month,
+			start_date, end_date, max_runs, runs_completed, insufficient_funds_policy, max_retries, retry_count,
+			scheduled_for, next_run_at, status, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.dialect.InsertID(r.db, query, order.AccountID, order.ToAccountNumber, order.Amount, order.Description, order.Frequency,
+		intPtrArg(order.DayOfMonth), order.StartDate, timePtrArg(order.EndDate), intPtrArg(order.MaxRuns), order.RunsCompleted,
+		order.InsufficientFundsPolicy, order.MaxRetries, order.RetryCount,
+		timePtrArg(order.ScheduledFor), timePtrArg(order.NextRunAt), order.Status, order.CreatedBy)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create standing order in database: %w", err)
+	}
+	return id, nil
+}
+
+// GetStandingOrderByID retrieves a standing order using its ID.
+func (r *standingOrderRepositoryImpl) GetStandingOrderByID(id int) (*models.StandingOrder, error) {
+	order, err := scanStandingOrder(r.db.QueryRow(r.dialect.Rebind(selectStandingOrderColumns+" WHERE so.id = ?"), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrStandingOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve standing order by ID: %w", err)
+	}
+	return order, nil
+}
+
+// GetStandingOrderByIDForUpdate reads a standing order inside tx with SELECT ... FOR UPDATE, so a
+// second scheduler instance cannot execute the same run concurrently.
+func (r *standingOrderRepositoryImpl) GetStandingOrderByIDForUpdate(tx *sql.Tx, id int) (*models.StandingOrder, error) {
+	order, err := scanStandingOrder(tx.QueryRow(r.dialect.Rebind(selectStandingOrderColumns+" WHERE so.id = ?"+r.dialect.ForUpdate()), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrStandingOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to lock standing order by ID: %w", err)
+	}
+	return order, nil
+}
+
+// GetStandingOrdersByAccountID retrieves every standing order of an account, newest first.
+func (r *standingOrderRepositoryImpl) GetStandingOrdersByAccountID(accountID int) ([]models.StandingOrder, error) {
+	var orders []models.StandingOrder
+	rows, err := r.db.Query(r.dialect.Rebind(selectStandingOrderColumns+" WHERE so.account_id = ? ORDER BY so.created_at DESC, so.id DESC"), accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch standing orders: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		o, err := scanStandingOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan standing order row: %w", err)
+		}
+		orders = append(orders, *o)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating standing order rows: %w", err)
+	}
+	return orders, nil
+}
+
+// UpdateStandingOrder writes back every mutable field of a standing order within the given transaction.
+func (r *standingOrderRepositoryImpl) UpdateStandingOrder(tx *sql.Tx, order *models.StandingOrder) error {
+	query := `UPDATE standing_orders SET amount = ?, description = ?, end_date = ?, max_runs = ?, runs_completed = ?,
+			retry_count = ?, scheduled_for = ?, next_run_at = ?, status = ?
+		WHERE id = ?`
+	_, err := tx.Exec(r.dialect.Rebind(query), order.Amount, order.Description, timePtrArg(order.EndDate), intPtrArg(order.MaxRuns), order.RunsCompleted,
+		order.RetryCount, timePtrArg(order.ScheduledFor), timePtrArg(order.NextRunAt), order.Status, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update standing order: %w", err)
+	}
+	return nil
+}
+
+// GetDueStandingOrderIDs returns active orders whose next run is at or before now, oldest first.
+func (r *standingOrderRepositoryImpl) GetDueStandingOrderIDs(now time.Time, limit int) ([]int, error) {
+	query := "SELECT id FROM standing_orders WHERE status = ? AND next_run_at <= ? ORDER BY next_run_at, id LIMIT ?"
+	rows, err := r.db.Query(r.dialect.Rebind(query), models.StandingOrderActive, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch due standing orders: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan due standing order: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating due standing orders: %w", err)
+	}
+	return ids, nil
+}
+
+// maxExecutionErrorLength matches the standing_order_executions.error column.
+const maxExecutionErrorLength = 255
+
+// CreateExecution records one execution attempt of a standing order. Long error messages are
+// truncated to fit the column.
+func (r *standingOrderRepositoryImpl) CreateExecution(tx *sql.Tx, execution *models.StandingOrderExecution) (int64, error) {
+	errMsg := execution.Error
+	if len(errMsg) > maxExecutionErrorLength {
+		errMsg = errMsg[:maxExecutionErrorLength]
+	}
+	query := `INSERT INTO standing_order_executions (standing_order_id, scheduled_for, attempt, status, transfer_id, error)
+		VALUES (?, ?, ?, ?, ?, ?)`
+	id, err := r.dialect.InsertID(tx, query, execution.StandingOrderID, execution.ScheduledFor, execution.Attempt, execution.Status,
+		intPtrArg(execution.TransferID), errMsg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record standing order execution: %w", err)
+	}
+	return id, nil
+}
+
+// GetExecutionsByStandingOrderID retrieves every execution of a standing order, newest first.
+func (r *standingOrderRepositoryImpl) GetExecutionsByStandingOrderID(orderID int) ([]models.StandingOrderExecution, error) {
+	var executions []models.StandingOrderExecution
+	query := `SELECT id, standing_order_id, scheduled_for, attempt, status, transfer_id, error, executed_at
+		FROM standing_order_executions WHERE standing_order_id = ? ORDER BY executed_at DESC, id DESC`
+	rows, err := r.db.Query(r.dialect.Rebind(query), orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch standing order executions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.StandingOrderExecution
+		var transferID sql.NullInt64
+		if err := rows.Scan(&e.ID, &e.StandingOrderID, &e.ScheduledFor, &e.Attempt, &e.Status, &transferID, &e.Error, &e.ExecutedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan standing order execution row: %w", err)
+		}
+		e.TransferID = nullIntPtr(transferID)
+		executions = append(executions, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating standing order execution rows: %w", err)
+	}
+	return executions, nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+	"time"
+)
+
+// TokenRepository defines the interface for refresh tokens and the access-token revocation list.
+type TokenRepository interface {
+	CreateRefreshToken(tx *sql.Tx, token *models.RefreshToken) (int64, error)
+	GetRefreshTokenByHashForUpdate(tx *sql.Tx, tokenHash string) (*models.RefreshToken, error)
+	MarkRefreshTokenRotated(tx *sql.Tx, id int, replacedByID int) error
+	RevokeRefreshTokenFamily(familyID string) error
+	RevokeAccessToken(jti string, userID int, expiresAt time.Time) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+}
+
+// tokenRepositoryImpl is the concrete implementation of TokenRepository.
+type tokenRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewTokenRepository creates a new instance of TokenRepository that writes its queries for d.
+func NewTokenRepository(db *sql.DB, d dialect.Dialect) TokenRepository {
+	return &tokenRepositoryImpl{db: db, dialect: d}
+}
+
+// CreateRefreshToken stores a new refresh token (hash only) within the given transaction.
+func (r *tokenRepositoryImpl) CreateRefreshToken(tx *sql.Tx, token *models.RefreshToken) (int64, error) {
+	query := "INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at) VALUES (?, ?, ?, ?)"
+	id, err := r.dialect.InsertID(tx, query, token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return id, nil
+}
+
+// GetRefreshTokenByHashForUpdate looks a refresh token up by hash and locks it, so two
+// concurrent refreshes with the same token cannot both rotate it.
+func (r *tokenRepositoryImpl) GetRefreshTokenByHashForUpdate(tx *sql.Tx, tokenHash string) (*models.RefreshToken, error) {
+	var t models.RefreshToken
+	var revokedAt sql.NullTime
+	var replacedByID sql.NullInt64
+	query := `SELECT id, user_id, token_hash, family_id, expires_at, revoked_at, replaced_by_id, created_at
+		FROM refresh_tokens WHERE token_hash = ?` + r.dialect.ForUpdate()
+	err := tx.QueryRow(r.dialect.Rebind(query), tokenHash).
+		Scan(&t.ID, &t.UserID, &t.TokenHash, &t.FamilyID, &t.ExpiresAt, &revokedAt, &replacedByID, &t.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve refresh token: %w", err)
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+	t.ReplacedByID = nullIntPtr(replacedByID)
+	return &t, nil
+}
+
+// MarkRefreshTokenRotated revokes a refresh token and records which token replaced it.
+func (r *tokenRepositoryImpl) MarkRefreshTokenRotated(tx *sql.Tx, id int, replacedByID int) error {
+	_, err := tx.Exec(r.dialect.Rebind("UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP, replaced_by_id = ? WHERE id = ?"), replacedByID, id)
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeRefreshTokenFamily revokes every still-active token descended from the same login.
+func (r *tokenRepositoryImpl) RevokeRefreshTokenFamily(familyID string) error {
+	_, err := r.db.Exec(r.dialect.Rebind("UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = ? AND revoked_at IS NULL"), familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+// RevokeAccessToken adds an access token's jti to the revocation list. The row only needs to
+// live until the token would have expired anyway.
+func (r *tokenRepositoryImpl) RevokeAccessToken(jti string, userID int, expiresAt time.Time) error {
+	query := "INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES (?, ?, ?)"
+	_, err := r.db.Exec(r.dialect.Rebind(query), jti, userID, expiresAt)
+	if err != nil && !r.dialect.IsDuplicateKey(err) {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	// Housekeeping: entries for tokens that have expired by now are useless
+	if _, err := r.db.Exec(r.dialect.Rebind("DELETE FROM revoked_tokens WHERE expires_at < ?"), time.Now()); err != nil {
+		return fmt.Errorf("failed to purge expired revoked tokens: %w", err)
+	}
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether the jti is on the revocation list.
+func (r *tokenRepositoryImpl) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int
+	err := r.db.QueryRow(r.dialect.Rebind("SELECT COUNT(*) FROM revoked_tokens WHERE jti = ?"), jti).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return count > 0, nil
+}
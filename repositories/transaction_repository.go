@@ -3,56 +3,180 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"go-bank-app/dialect"
 	"go-bank-app/models"
+	"go-bank-app/money"
+	"strings"
+	"time"
 )
 
 // TransactionRepository defines the interface for transaction operations in the database.
 type TransactionRepository interface {
-	CreateTransaction(tx *sql.Tx, transaction *models.Transaction) (int64, error) // Accepts *sql.Tx
-	GetTransactionsByAccountID(accountID int) ([]models.Transaction, error)
+	CreateTransaction(tx *sql.Tx, transaction *models.Transaction) (int64, error)                            // Accepts *sql.Tx
+	GetTransactionsByAccountID(accountID int, filter models.TransactionFilter) ([]models.Transaction, error) // One page, newest first
+	GetTransactionsInPeriod(accountID int, from, to time.Time) ([]models.Transaction, error)                 // Oldest first, for statements
+	GetBalanceBefore(accountID int, before time.Time, currency money.Currency) (money.Money, error)
+	GetTransactionByID(id int) (*models.Transaction, error)
+	GetTransactionByIDForUpdate(tx *sql.Tx, id int) (*models.Transaction, error)      // Locks the row until tx ends
+	GetTransactionsByJournalEntryID(journalEntryID int) ([]models.Transaction, error) // Every leg of one business event
+	MarkTransactionReversed(tx *sql.Tx, id, reversalID int) error
 }
 
 // transactionRepositoryImpl is the concrete implementation of TransactionRepository.
 type transactionRepositoryImpl struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect.Dialect
 }
 
-// NewTransactionRepository creates a new instance of TransactionRepository.
-func NewTransactionRepository(db *sql.DB) TransactionRepository {
-	return &transactionRepositoryImpl{db: db}
+// NewTransactionRepository creates a new instance of TransactionRepository that writes its queries for d.
+func NewTransactionRepository(db *sql.DB, d dialect.Dialect) TransactionRepository {
+	return &transactionRepositoryImpl{db: db, dialect: d}
 }
 
 // CreateTransaction inserts a new transaction into the database within the given transaction context.
+// TransactionDate defaults to now; a non-zero value books the transaction with that value date
+// (used by interest capitalization, which is effective from the first day of the next month).
 func (r *transactionRepositoryImpl) CreateTransaction(tx *sql.Tx, transaction *models.Transaction) (int64, error) {
-	query := "INSERT INTO transactions (account_id, transaction_type, amount, description) VALUES (?, ?, ?, ?)"
-	result, err := tx.Exec(query, transaction.AccountID, transaction.TransactionType, transaction.Amount, transaction.Description)
+	query := `INSERT INTO transactions (account_id, transaction_type, amount, currency, description, journal_entry_id, reversal_of_transaction_id, transaction_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP))`
+	var journalEntryID sql.NullInt64
+	if transaction.JournalEntryID != 0 {
+		journalEntryID = sql.NullInt64{Int64: int64(transaction.JournalEntryID), Valid: true}
+	}
+	var transactionDate sql.NullTime
+	if !transaction.TransactionDate.IsZero() {
+		transactionDate = sql.NullTime{Time: transaction.TransactionDate, Valid: true}
+	}
+	id, err := r.dialect.InsertID(tx, query, transaction.AccountID, transaction.TransactionType, transaction.Amount, transaction.Amount.Currency(), transaction.Description, journalEntryID, intPtrArg(transaction.ReversalOf), transactionDate)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create transaction in the database: %w", err)
 	}
-	id, err := result.LastInsertId()
+	return id, nil
+}
+
+const selectTransactionColumns = `SELECT id, account_id, transaction_type, amount, currency, description, transaction_date, journal_entry_id,
+		reversal_of_transaction_id, reversed_by_transaction_id
+	FROM transactions`
+
+// scanTransaction reads a single transaction row produced by a query built on selectTransactionColumns.
+func scanTransaction(row rowScanner) (*models.Transaction, error) {
+	var t models.Transaction
+	var amount string
+	var journalEntryID, reversalOf, reversedBy sql.NullInt64
+	err := row.Scan(&t.ID, &t.AccountID, &t.TransactionType, &amount, &t.Currency, &t.Description, &t.TransactionDate, &journalEntryID,
+		&reversalOf, &reversedBy)
 	if err != nil {
-		return 0, fmt.Errorf("failed to retrieve new transaction ID: %w", err)
+		return nil, err
 	}
-	return id, nil
+	if t.Amount, err = money.Parse(amount, t.Currency); err != nil {
+		return nil, fmt.Errorf("invalid amount for transaction %d: %w", t.ID, err)
+	}
+	t.JournalEntryID = int(journalEntryID.Int64)
+	t.ReversalOf = nullIntPtr(reversalOf)
+	t.ReversedBy = nullIntPtr(reversedBy)
+	t.Reversed = t.ReversedBy != nil
+	return &t, nil
+}
+
+// GetTransactionByID retrieves a transaction using its ID.
+func (r *transactionRepositoryImpl) GetTransactionByID(id int) (*models.Transaction, error) {
+	t, err := scanTransaction(r.db.QueryRow(r.dialect.Rebind(selectTransactionColumns+" WHERE id = ?"), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve transaction by ID: %w", err)
+	}
+	return t, nil
+}
+
+// GetTransactionByIDForUpdate reads a transaction inside tx with SELECT ... FOR UPDATE, so
+// concurrent reversals of the same transaction are serialized.
+func (r *transactionRepositoryImpl) GetTransactionByIDForUpdate(tx *sql.Tx, id int) (*models.Transaction, error) {
+	t, err := scanTransaction(tx.QueryRow(r.dialect.Rebind(selectTransactionColumns+" WHERE id = ?"+r.dialect.ForUpdate()), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve and lock transaction: %w", err)
+	}
+	return t, nil
 }
 
-// GetTransactionsByAccountID retrieves all transactions for a specific account, ordered by transaction date (descending).
-func (r *transactionRepositoryImpl) GetTransactionsByAccountID(accountID int) ([]models.Transaction, error) {
+// GetTransactionsByJournalEntryID retrieves every transaction recorded by one journal entry, e.g.
+// the transfer_out and transfer_in legs of a transfer.
+func (r *transactionRepositoryImpl) GetTransactionsByJournalEntryID(journalEntryID int) ([]models.Transaction, error) {
+	rows, err := r.db.Query(r.dialect.Rebind(selectTransactionColumns+" WHERE journal_entry_id = ? ORDER BY id"), journalEntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions of journal entry: %w", err)
+	}
+	defer rows.Close()
+
 	var transactions []models.Transaction
-	query := "SELECT id, account_id, transaction_type, amount, description, transaction_date FROM transactions WHERE account_id = ? ORDER BY transaction_date DESC"
-	rows, err := r.db.Query(query, accountID)
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction row: %w", err)
+		}
+		transactions = append(transactions, *t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating transaction rows: %w", err)
+	}
+	return transactions, nil
+}
+
+// MarkTransactionReversed links a transaction to the transaction that reversed it. Whether it
+// may be reversed is checked by the service layer on the locked row.
+func (r *transactionRepositoryImpl) MarkTransactionReversed(tx *sql.Tx, id, reversalID int) error {
+	_, err := tx.Exec(r.dialect.Rebind("UPDATE transactions SET reversed_by_transaction_id = ? WHERE id = ?"), reversalID, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark transaction %d as reversed: %w", id, err)
+	}
+	return nil
+}
+
+// GetTransactionsByAccountID retrieves one page of an account's transactions matching filter,
+// newest first (transaction_date DESC, id DESC). At most filter.Limit rows are returned; the
+// (account_id, transaction_date, id) index serves both the ordering and the cursor condition.
+func (r *transactionRepositoryImpl) GetTransactionsByAccountID(accountID int, filter models.TransactionFilter) ([]models.Transaction, error) {
+	var where whereBuilder
+	where.add("account_id = ?", accountID)
+	if filter.After != nil {
+		where.add("(transaction_date < ? OR (transaction_date = ? AND id < ?))", filter.After.Time, filter.After.Time, filter.After.ID)
+	}
+	if filter.From != nil {
+		where.add("transaction_date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		where.add("transaction_date < ?", *filter.To)
+	}
+	where.in("transaction_type", filter.Types)
+	if filter.MinAmount != nil {
+		where.add("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		where.add("amount <= ?", *filter.MaxAmount)
+	}
+	if filter.Description != "" {
+		where.contains("description", filter.Description, r.dialect.LikeEscape())
+	}
+
+	query := selectTransactionColumns + where.sql() + " ORDER BY transaction_date DESC, id DESC LIMIT ?"
+	rows, err := r.db.Query(r.dialect.Rebind(query), append(where.args, filter.Limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
 	}
 	defer rows.Close()
 
+	var transactions []models.Transaction
 	for rows.Next() {
-		var t models.Transaction
-		err := rows.Scan(&t.ID, &t.AccountID, &t.TransactionType, &t.Amount, &t.Description, &t.TransactionDate)
+		t, err := scanTransaction(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan transaction row: %w", err)
 		}
-		transactions = append(transactions, t)
+		transactions = append(transactions, *t)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -60,3 +184,52 @@ func (r *transactionRepositoryImpl) GetTransactionsByAccountID(accountID int) ([
 	}
 	return transactions, nil
 }
+
+// GetTransactionsInPeriod retrieves every transaction of an account with from <= transaction_date < to,
+// oldest first, so a running balance can be computed line by line.
+func (r *transactionRepositoryImpl) GetTransactionsInPeriod(accountID int, from, to time.Time) ([]models.Transaction, error) {
+	query := selectTransactionColumns + " WHERE account_id = ? AND transaction_date >= ? AND transaction_date < ? ORDER BY transaction_date, id"
+	rows, err := r.db.Query(r.dialect.Rebind(query), accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions for period: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction row: %w", err)
+		}
+		transactions = append(transactions, *t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating transaction rows: %w", err)
+	}
+	return transactions, nil
+}
+
+// GetBalanceBefore sums every transaction of an account dated before the given time, counting
+// credit types (see models.CreditTransactionTypes) as inflows and the rest as outflows.
+func (r *transactionRepositoryImpl) GetBalanceBefore(accountID int, before time.Time, currency money.Currency) (money.Money, error) {
+	creditTypes := models.CreditTransactionTypes()
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(creditTypes)), ", ")
+	query := `SELECT COALESCE(SUM(CASE WHEN transaction_type IN (` + placeholders + `) THEN amount ELSE -amount END), 0)
+		FROM transactions WHERE account_id = ? AND transaction_date < ?`
+	args := make([]interface{}, 0, len(creditTypes)+2)
+	for _, t := range creditTypes {
+		args = append(args, t)
+	}
+	args = append(args, accountID, before)
+
+	var balance string
+	if err := r.db.QueryRow(r.dialect.Rebind(query), args...).Scan(&balance); err != nil {
+		return money.Money{}, fmt.Errorf("failed to compute balance before %s: %w", before.Format(time.RFC3339), err)
+	}
+	m, err := money.Parse(balance, currency)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("invalid opening balance for account %d: %w", accountID, err)
+	}
+	return m, nil
+}
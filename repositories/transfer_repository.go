@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"time"
+)
+
+// TransferRepository defines the interface for transfer operations in the database.
+type TransferRepository interface {
+	CreateTransfer(tx *sql.Tx, transfer *models.Transfer) (int64, error) // Accepts *sql.Tx
+	GetTransferByID(id int) (*models.Transfer, error)
+	GetTransferByIDForUpdate(tx *sql.Tx, id int) (*models.Transfer, error) // Locks the row until tx ends
+	GetTransfersByUserID(userID int) ([]models.Transfer, error)
+	UpdateTransfer(tx *sql.Tx, transfer *models.Transfer) error           // Stores the outcome of an approval decision
+	GetTransferIDByJournalEntryID(journalEntryID int) (int, error)        // 0 if the entry did not book a transfer
+	GetPendingApprovalsForApprover(userID int) ([]models.Transfer, error) // Pending transfers from accounts userID approves
+	GetExpiredPendingTransferIDs(now time.Time, limit int) ([]int, error)
+}
+
+// transferRepositoryImpl is the concrete implementation of TransferRepository.
+type transferRepositoryImpl struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewTransferRepository creates a new instance of TransferRepository that writes its queries for d.
+func NewTransferRepository(db *sql.DB, d dialect.Dialect) TransferRepository {
+	return &transferRepositoryImpl{db: db, dialect: d}
+}
+
+const selectTransferColumns = `SELECT t.id, t.from_account_id, fa.account_number, t.to_account_id, ta.account_number,
+		fa.currency, t.amount, ta.currency, t.destination_amount, t.fee,
+		t.fx_mid_rate, t.fx_applied_rate, t.fx_rate_source, t.fx_rate_as_of, t.fx_spread, t.description, t.status, t.outbound_transaction_id, t.inbound_transaction_id, t.journal_entry_id,
+		t.requested_by, t.approval_expires_at, t.hold_id, t.decided_by, t.decided_at, t.decision_reason,
+		t.created_at, t.updated_at
+	FROM transfers t
+	JOIN accounts fa ON fa.id = t.from_account_id
+	JOIN accounts ta ON ta.id = t.to_account_id`
+
+func scanTransfer(row rowScanner) (*models.Transfer, error) {
+	var t models.Transfer
+	var fromCurrency, toCurrency money.Currency
+	var amount, destinationAmount, fee string
+	var midRate, appliedRate, rateSource, spread sql.NullString
+	var rateAsOf sql.NullTime
+	var outboundID, inboundID, journalEntryID sql.NullInt64
+	var requestedBy, holdID, decidedBy sql.NullInt64
+	var approvalExpiresAt, decidedAt sql.NullTime
+	var decisionReason sql.NullString
+	err := row.Scan(&t.ID, &t.FromAccountID, &t.FromAccountNumber, &t.ToAccountID, &t.ToAccountNumber,
+		&fromCurrency, &amount, &toCurrency, &destinationAmount, &fee,
+		&midRate, &appliedRate, &rateSource, &rateAsOf, &spread, &t.Description,
+		&t.Status, &outboundID, &inboundID, &journalEntryID,
+		&requestedBy, &approvalExpiresAt, &holdID, &decidedBy, &decidedAt, &decisionReason,
+		&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if t.Amount, err = money.Parse(amount, fromCurrency); err != nil {
+		return nil, fmt.Errorf("invalid amount for transfer %d: %w", t.ID, err)
+	}
+	if t.DestinationAmount, err = money.Parse(destinationAmount, toCurrency); err != nil {
+		return nil, fmt.Errorf("invalid destination amount for transfer %d: %w", t.ID, err)
+	}
+	if t.Fee, err = money.Parse(fee, fromCurrency); err != nil {
+		return nil, fmt.Errorf("invalid fee for transfer %d: %w", t.ID, err)
+	}
+	if midRate.Valid {
+		t.FX = &models.TransferFX{
+			MidRate:     midRate.String,
+			AppliedRate: appliedRate.String,
+			RateSource:  rateSource.String,
+			RateAsOf:    rateAsOf.Time,
+		}
+		if t.FX.Spread, err = money.Parse(spread.String, toCurrency); err != nil {
+			return nil, fmt.Errorf("invalid FX spread for transfer %d: %w", t.ID, err)
+		}
+	}
+	t.OutboundTransactionID = nullIntPtr(outboundID)
+	t.InboundTransactionID = nullIntPtr(inboundID)
+	t.JournalEntryID = nullIntPtr(journalEntryID)
+	if requestedBy.Valid {
+		t.Approval = &models.TransferApproval{
+			RequestedBy: int(requestedBy.Int64),
+			ExpiresAt:   approvalExpiresAt.Time,
+			HoldID:      nullIntPtr(holdID),
+			DecidedBy:   nullIntPtr(decidedBy),
+			DecidedAt:   nullTimePtr(decidedAt),
+			Reason:      decisionReason.String,
+		}
+	}
+	return &t, nil
+}
+
+// CreateTransfer inserts a new transfer within the given transaction.
+func (r *transferRepositoryImpl) CreateTransfer(tx *sql.Tx, transfer *models.Transfer) (int64, error) {
+	var midRate, appliedRate, rateSource, spread, rateAsOf interface{}
+	if transfer.FX != nil {
+		midRate, appliedRate, rateSource, spread, rateAsOf = transfer.FX.MidRate, transfer.FX.AppliedRate, transfer.FX.RateSource, transfer.FX.Spread, transfer.FX.RateAsOf
+	}
+	var requestedBy, approvalExpiresAt, holdID interface{}
+	if transfer.Approval != nil {
+		requestedBy, approvalExpiresAt, holdID = transfer.Approval.RequestedBy, transfer.Approval.ExpiresAt, intPtrArg(transfer.Approval.HoldID)
+	}
+	query := `INSERT INTO transfers (from_account_id, to_account_id, amount, destination_amount, fee,
+			fx_mid_rate, fx_applied_rate, fx_rate_source, fx_rate_as_of, fx_spread, description, status,
+			outbound_transaction_id, inbound_transaction_id, journal_entry_id,
+			requested_by, approval_expires_at, hold_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	id, err := r.dialect.InsertID(tx, query, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount, transfer.DestinationAmount, transfer.Fee,
+		midRate, appliedRate, rateSource, rateAsOf, spread, transfer.Description, transfer.Status,
+		intPtrArg(transfer.OutboundTransactionID), intPtrArg(transfer.InboundTransactionID), intPtrArg(transfer.JournalEntryID),
+		requestedBy, approvalExpiresAt, holdID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create transfer in the database: %w", err)
+	}
+	return id, nil
+}
+
+// GetTransferByID retrieves a transfer using its ID.
+func (r *transferRepositoryImpl) GetTransferByID(id int) (*models.Transfer, error) {
+	transfer, err := scanTransfer(r.db.QueryRow(r.dialect.Rebind(selectTransferColumns+" WHERE t.id = ?"), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTransferNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve transfer by ID: %w", err)
+	}
+	return transfer, nil
+}
+
+// GetTransferByIDForUpdate reads a transfer inside tx with SELECT ... FOR UPDATE, so concurrent
+// approval decisions on the same transfer are serialized.
+func (r *transferRepositoryImpl) GetTransferByIDForUpdate(tx *sql.Tx, id int) (*models.Transfer, error) {
+	transfer, err := scanTransfer(tx.QueryRow(r.dialect.Rebind(selectTransferColumns+" WHERE t.id = ?"+r.dialect.ForUpdate()), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTransferNotFound
+		}
+		return nil, fmt.Errorf("failed to retrieve and lock transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// UpdateTransfer stores the outcome of an approval decision: the status, the amounts, fee and FX
+// actually booked, the transaction and journal references, and who decided.
+func (r *transferRepositoryImpl) UpdateTransfer(tx *sql.Tx, transfer *models.Transfer) error {
+	var midRate, appliedRate, rateSource, spread, rateAsOf interface{}
+	if transfer.FX != nil {
+		midRate, appliedRate, rateSource, spread, rateAsOf = transfer.FX.MidRate, transfer.FX.AppliedRate, transfer.FX.RateSource, transfer.FX.Spread, transfer.FX.RateAsOf
+	}
+	var decidedBy, decidedAt, decisionReason interface{}
+	if transfer.Approval != nil {
+		decidedBy, decidedAt = intPtrArg(transfer.Approval.DecidedBy), timePtrArg(transfer.Approval.DecidedAt)
+		if transfer.Approval.Reason != "" {
+			decisionReason = transfer.Approval.Reason
+		}
+	}
+	query := `UPDATE transfers SET destination_amount = ?, fee = ?,
+			fx_mid_rate = ?, fx_applied_rate = ?, fx_rate_source = ?, fx_rate_as_of = ?, fx_spread = ?, status = ?,
+			outbound_transaction_id = ?, inbound_transaction_id = ?, journal_entry_id = ?,
+			decided_by = ?, decided_at = ?, decision_reason = ?
+		WHERE id = ?`
+	_, err := tx.Exec(r.dialect.Rebind(query), transfer.DestinationAmount, transfer.Fee,
+		midRate, appliedRate, rateSource, rateAsOf, spread, transfer.Status,
+		intPtrArg(transfer.OutboundTransactionID), intPtrArg(transfer.InboundTransactionID), intPtrArg(transfer.JournalEntryID),
+		decidedBy, decidedAt, decisionReason, transfer.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer: %w", err)
+	}
+	return nil
+}
+
+// GetTransferIDByJournalEntryID returns the ID of the transfer booked by a journal entry, or 0 if
+// the entry booked something else (a deposit, a withdrawal, ...).
+func (r *transferRepositoryImpl) GetTransferIDByJournalEntryID(journalEntryID int) (int, error) {
+	var id int
+	err := r.db.QueryRow(r.dialect.Rebind("SELECT id FROM transfers WHERE journal_entry_id = ?"), journalEntryID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up transfer of journal entry %d: %w", journalEntryID, err)
+	}
+	return id, nil
+}
+
+// GetPendingApprovalsForApprover retrieves the transfers awaiting approval from every account
+// userID is an approver of, oldest first.
+func (r *transferRepositoryImpl) GetPendingApprovalsForApprover(userID int) ([]models.Transfer, error) {
+	query := selectTransferColumns + `
+		JOIN account_approvers ap ON ap.account_id = t.from_account_id AND ap.user_id = ?
+		WHERE t.status = ? ORDER BY t.created_at, t.id`
+	return r.queryTransfers(query, userID, models.TransferPendingApproval)
+}
+
+// GetExpiredPendingTransferIDs returns up to limit pending transfers whose approval window has passed.
+func (r *transferRepositoryImpl) GetExpiredPendingTransferIDs(now time.Time, limit int) ([]int, error) {
+	query := "SELECT id FROM transfers WHERE status = ? AND approval_expires_at < ? ORDER BY approval_expires_at, id LIMIT ?"
+	rows, err := r.db.Query(r.dialect.Rebind(query), models.TransferPendingApproval, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch expired pending transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan expired pending transfer row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating expired pending transfers: %w", err)
+	}
+	return ids, nil
+}
+
+// GetTransfersByUserID retrieves every transfer sent from or received by one of the user's accounts, newest first.
+func (r *transferRepositoryImpl) GetTransfersByUserID(userID int) ([]models.Transfer, error) {
+	query := selectTransferColumns + " WHERE fa.user_id = ? OR ta.user_id = ? ORDER BY t.created_at DESC, t.id DESC"
+	return r.queryTransfers(query, userID, userID)
+}
+
+// queryTransfers runs a transfer list query.
+func (r *transferRepositoryImpl) queryTransfers(query string, args ...interface{}) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+	rows, err := r.db.Query(r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transfers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer row: %w", err)
+		}
+		transfers = append(transfers, *t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating transfer rows: %w", err)
+	}
+	return transfers, nil
+}
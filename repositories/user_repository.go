@@ -3,6 +3,7 @@ package repositories
 
 import (
 	"database/sql" // Untuk akses ke config.DB
+	"go-bank-app/dialect"
 	"go-bank-app/models"
 )
 
@@ -12,52 +13,70 @@ type UserRepository interface {
 	GetUserByID(id int) (*models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
 	GetAllUsers() ([]models.User, error)
+	UpdateUserRole(id int, role string) error
+	UpdatePreferredLanguage(id int, language string) error
 }
 
 // userRepositoryImpl adalah implementasi konkrit dari UserRepository.
 type userRepositoryImpl struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect.Dialect
 }
 
-// NewUserRepository membuat instance baru dari UserRepository.
-func NewUserRepository(db *sql.DB) UserRepository {
-	return &userRepositoryImpl{db: db}
+// NewUserRepository membuat instance baru dari UserRepository. Query ditulis dengan placeholder ?
+// dan diterjemahkan oleh d sesuai database yang dipakai.
+func NewUserRepository(db *sql.DB, d dialect.Dialect) UserRepository {
+	return &userRepositoryImpl{db: db, dialect: d}
 }
 
 func (r *userRepositoryImpl) CreateUser(user *models.User) (int64, error) {
-	query := "INSERT INTO users (name, email, password_hash) VALUES (?, ?, ?)"
-	result, err := r.db.Exec(query, user.Name, user.Email, user.PasswordHash)
+	if user.Role == "" {
+		user.Role = models.RoleCustomer
+	}
+	query := "INSERT INTO users (name, email, password_hash, role, preferred_language) VALUES (?, ?, ?, ?, ?)"
+	id, err := r.dialect.InsertID(r.db, query, user.Name, user.Email, user.PasswordHash, user.Role, user.PreferredLanguage)
 	if err != nil {
+		if r.dialect.IsDuplicateKey(err) {
+			return 0, ErrDuplicateEmail
+		}
 		return 0, err
 	}
-	return result.LastInsertId()
+	return id, nil
 }
 
 func (r *userRepositoryImpl) GetUserByID(id int) (*models.User, error) {
 	var user models.User
-	query := "SELECT id, name, email, password_hash, created_at, updated_at FROM users WHERE id = ?"
-	err := r.db.QueryRow(query, id).
-		Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	query := "SELECT id, name, email, password_hash, role, preferred_language, created_at, updated_at FROM users WHERE id = ?"
+	err := r.db.QueryRow(r.dialect.Rebind(query), id).
+		Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.PreferredLanguage, &user.CreatedAt, &user.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
+	user.Permissions = models.PermissionsForRole(user.Role)
 	return &user, nil
 }
 
 func (r *userRepositoryImpl) GetUserByEmail(email string) (*models.User, error) {
 	var user models.User
-	query := "SELECT id, name, email, password_hash FROM users WHERE email = ?"
-	err := r.db.QueryRow(query, email).
-		Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash)
+	query := "SELECT id, name, email, password_hash, role, preferred_language FROM users WHERE email = ?"
+	err := r.db.QueryRow(r.dialect.Rebind(query), email).
+		Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.PreferredLanguage)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
+	user.Permissions = models.PermissionsForRole(user.Role)
 	return &user, nil
 }
 
 func (r *userRepositoryImpl) GetAllUsers() ([]models.User, error) {
 	var users []models.User
-	rows, err := r.db.Query("SELECT id, name, email, created_at, updated_at FROM users")
+	rows, err := r.db.Query("SELECT id, name, email, role, preferred_language, created_at, updated_at FROM users")
 	if err != nil {
 		return nil, err
 	}
@@ -65,10 +84,11 @@ func (r *userRepositoryImpl) GetAllUsers() ([]models.User, error) {
 
 	for rows.Next() {
 		var user models.User
-		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.PreferredLanguage, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
 			return nil, err // Atau log dan continue
 		}
+		user.Permissions = models.PermissionsForRole(user.Role)
 		users = append(users, user)
 	}
 
@@ -77,3 +97,13 @@ func (r *userRepositoryImpl) GetAllUsers() ([]models.User, error) {
 	}
 	return users, nil
 }
+
+func (r *userRepositoryImpl) UpdateUserRole(id int, role string) error {
+	_, err := r.db.Exec(r.dialect.Rebind("UPDATE users SET role = ? WHERE id = ?"), role, id)
+	return err
+}
+
+func (r *userRepositoryImpl) UpdatePreferredLanguage(id int, language string) error {
+	_, err := r.db.Exec(r.dialect.Rebind("UPDATE users SET preferred_language = ? WHERE id = ?"), language, id)
+	return err
+}
@@ -6,20 +6,42 @@ import (
 
 	"go-bank-app/handlers"
 	"go-bank-app/middleware"
+	"go-bank-app/models"
+	"go-bank-app/repositories"
+	"go-bank-app/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 // InitHandlers and Services (akan diinisialisasi di main.go)
 var (
-	AuthHandler        *handlers.AuthHandler
-	UserHandler        *handlers.UserHandler
-	AccountHandler     *handlers.AccountHandler     // Belum dibuat, tapi placeholder
-	TransactionHandler *handlers.TransactionHandler // Belum dibuat, tapi placeholder
+	AuthHandler          *handlers.AuthHandler
+	UserHandler          *handlers.UserHandler
+	AccountHandler       *handlers.AccountHandler     // Belum dibuat, tapi placeholder
+	TransactionHandler   *handlers.TransactionHandler // Belum dibuat, tapi placeholder
+	TransferHandler      *handlers.TransferHandler
+	LedgerHandler        *handlers.LedgerHandler
+	StatementHandler     *handlers.StatementHandler
+	StandingOrderHandler *handlers.StandingOrderHandler
+	HoldHandler          *handlers.HoldHandler
+	LimitHandler         *handlers.LimitHandler
+	FeeHandler           *handlers.FeeHandler
+	InterestHandler      *handlers.InterestHandler
+	OverdraftHandler     *handlers.OverdraftHandler
+
+	IdempotencyRepo repositories.IdempotencyRepository // Penyimpanan Idempotency-Key untuk endpoint yang memindahkan uang
+	TokenService    services.TokenService              // Dipakai AuthMiddleware untuk memeriksa token yang dicabut
+	AuditService    services.AuditService              // Mencatat setiap akses admin/operator
 )
 
 // SetupRoutes mengatur semua rute API untuk aplikasi
 func SetupRoutes(router *gin.Engine) {
+	// Setiap request mendapat X-Request-ID; error dari handler dan middleware dijawab dengan envelope yang sama
+	router.Use(middleware.RequestIDMiddleware(), middleware.ErrorMiddleware())
+	router.NoRoute(func(c *gin.Context) {
+		middleware.Fail(c, middleware.NewAPIError(http.StatusNotFound, "route_not_found", "Route not found"))
+	})
+
 	// Rute Publik
 	router.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "pong"})
@@ -32,22 +54,75 @@ func SetupRoutes(router *gin.Engine) {
 	// Rute Autentikasi
 	router.POST("/auth/register", AuthHandler.RegisterUser)
 	router.POST("/auth/login", AuthHandler.LoginUser)
+	router.POST("/auth/refresh", AuthHandler.RefreshToken)
 
 	// Rute yang Dilindungi (memerlukan autentikasi JWT)
 	authenticated := router.Group("/")
-	authenticated.Use(middleware.AuthMiddleware())
+	authenticated.Use(middleware.AuthMiddleware(TokenService), middleware.AuditMiddleware(AuditService))
 	{
+		authenticated.POST("/auth/logout", AuthHandler.Logout)
+
+		// Endpoint yang memindahkan uang mendukung header Idempotency-Key
+		idempotent := middleware.IdempotencyMiddleware(IdempotencyRepo)
+
 		authenticated.GET("/users/:id", UserHandler.GetUserByID)
-		authenticated.GET("/users", UserHandler.GetAllUsers) // Akan memerlukan otorisasi peran admin
+		authenticated.GET("/users", middleware.RequirePermission(models.PermUsersRead), UserHandler.GetAllUsers)
+		authenticated.PUT("/users/:id/role", middleware.RequirePermission(models.PermUsersManage), UserHandler.UpdateUserRole)
+		authenticated.PUT("/users/:id/language", UserHandler.UpdatePreferredLanguage)
 
 		// Account
 		authenticated.POST("/accounts", AccountHandler.CreateAccount)
 		authenticated.GET("/accounts/:id", AccountHandler.GetAccountByID)
-		authenticated.POST("/accounts/:id/deposit", AccountHandler.Deposit)
-		authenticated.POST("/accounts/:id/withdraw", AccountHandler.Withdraw)
+		authenticated.POST("/accounts/:id/deposit", idempotent, AccountHandler.Deposit)
+		authenticated.POST("/accounts/:id/withdraw", idempotent, AccountHandler.Withdraw)
+		authenticated.POST("/accounts/:id/close", AccountHandler.CloseAccount)
+		authenticated.GET("/accounts/:id/status-history", AccountHandler.GetStatusHistory)
+		authenticated.GET("/accounts/:id/limits", LimitHandler.GetAccountLimits)
+		authenticated.GET("/accounts/:id/fee-quote", FeeHandler.QuoteFee)
+		authenticated.GET("/accounts/:id/interest", InterestHandler.GetAccountInterest)
+		authenticated.GET("/accounts/:id/approvers", AccountHandler.GetApprovers)
+		authenticated.POST("/accounts/:id/approvers", AccountHandler.AddApprover)
+		authenticated.DELETE("/accounts/:id/approvers/:userId", AccountHandler.RemoveApprover)
+
+		// Siklus hidup akun (khusus operator/admin)
+		manageAccounts := middleware.RequirePermission(models.PermAccountsManage)
+		authenticated.POST("/accounts/:id/freeze", manageAccounts, AccountHandler.FreezeAccount)
+		authenticated.POST("/accounts/:id/unfreeze", manageAccounts, AccountHandler.UnfreezeAccount)
+		authenticated.POST("/accounts/:id/dormant", manageAccounts, AccountHandler.MarkAccountDormant)
+		authenticated.PUT("/accounts/:id/limits", manageAccounts, LimitHandler.UpdateAccountLimits)
+		authenticated.PUT("/accounts/:id/interest-plan", manageAccounts, InterestHandler.UpdateInterestPlan)
+		authenticated.PUT("/accounts/:id/overdraft", manageAccounts, OverdraftHandler.UpdateOverdraft)
+
+		// Hold dana: dibuat, di-capture dan dilepas oleh operator/admin; nasabah hanya melihat
+		manageHolds := middleware.RequirePermission(models.PermHoldsManage)
+		authenticated.GET("/accounts/:id/holds", HoldHandler.GetHolds)
+		authenticated.GET("/accounts/:id/holds/:holdId", HoldHandler.GetHold)
+		authenticated.POST("/accounts/:id/holds", manageHolds, idempotent, HoldHandler.CreateHold)
+		authenticated.POST("/accounts/:id/holds/:holdId/capture", manageHolds, idempotent, HoldHandler.CaptureHold)
+		authenticated.POST("/accounts/:id/holds/:holdId/release", manageHolds, HoldHandler.ReleaseHold)
 
 		// Transaction
-		authenticated.POST("/transactions/transfer", TransactionHandler.Transfer)
+		authenticated.POST("/transactions/transfer", idempotent, TransactionHandler.Transfer)
 		authenticated.GET("/accounts/:id/transactions", TransactionHandler.GetAccountTransactions)
+		authenticated.GET("/accounts/:id/statement", StatementHandler.GetStatement)
+		authenticated.POST("/transactions/:id/reverse", middleware.RequirePermission(models.PermTransactionsReverse), idempotent, TransactionHandler.ReverseTransaction)
+
+		// Standing order (transfer terjadwal/berulang)
+		authenticated.POST("/accounts/:id/standing-orders", idempotent, StandingOrderHandler.CreateStandingOrder)
+		authenticated.GET("/accounts/:id/standing-orders", StandingOrderHandler.GetStandingOrders)
+		authenticated.GET("/accounts/:id/standing-orders/:orderId", StandingOrderHandler.GetStandingOrder)
+		authenticated.PUT("/accounts/:id/standing-orders/:orderId", StandingOrderHandler.UpdateStandingOrder)
+		authenticated.DELETE("/accounts/:id/standing-orders/:orderId", StandingOrderHandler.CancelStandingOrder)
+		authenticated.GET("/accounts/:id/standing-orders/:orderId/executions", StandingOrderHandler.GetExecutions)
+
+		// Transfer
+		authenticated.GET("/transfers", TransferHandler.GetTransfers)
+		authenticated.GET("/transfers/pending-approvals", TransferHandler.GetPendingApprovals)
+		authenticated.GET("/transfers/:id", TransferHandler.GetTransferByID)
+		authenticated.POST("/transfers/:id/approve", idempotent, TransferHandler.ApproveTransfer)
+		authenticated.POST("/transfers/:id/reject", TransferHandler.RejectTransfer)
+
+		// Ledger
+		authenticated.GET("/ledger/trial-balance", middleware.RequirePermission(models.PermLedgerRead), LedgerHandler.GetTrialBalance)
 	}
 }
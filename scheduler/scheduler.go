@@ -0,0 +1,111 @@
+// go-bank-app/scheduler/scheduler.go
+
+// Package scheduler runs background jobs at a fixed interval inside the API process. Each run
+// first takes a database lease named after the job, so when several app instances run side by
+// side only one of them executes a given job at a time.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go-bank-app/repositories"
+)
+
+// Job is a unit of background work.
+type Job struct {
+	Name     string        // Also the lease name; must be unique
+	Interval time.Duration // Time between runs
+	LeaseTTL time.Duration // How long one run may hold the lease; defaults to 5 minutes
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs until its context is cancelled.
+type Scheduler struct {
+	leases repositories.LeaseRepository
+	owner  string
+	jobs   []Job
+}
+
+// New creates a scheduler that identifies itself to the lease table as owner.
+func New(leases repositories.LeaseRepository, owner string) *Scheduler {
+	return &Scheduler{leases: leases, owner: owner}
+}
+
+// DefaultOwner identifies this process as "<hostname>-<pid>".
+func DefaultOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// Register adds a job. It must be called before Start.
+func (s *Scheduler) Register(job Job) {
+	if job.LeaseTTL <= 0 {
+		job.LeaseTTL = 5 * time.Minute
+	}
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches one goroutine per job and returns immediately.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		s.runOnce(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce executes job if this instance can take its lease. Errors and panics are logged, never
+// propagated, so one failing run does not stop the schedule.
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	acquired, err := s.leases.TryAcquireLease(job.Name, s.owner, newToken(), job.LeaseTTL)
+	if err != nil {
+		log.Printf("scheduler: %s: %v", job.Name, err)
+		return
+	}
+	if !acquired {
+		return // Another instance is running this job
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("scheduler: %s panicked: %v", job.Name, r)
+		}
+		if err := s.leases.ReleaseLease(job.Name, s.owner); err != nil {
+			log.Printf("scheduler: %s: %v", job.Name, err)
+		}
+	}()
+
+	runCtx, cancel := context.WithTimeout(ctx, job.LeaseTTL)
+	defer cancel()
+	if err := job.Run(runCtx); err != nil {
+		log.Printf("scheduler: %s failed: %v", job.Name, err)
+	}
+}
+
+// newToken returns a random value that makes every lease acquisition a distinct row update.
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"go-bank-app/models"
+)
+
+// ErrInvalidApprover is returned when a user cannot be made an approver of an account.
+var ErrInvalidApprover = errors.New("invalid approver")
+
+// AddApprover lets userID approve large transfers from accountID. The owner initiates transfers,
+// so making them their own checker would defeat the purpose.
+func (s *accountServiceImpl) AddApprover(accountID, userID, actorUserID int) ([]models.AccountApprover, error) {
+	account, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account: %w", err)
+	}
+	if account.UserID == userID {
+		return nil, fmt.Errorf("the account owner cannot approve their own transfers: %w", ErrInvalidApprover)
+	}
+	err = s.accountRepo.AddApprover(&models.AccountApprover{AccountID: accountID, UserID: userID, AddedBy: actorUserID})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetApprovers(accountID)
+}
+
+// RemoveApprover revokes userID's approval right. Transfers already pending stay pending, but
+// userID can no longer decide them.
+func (s *accountServiceImpl) RemoveApprover(accountID, userID int) ([]models.AccountApprover, error) {
+	if err := s.accountRepo.RemoveApprover(accountID, userID); err != nil {
+		return nil, err
+	}
+	return s.GetApprovers(accountID)
+}
+
+func (s *accountServiceImpl) GetApprovers(accountID int) ([]models.AccountApprover, error) {
+	approvers, err := s.accountRepo.GetApprovers(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account approvers: %w", err)
+	}
+	if approvers == nil {
+		approvers = []models.AccountApprover{}
+	}
+	return approvers, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
+
+var (
+	// ErrAccountNotDebitable is returned when money would leave an account whose status forbids it
+	// (frozen, dormant or closed).
+	ErrAccountNotDebitable = errors.New("account status does not allow debits")
+	// ErrAccountNotCreditable is returned when money would enter a closed account.
+	ErrAccountNotCreditable = errors.New("account status does not allow credits")
+	// ErrInvalidStatusTransition is returned when the account state machine forbids a change.
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+	// ErrNonZeroBalance is returned when closing an account that still holds money and no sweep target was given.
+	ErrNonZeroBalance = errors.New("account balance must be zero or swept before closing")
+)
+
+// checkDebit rejects a debit against an account whose status does not allow money to leave.
+func checkDebit(account *models.Account) error {
+	if !account.Status.AllowsDebit() {
+		return fmt.Errorf("account %s is %s: %w", account.AccountNumber, account.Status, ErrAccountNotDebitable)
+	}
+	return nil
+}
+
+// checkCredit rejects a credit to an account whose status does not allow money to enter.
+func checkCredit(account *models.Account) error {
+	if !account.Status.AllowsCredit() {
+		return fmt.Errorf("account %s is %s: %w", account.AccountNumber, account.Status, ErrAccountNotCreditable)
+	}
+	return nil
+}
+
+// setStatus moves a locked account to a new status and records the change in its history.
+func (s *accountServiceImpl) setStatus(tx *sql.Tx, account *models.Account, to models.AccountStatus, reason string, actorUserID int) error {
+	if !account.Status.CanTransitionTo(to) {
+		return fmt.Errorf("cannot change account from %s to %s: %w", account.Status, to, ErrInvalidStatusTransition)
+	}
+	if err := s.accountRepo.UpdateAccountStatus(tx, account.ID, to); err != nil {
+		return err
+	}
+	_, err := s.accountRepo.CreateStatusChange(tx, &models.AccountStatusChange{
+		AccountID:   account.ID,
+		FromStatus:  account.Status,
+		ToStatus:    to,
+		Reason:      reason,
+		ActorUserID: actorUserID,
+	})
+	if err != nil {
+		return err
+	}
+	account.Status = to
+	return nil
+}
+
+// ChangeAccountStatus moves an account to a new status (freeze, unfreeze, dormant).
+// Closing goes through CloseAccount, which also settles the remaining balance.
+func (s *accountServiceImpl) ChangeAccountStatus(accountID int, to models.AccountStatus, reason string, actorUserID int) (*models.Account, error) {
+	if to == models.AccountClosed {
+		return nil, fmt.Errorf("use CloseAccount to close an account: %w", ErrInvalidStatusTransition)
+	}
+
+	err := runInTx(func(tx *sql.Tx) error {
+		account, err := s.accountRepo.GetAccountByIDForUpdate(tx, accountID)
+		if err != nil {
+			return fmt.Errorf("account not found: %w", err)
+		}
+		return s.setStatus(tx, account, to, reason, actorUserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updatedAccount, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("status changed, but failed to retrieve updated account: %w", err)
+	}
+	return updatedAccount, nil
+}
+
+// CloseAccount closes an account permanently. Interest accrued since the last month end is
+// capitalized first, then, if the account still holds money, the balance is swept to
+// req.SweepToAccountNumber, all in the same DB transaction, so the account is never closed with
+// funds or interest stranded in it and the sweep never happens without the close.
+func (s *accountServiceImpl) CloseAccount(accountID int, req *models.CloseAccountRequest, actorUserID int) (*models.Account, error) {
+	// Resolve the sweep target first (without locks) so both rows can be locked by ID below
+	var sweepRef *models.Account
+	if req.SweepToAccountNumber != "" {
+		target, err := s.accountRepo.GetAccountByNumber(req.SweepToAccountNumber)
+		if err != nil {
+			return nil, fmt.Errorf("sweep account not found: %w", err)
+		}
+		if target.ID == accountID {
+			return nil, fmt.Errorf("cannot sweep an account into itself: %w", ErrSameAccount)
+		}
+		sweepRef = target
+	}
+
+	err := runInTx(func(tx *sql.Tx) error {
+		ids := []int{accountID}
+		if sweepRef != nil {
+			ids = append(ids, sweepRef.ID)
+		}
+		locked, err := lockAccountsInOrder(tx, s.accountRepo, ids...)
+		if err != nil {
+			return err
+		}
+		account := locked[accountID]
+
+		if !account.Status.CanTransitionTo(models.AccountClosed) {
+			return fmt.Errorf("cannot close a %s account: %w", account.Status, ErrInvalidStatusTransition)
+		}
+		if !account.HeldAmount.IsZero() {
+			return fmt.Errorf("account %s has %s %s on hold: %w", account.AccountNumber, account.HeldAmount, account.Currency, ErrFundsOnHold)
+		}
+
+		// Closed accounts are left out of the monthly capitalization, so pay what is pending now
+		capitalized, err := s.interest.capitalizeOutstanding(tx, account)
+		if err != nil {
+			return err
+		}
+		if capitalized {
+			if account, err = s.accountRepo.GetAccountByIDForUpdate(tx, accountID); err != nil {
+				return err
+			}
+		}
+
+		if !account.Balance.IsZero() {
+			if sweepRef == nil {
+				return fmt.Errorf("account %s holds %s %s: %w", account.AccountNumber, account.Balance, account.Balance.Currency(), ErrNonZeroBalance)
+			}
+			if account.Balance.IsNegative() {
+				return fmt.Errorf("account %s has a negative balance: %w", account.AccountNumber, ErrNonZeroBalance)
+			}
+			target := locked[sweepRef.ID]
+			if err := checkCredit(target); err != nil {
+				return err
+			}
+			if !target.Balance.SameCurrency(account.Balance) {
+				return fmt.Errorf("invalid sweep account: %w", money.ErrCurrencyMismatch)
+			}
+			// The sweep is a normal internal transfer, except that it is allowed out of a frozen or
+			// dormant account: closing is the one debit those states permit.
+			// No fee is charged on the sweep
+			sweep := &transferPlan{from: account, to: target, amount: account.Balance}
+			_, err := s.bookings.book(tx, sweep, "Account closure sweep: "+req.Reason)
+			if err != nil {
+				return fmt.Errorf("failed to sweep balance: %w", err)
+			}
+		}
+
+		return s.setStatus(tx, account, models.AccountClosed, req.Reason, actorUserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	closedAccount, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("account closed, but failed to retrieve it: %w", err)
+	}
+	return closedAccount, nil
+}
+
+func (s *accountServiceImpl) GetStatusHistory(accountID int) ([]models.AccountStatusChange, error) {
+	history, err := s.accountRepo.GetStatusHistory(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account status history: %w", err)
+	}
+	return history, nil
+}
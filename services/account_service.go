@@ -3,8 +3,10 @@ package services
 import (
 	"database/sql"
 	"fmt"
-	"go-bank-app/config" // For accessing config.DB.Begin()
+	"go-bank-app/config"
+	"go-bank-app/fees"
 	"go-bank-app/models"
+	"go-bank-app/money"
 	"go-bank-app/repositories"
 )
 
@@ -13,19 +15,38 @@ type AccountService interface {
 	CreateAccount(req *models.CreateAccountRequest) (*models.Account, error)
 	GetAccountByID(id int) (*models.Account, error)
 	GetAccountByNumber(accountNumber string) (*models.Account, error)
-	Deposit(accountID int, amount float64) (*models.Account, error)
-	Withdraw(accountID int, amount float64) (*models.Account, error)
+	Deposit(accountID int, amount money.Money) (*models.Account, error)
+	Withdraw(accountID int, amount money.Money) (*models.Account, error)
+	ChangeAccountStatus(accountID int, to models.AccountStatus, reason string, actorUserID int) (*models.Account, error)
+	CloseAccount(accountID int, req *models.CloseAccountRequest, actorUserID int) (*models.Account, error)
+	GetStatusHistory(accountID int) ([]models.AccountStatusChange, error)
+	AddApprover(accountID, userID, actorUserID int) ([]models.AccountApprover, error)
+	RemoveApprover(accountID, userID int) ([]models.AccountApprover, error)
+	GetApprovers(accountID int) ([]models.AccountApprover, error)
 }
 
 // accountServiceImpl is the concrete implementation of AccountService.
 type accountServiceImpl struct {
 	accountRepo     repositories.AccountRepository
 	transactionRepo repositories.TransactionRepository // Needed for Deposit/Withdraw
+	ledger          *ledger                            // Every balance change is posted through the ledger
+	limits          *limitChecker                      // Debit limits for withdrawals
+	fees            *fees.Schedule                     // Withdrawal fees; nil charges none
+	bookings        *transferBookings                  // Used to sweep the balance when an account is closed
+	interest        *interestBookings                  // Used to pay pending interest when an account is closed
 }
 
 // NewAccountService creates a new instance of AccountService.
-func NewAccountService(accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository) AccountService {
-	return &accountServiceImpl{accountRepo: accountRepo, transactionRepo: transactionRepo}
+func NewAccountService(accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository, transferRepo repositories.TransferRepository, ledgerRepo repositories.LedgerRepository, holdRepo repositories.HoldRepository, limitRepo repositories.LimitRepository, interestRepo repositories.InterestRepository, feeSchedule *fees.Schedule) AccountService {
+	return &accountServiceImpl{
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		ledger:          newLedger(ledgerRepo, accountRepo),
+		limits:          newLimitChecker(limitRepo),
+		fees:            feeSchedule,
+		bookings:        newTransferBookings(accountRepo, transactionRepo, transferRepo, ledgerRepo, holdRepo, limitRepo, nil, nil), // Sweeps are same-currency and free
+		interest:        newInterestBookings(interestRepo, accountRepo, transactionRepo, ledgerRepo),
+	}
 }
 
 func (s *accountServiceImpl) CreateAccount(req *models.CreateAccountRequest) (*models.Account, error) {
@@ -33,10 +54,37 @@ func (s *accountServiceImpl) CreateAccount(req *models.CreateAccountRequest) (*m
 	// or just rely on the foreign key constraint in the DB.
 	// For now, we assume the handler validates the logged-in user ID.
 
+	currency := money.DefaultCurrency
+	if req.Currency != "" {
+		c, err := money.ParseCurrency(req.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account currency: %w", err)
+		}
+		currency = c
+	}
+
+	accountNumber, err := s.nextAccountNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	accountType := models.AccountTypeChecking
+	if req.Type != "" {
+		if !req.Type.IsValid() {
+			return nil, fmt.Errorf("%w %q", ErrInvalidAccountType, req.Type)
+		}
+		accountType = req.Type
+	}
+
+	plan := config.DefaultInterestPlan(accountType)
 	account := &models.Account{
 		UserID:        req.UserID,
-		AccountNumber: req.AccountNumber,
-		Balance:       0.00, // Initial balance
+		AccountNumber: accountNumber,
+		Type:          accountType,
+		Currency:      currency,
+		Balance:       money.Zero(currency), // Initial balance
+		InterestRate:  plan.RatePercent(),
+		DayCount:      plan.DayCount,
 	}
 
 	id, err := s.accountRepo.CreateAccount(account)
@@ -53,6 +101,21 @@ func (s *accountServiceImpl) CreateAccount(req *models.CreateAccountRequest) (*m
 	return newAccount, nil
 }
 
+// nextAccountNumber allocates a sequence number for the configured branch and formats it with
+// its check digits.
+func (s *accountServiceImpl) nextAccountNumber() (string, error) {
+	scheme := config.AccountNumberScheme()
+	seq, err := s.accountRepo.NextAccountSequence(scheme.BranchPrefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate account number: %w", err)
+	}
+	accountNumber, err := scheme.Format(seq)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate account number: %w", err)
+	}
+	return accountNumber, nil
+}
+
 func (s *accountServiceImpl) GetAccountByID(id int) (*models.Account, error) {
 	account, err := s.accountRepo.GetAccountByID(id)
 	if err != nil {
@@ -63,41 +126,62 @@ func (s *accountServiceImpl) GetAccountByID(id int) (*models.Account, error) {
 func (s *accountServiceImpl) GetAccountByNumber(accountNumber string) (*models.Account, error) {
 	account, err := s.accountRepo.GetAccountByNumber(accountNumber)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("akun tidak ditemukan")
-		}
-		return nil, fmt.Errorf("gagal mengambil akun berdasarkan nomor: %w", err)
+		return nil, fmt.Errorf("failed to retrieve account by number: %w", err)
 	}
 	return account, nil
 }
-func (s *accountServiceImpl) Deposit(accountID int, amount float64) (*models.Account, error) {
-	tx, err := config.DB.Begin() // Begin transaction at the service layer
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin deposit transaction: %w", err)
-	}
-	defer tx.Rollback() // Ensure rollback on error
+func (s *accountServiceImpl) Deposit(accountID int, amount money.Money) (*models.Account, error) {
+	// The transaction is retried automatically on deadlock / lock wait timeout
+	err := runInTx(func(tx *sql.Tx) error {
+		// Lock the account row so the balance update and the transaction record stay consistent
+		account, err := s.accountRepo.GetAccountByIDForUpdate(tx, accountID)
+		if err != nil {
+			return fmt.Errorf("account not found: %w", err)
+		}
+		if err := checkCredit(account); err != nil {
+			return err
+		}
 
-	// Update account balance
-	err = s.accountRepo.UpdateAccountBalance(tx, accountID, amount) // Positive amount for deposit
-	if err != nil {
-		return nil, fmt.Errorf("failed to update balance during deposit: %w", err)
-	}
+		// Bind the amount to the account currency; this rejects amounts more precise than the currency allows
+		amount, err := amount.In(account.Currency)
+		if err != nil {
+			return fmt.Errorf("invalid deposit amount: %w", err)
+		}
 
-	// Record the transaction
-	transaction := &models.Transaction{
-		AccountID:       accountID,
-		TransactionType: "deposit",
-		Amount:          amount,
-		Description:     "Deposit funds",
-	}
-	_, err = s.transactionRepo.CreateTransaction(tx, transaction)
-	if err != nil {
-		return nil, fmt.Errorf("failed to record deposit transaction: %w", err)
-	}
+		// Post to the ledger: cash comes into the vault and the bank owes the customer more.
+		// Posting also credits the account balance.
+		customerLedger, err := s.ledger.customerAccount(tx, account)
+		if err != nil {
+			return err
+		}
+		vault, err := s.ledger.systemAccount(tx, models.LedgerCashVault, amount.Currency())
+		if err != nil {
+			return err
+		}
+		entryID, err := s.ledger.post(tx, &models.JournalEntry{
+			Description: "Deposit funds",
+			Postings:    []models.Posting{models.Debit(vault, amount), models.Credit(customerLedger, amount)},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to post deposit: %w", err)
+		}
 
-	err = tx.Commit() // Commit transaction
+		// Record the transaction
+		transaction := &models.Transaction{
+			AccountID:       accountID,
+			TransactionType: models.TransactionDeposit,
+			Amount:          amount,
+			Description:     "Deposit funds",
+			JournalEntryID:  entryID,
+		}
+		_, err = s.transactionRepo.CreateTransaction(tx, transaction)
+		if err != nil {
+			return fmt.Errorf("failed to record deposit transaction: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to commit deposit transaction: %w", err)
+		return nil, err
 	}
 
 	// Fetch and return the updated account
@@ -108,44 +192,92 @@ func (s *accountServiceImpl) Deposit(accountID int, amount float64) (*models.Acc
 	return updatedAccount, nil
 }
 
-func (s *accountServiceImpl) Withdraw(accountID int, amount float64) (*models.Account, error) {
-	tx, err := config.DB.Begin() // Begin transaction at the service layer
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin withdrawal transaction: %w", err)
-	}
-	defer tx.Rollback() // Ensure rollback on error
-
-	// Retrieve account to check balance
-	account, err := s.accountRepo.GetAccountByID(accountID) // Locks row if using FOR UPDATE
-	if err != nil {
-		return nil, fmt.Errorf("account not found or failed to fetch balance: %w", err)
-	}
+func (s *accountServiceImpl) Withdraw(accountID int, amount money.Money) (*models.Account, error) {
+	// The transaction is retried automatically on deadlock / lock wait timeout
+	err := runInTx(func(tx *sql.Tx) error {
+		// Lock the account row: concurrent withdrawals queue here, so each one sees the balance
+		// left by the previous one and the check below cannot be raced
+		account, err := s.accountRepo.GetAccountByIDForUpdate(tx, accountID)
+		if err != nil {
+			return fmt.Errorf("account not found or failed to fetch balance: %w", err)
+		}
+		if err := checkDebit(account); err != nil {
+			return err
+		}
 
-	if account.Balance < amount {
-		return nil, fmt.Errorf("insufficient balance")
-	}
+		amount, err := amount.In(account.Currency)
+		if err != nil {
+			return fmt.Errorf("invalid withdrawal amount: %w", err)
+		}
 
-	// Update account balance (negative amount for withdrawal)
-	err = s.accountRepo.UpdateAccountBalance(tx, accountID, -amount)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update balance during withdrawal: %w", err)
-	}
+		// Held funds stay in the balance but cannot be withdrawn. The fees must be covered too,
+		// but they do not count towards the debit limits.
+		fee := s.fees.Fee(fees.OpWithdraw, string(account.Type), amount)
+		odFee := overdraftFee(account, amount.Add(fee))
+		if account.AvailableBalance.LessThan(amount.Add(fee).Add(odFee)) {
+			return fmt.Errorf("insufficient balance: %w", ErrInsufficientFunds)
+		}
+		if err := s.limits.check(tx, account, amount, false); err != nil {
+			return err
+		}
 
-	// Record the transaction
-	transaction := &models.Transaction{
-		AccountID:       accountID,
-		TransactionType: "withdraw",
-		Amount:          amount,
-		Description:     "Withdrawal funds",
-	}
-	_, err = s.transactionRepo.CreateTransaction(tx, transaction)
-	if err != nil {
-		return nil, fmt.Errorf("failed to record withdrawal transaction: %w", err)
-	}
+		// Post to the ledger: cash leaves the vault and the bank owes the customer less, and the
+		// fee moves to fee income. Posting also debits the account balance.
+		customerLedger, err := s.ledger.customerAccount(tx, account)
+		if err != nil {
+			return err
+		}
+		vault, err := s.ledger.systemAccount(tx, models.LedgerCashVault, amount.Currency())
+		if err != nil {
+			return err
+		}
+		postings := []models.Posting{models.Debit(customerLedger, amount), models.Credit(vault, amount)}
+		if !fee.IsZero() {
+			charged, err := feePostings(tx, s.ledger, customerLedger, fee)
+			if err != nil {
+				return err
+			}
+			postings = append(postings, charged...)
+		}
+		if !odFee.IsZero() {
+			charged, err := feePostings(tx, s.ledger, customerLedger, odFee)
+			if err != nil {
+				return err
+			}
+			postings = append(postings, charged...)
+		}
+		entryID, err := s.ledger.post(tx, &models.JournalEntry{
+			Description: "Withdrawal funds",
+			Postings:    postings,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to post withdrawal: %w", err)
+		}
 
-	err = tx.Commit() // Commit transaction
+		// Record the transaction
+		transaction := &models.Transaction{
+			AccountID:       accountID,
+			TransactionType: models.TransactionWithdraw,
+			Amount:          amount,
+			Description:     "Withdrawal funds",
+			JournalEntryID:  entryID,
+		}
+		_, err = s.transactionRepo.CreateTransaction(tx, transaction)
+		if err != nil {
+			return fmt.Errorf("failed to record withdrawal transaction: %w", err)
+		}
+		if !fee.IsZero() {
+			if err := recordFee(tx, s.transactionRepo, account, fee, entryID, "Withdrawal fee"); err != nil {
+				return err
+			}
+		}
+		if !odFee.IsZero() {
+			return recordFee(tx, s.transactionRepo, account, odFee, entryID, "Overdraft fee")
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to commit withdrawal transaction: %w", err)
+		return nil, err
 	}
 
 	// Fetch and return the updated account
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"fmt"
+
+	"go-bank-app/models"
+	"go-bank-app/repositories"
+)
+
+// AuditService defines the interface for recording privileged access.
+type AuditService interface {
+	Record(entry *models.AuditLog) error
+}
+
+// auditServiceImpl is the concrete implementation of AuditService.
+type auditServiceImpl struct {
+	auditRepo repositories.AuditRepository
+}
+
+// NewAuditService creates a new instance of AuditService.
+func NewAuditService(auditRepo repositories.AuditRepository) AuditService {
+	return &auditServiceImpl{auditRepo: auditRepo}
+}
+
+func (s *auditServiceImpl) Record(entry *models.AuditLog) error {
+	if _, err := s.auditRepo.CreateAuditLog(entry); err != nil {
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/dialect"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/services"
+)
+
+// TestConcurrentDebitsConserveMoney hammers two accounts with concurrent withdrawals and transfers
+// in both directions. Balances must never go negative, no money may be created or lost, and every
+// operation must finish: a lock-ordering bug shows up as a deadlock error or a timeout.
+//
+// It needs a server database (TEST_DB_DRIVER=mysql or postgres); TestLockAccountsInOrder covers
+// the lock order itself on every run.
+func TestConcurrentDebitsConserveMoney(t *testing.T) {
+	workers, ops := 16, 40
+	if testing.Short() {
+		workers, ops = 4, 20
+	}
+	s := newTestServices(t)
+	if config.Dialect().Name() == dialect.SQLite {
+		// _txlock=immediate makes SQLite run writers one at a time and it has no FOR UPDATE, so the
+		// test would pass without any row locking and never reach the deadlock retry in runInTx
+		t.Skip("row locking cannot be exercised on SQLite; set TEST_DB_DRIVER and TEST_DB_DSN to a MySQL or PostgreSQL database")
+	}
+	accountService, transactionService := s.accountService, s.transactionService // No fees: the money invariant below assumes none
+	userID := s.createUser(t, fmt.Sprintf("concurrency-%d@example.test", time.Now().UnixNano()))
+
+	initial := money.MustParse("1000.00", money.DefaultCurrency)
+	var accounts [2]*models.Account
+	for i := range accounts {
+		accounts[i] = s.createAccount(t, userID, models.AccountTypeChecking, initial)
+		// Lift the debit limits on the test accounts: this measures locking, not limits
+		s.liftLimits(t, accounts[i].ID, workers*ops)
+	}
+
+	var (
+		withdrawn atomic.Int64 // Minor units successfully withdrawn
+		succeeded atomic.Int64
+		rejected  atomic.Int64
+		wg        sync.WaitGroup
+	)
+	errs := make(chan error, workers*ops)
+
+	// Sample balances while the workers run so a transient overdraft is caught too, not just one
+	// that survives to the end
+	done := make(chan struct{})
+	sampled := make(chan error, 1)
+	go func() {
+		defer close(sampled)
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+			for _, acc := range accounts {
+				current, err := s.accountRepo.GetAccountByID(acc.ID)
+				if err == nil && current.Balance.IsNegative() {
+					sampled <- fmt.Errorf("observed negative balance %s on %s", current.Balance, current.AccountNumber)
+					return
+				}
+			}
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(w)))
+			for i := 0; i < ops; i++ {
+				amount := money.New(int64(rng.Intn(5000)+1), money.DefaultCurrency)
+				from, to := accounts[rng.Intn(2)], accounts[0]
+				if from == accounts[0] {
+					to = accounts[1]
+				}
+
+				var err error
+				if rng.Intn(2) == 0 {
+					_, err = accountService.Withdraw(from.ID, amount)
+					if err == nil {
+						withdrawn.Add(amount.MinorUnits())
+					}
+				} else {
+					_, err = transactionService.Transfer(&models.TransferRequest{
+						FromAccountID: from.AccountNumber,
+						ToAccountID:   to.AccountNumber,
+						Amount:        amount,
+						Description:   "concurrency",
+					}, userID)
+				}
+
+				switch {
+				case err == nil:
+					succeeded.Add(1)
+				case errors.Is(err, services.ErrInsufficientFunds):
+					rejected.Add(1)
+				default:
+					errs <- err
+				}
+			}
+		}(w)
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Minute):
+		t.Fatal("workers did not finish within 2 minutes; suspected deadlock")
+	}
+	close(done)
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := <-sampled; err != nil {
+		t.Error(err)
+	}
+
+	total := money.Zero(money.DefaultCurrency)
+	for _, acc := range accounts {
+		final, err := s.accountRepo.GetAccountByID(acc.ID)
+		if err != nil {
+			t.Fatalf("reload account: %v", err)
+		}
+		if final.Balance.IsNegative() {
+			t.Errorf("account %s ended negative at %s", final.AccountNumber, final.Balance)
+		}
+		total = total.Add(final.Balance)
+	}
+	expected := initial.Add(initial).Sub(money.New(withdrawn.Load(), money.DefaultCurrency))
+	if total.Cmp(expected) != 0 {
+		t.Errorf("total balance %s, want %s", total, expected)
+	}
+	if succeeded.Load() == 0 {
+		t.Error("no operation succeeded")
+	}
+	t.Logf("succeeded=%d rejected_insufficient=%d", succeeded.Load(), rejected.Load())
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"errors"
+
+	"go-bank-app/repositories"
+)
+
+// Lookups that find nothing and inserts that hit a unique key. They are raised by the
+// repositories and re-exported here so handlers depend on services alone.
+var (
+	ErrUserNotFound           = repositories.ErrUserNotFound
+	ErrAccountNotFound        = repositories.ErrAccountNotFound
+	ErrTransactionNotFound    = repositories.ErrTransactionNotFound
+	ErrTransferNotFound       = repositories.ErrTransferNotFound
+	ErrHoldNotFound           = repositories.ErrHoldNotFound
+	ErrStandingOrderNotFound  = repositories.ErrStandingOrderNotFound
+	ErrApproverNotFound       = repositories.ErrApproverNotFound
+	ErrDuplicateEmail         = repositories.ErrDuplicateEmail
+	ErrDuplicateAccountNumber = repositories.ErrDuplicateAccountNumber
+)
+
+var (
+	// ErrInvalidCredentials is returned by LoginUser for an unknown email or a wrong password alike.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrInvalidRole is returned when a user is given a role that does not exist.
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrInvalidLanguage is returned for a preferred language that has no message catalog.
+	ErrInvalidLanguage = errors.New("unsupported language")
+	// ErrInvalidAccountType is returned when an account is opened with an unknown type.
+	ErrInvalidAccountType = errors.New("invalid account type")
+	// ErrSameAccount is returned when money would move from an account into itself.
+	ErrSameAccount = errors.New("source and destination are the same account")
+)
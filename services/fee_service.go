@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"go-bank-app/fees"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// ErrUnknownFeeOperation is returned when a quote is requested for an operation that carries no fee.
+var ErrUnknownFeeOperation = errors.New("unknown fee operation")
+
+// FeeService quotes the fee of an operation before the customer commits to it.
+type FeeService interface {
+	QuoteFee(accountID int, operation string, amount money.Money) (*models.FeeQuote, error)
+}
+
+// feeServiceImpl is the concrete implementation of FeeService.
+type feeServiceImpl struct {
+	accountRepo repositories.AccountRepository
+	fees        *fees.Schedule // nil charges no fees
+}
+
+// NewFeeService creates a new instance of FeeService.
+func NewFeeService(accountRepo repositories.AccountRepository, feeSchedule *fees.Schedule) FeeService {
+	return &feeServiceImpl{accountRepo: accountRepo, fees: feeSchedule}
+}
+
+// QuoteFee returns the fee that withdrawing or transferring amount from an account would cost
+// right now. It uses the same schedule as the booking itself, so the quote matches the charge
+// as long as the schedule is not reloaded in between.
+func (s *feeServiceImpl) QuoteFee(accountID int, operation string, amount money.Money) (*models.FeeQuote, error) {
+	if operation != fees.OpWithdraw && operation != fees.OpTransfer {
+		return nil, fmt.Errorf("%w %q, want %s or %s", ErrUnknownFeeOperation, operation, fees.OpWithdraw, fees.OpTransfer)
+	}
+	account, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account: %w", err)
+	}
+	amount, err = amount.In(account.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quote amount: %w", err)
+	}
+
+	fee := s.fees.Fee(operation, string(account.Type), amount)
+	return &models.FeeQuote{
+		AccountID:   account.ID,
+		AccountType: account.Type,
+		Operation:   operation,
+		Amount:      amount,
+		Fee:         fee,
+		Total:       amount.Add(fee),
+	}, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	"go-bank-app/config"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// feePostings moves a fee from a customer ledger account to fee income. The postings join the
+// journal entry of the operation the fee is charged on, so reversing that entry refunds the fee.
+func feePostings(tx *sql.Tx, l *ledger, customerLedger *models.LedgerAccount, fee money.Money) ([]models.Posting, error) {
+	feesIncome, err := l.systemAccount(tx, models.LedgerFeesIncome, fee.Currency())
+	if err != nil {
+		return nil, err
+	}
+	return []models.Posting{models.Debit(customerLedger, fee), models.Credit(feesIncome, fee)}, nil
+}
+
+// overdraftFee returns the fee charged when a debit of amount takes an account from a zero or
+// positive balance into overdraft (see config.OverdraftFee). An account that is already overdrawn
+// is not charged again until it has been back in credit.
+func overdraftFee(account *models.Account, amount money.Money) money.Money {
+	if account.Balance.IsNegative() || !account.Balance.LessThan(amount) {
+		return money.Zero(account.Currency)
+	}
+	return config.OverdraftFee(account.Currency)
+}
+
+// recordFee records a fee charged to an account as its own fee transaction.
+func recordFee(tx *sql.Tx, transactionRepo repositories.TransactionRepository, account *models.Account, fee money.Money, journalEntryID int, description string) error {
+	_, err := transactionRepo.CreateTransaction(tx, &models.Transaction{
+		AccountID:       account.ID,
+		TransactionType: models.TransactionFee,
+		Amount:          fee,
+		Description:     description,
+		JournalEntryID:  journalEntryID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record fee transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/models"
+	"go-bank-app/repositories"
+)
+
+// HoldService manages funds holds: amounts reserved on an account without posting, which reduce
+// the available balance but not the ledger balance.
+type HoldService interface {
+	CreateHold(accountID int, req *models.CreateHoldRequest, actorUserID int) (*models.Hold, error)
+	GetHoldByID(id int) (*models.Hold, error)
+	GetHoldsByAccountID(accountID int, status string) ([]models.Hold, error)
+	CaptureHold(id int, req *models.CaptureHoldRequest) (*models.Hold, error)
+	ReleaseHold(id int) (*models.Hold, error)
+	ExpireHolds(ctx context.Context) error // Scheduler job: releases authorizations past their expiry
+}
+
+// holdServiceImpl is the concrete implementation of HoldService.
+type holdServiceImpl struct {
+	accountRepo     repositories.AccountRepository
+	transactionRepo repositories.TransactionRepository
+	holdRepo        repositories.HoldRepository
+	ledger          *ledger
+}
+
+// NewHoldService creates a new instance of HoldService.
+func NewHoldService(accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository, ledgerRepo repositories.LedgerRepository, holdRepo repositories.HoldRepository) HoldService {
+	return &holdServiceImpl{
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		holdRepo:        holdRepo,
+		ledger:          newLedger(ledgerRepo, accountRepo),
+	}
+}
+
+// CreateHold reserves an amount on an account. An authorization must fit in the available balance
+// of an account that can be debited, and expires after config.AppCfg.Hold.AuthorizationTTL unless
+// req.ExpiresAt says otherwise. A legal hold is placed regardless of the available balance (which
+// may go negative, blocking every debit) and lasts until released.
+func (s *holdServiceImpl) CreateHold(accountID int, req *models.CreateHoldRequest, actorUserID int) (*models.Hold, error) {
+	var holdID int
+	err := runInTx(func(tx *sql.Tx) error {
+		account, err := s.accountRepo.GetAccountByIDForUpdate(tx, accountID)
+		if err != nil {
+			return err
+		}
+		amount, err := req.Amount.In(account.Currency)
+		if err != nil {
+			return fmt.Errorf("invalid hold amount: %w", err)
+		}
+
+		hold := &models.Hold{
+			AccountID: account.ID,
+			Type:      req.Type,
+			Amount:    amount,
+			Reason:    req.Reason,
+			Reference: req.Reference,
+			ExpiresAt: req.ExpiresAt,
+			CreatedBy: actorUserID,
+		}
+		if hold.ExpiresAt != nil && !hold.ExpiresAt.After(time.Now()) {
+			return fmt.Errorf("expires_at must be in the future: %w", ErrInvalidHoldOperation)
+		}
+
+		switch req.Type {
+		case models.HoldTypeAuthorization:
+			if err := checkDebit(account); err != nil {
+				return err
+			}
+			if account.AvailableBalance.LessThan(amount) {
+				return fmt.Errorf("insufficient available balance for authorization: %w", ErrInsufficientFunds)
+			}
+			if hold.ExpiresAt == nil {
+				expiresAt := time.Now().Add(config.AppCfg.Hold.AuthorizationTTL)
+				hold.ExpiresAt = &expiresAt
+			}
+		case models.HoldTypeLegal:
+			if err := checkCredit(account); err != nil { // Closed accounts cannot be held
+				return err
+			}
+		default:
+			return fmt.Errorf("holds of type %q cannot be created directly: %w", req.Type, ErrInvalidHoldOperation)
+		}
+
+		holdID, err = placeHold(tx, s.accountRepo, s.holdRepo, hold)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetHoldByID(holdID)
+}
+
+func (s *holdServiceImpl) GetHoldByID(id int) (*models.Hold, error) {
+	hold, err := s.holdRepo.GetHoldByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve hold: %w", err)
+	}
+	return hold, nil
+}
+
+func (s *holdServiceImpl) GetHoldsByAccountID(accountID int, status string) ([]models.Hold, error) {
+	holds, err := s.holdRepo.GetHoldsByAccountID(accountID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holds: %w", err)
+	}
+	if holds == nil {
+		holds = []models.Hold{}
+	}
+	return holds, nil
+}
+
+// CaptureHold posts an authorization: the captured amount is debited from the account and owed
+// to card settlement, and the whole hold is removed from the held amount, so any uncaptured
+// remainder becomes available again. A hold can be captured once, and only while the account
+// still allows debits.
+func (s *holdServiceImpl) CaptureHold(id int, req *models.CaptureHoldRequest) (*models.Hold, error) {
+	err := s.withLockedHold(id, func(tx *sql.Tx, account *models.Account, hold *models.Hold) error {
+		if hold.Type != models.HoldTypeAuthorization {
+			return fmt.Errorf("only authorization holds can be captured: %w", ErrInvalidHoldOperation)
+		}
+		if hold.Status != models.HoldActive {
+			return fmt.Errorf("hold %d is %s: %w", hold.ID, hold.Status, ErrHoldNotActive)
+		}
+		if hold.ExpiresAt != nil && time.Now().After(*hold.ExpiresAt) {
+			return fmt.Errorf("hold %d has expired: %w", hold.ID, ErrHoldNotActive)
+		}
+		// An authorization placed before the account was frozen or closed stays reserved but cannot
+		// be settled until the account may be debited again
+		if err := checkDebit(account); err != nil {
+			return err
+		}
+
+		amount := hold.Amount
+		if req.Amount != nil {
+			var err error
+			if amount, err = req.Amount.In(account.Currency); err != nil {
+				return fmt.Errorf("invalid capture amount: %w", err)
+			}
+			if amount.GreaterThan(hold.Amount) {
+				return fmt.Errorf("capture amount exceeds the held %s: %w", hold.Amount, ErrInvalidHoldOperation)
+			}
+		}
+		// Every other debit is limited to the available balance, so the ledger balance (plus any
+		// overdraft) still covers the authorization; this only guards against a broken invariant
+		if account.Balance.Add(account.OverdraftLimit).LessThan(amount) {
+			return fmt.Errorf("balance does not cover the capture: %w", ErrInsufficientFunds)
+		}
+		// The overdraft fee was not part of the authorization, so it is charged even if it takes the
+		// account slightly past its limit
+		odFee := overdraftFee(account, amount)
+
+		description := req.Description
+		if description == "" {
+			description = "Capture: " + hold.Reason
+		}
+		customerLedger, err := s.ledger.customerAccount(tx, account)
+		if err != nil {
+			return err
+		}
+		settlement, err := s.ledger.systemAccount(tx, models.LedgerSettlement, account.Currency)
+		if err != nil {
+			return err
+		}
+		postings := []models.Posting{models.Debit(customerLedger, amount), models.Credit(settlement, amount)}
+		if !odFee.IsZero() {
+			charged, err := feePostings(tx, s.ledger, customerLedger, odFee)
+			if err != nil {
+				return err
+			}
+			postings = append(postings, charged...)
+		}
+		entryID, err := s.ledger.post(tx, &models.JournalEntry{
+			Reference:   hold.Reference,
+			Description: description,
+			Postings:    postings,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to post capture: %w", err)
+		}
+		transactionID, err := s.transactionRepo.CreateTransaction(tx, &models.Transaction{
+			AccountID:       account.ID,
+			TransactionType: models.TransactionCapture,
+			Amount:          amount,
+			Description:     description,
+			JournalEntryID:  entryID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record capture transaction: %w", err)
+		}
+
+		if !odFee.IsZero() {
+			if err := recordFee(tx, s.transactionRepo, account, odFee, entryID, "Overdraft fee"); err != nil {
+				return err
+			}
+		}
+
+		txID := int(transactionID)
+		hold.CapturedAmount, hold.TransactionID = &amount, &txID
+		return endHold(tx, s.accountRepo, s.holdRepo, hold, models.HoldCaptured)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetHoldByID(id)
+}
+
+// ReleaseHold ends an authorization or legal hold without posting. Holds of pending transfers are
+// released by rejecting the transfer instead.
+func (s *holdServiceImpl) ReleaseHold(id int) (*models.Hold, error) {
+	err := s.withLockedHold(id, func(tx *sql.Tx, account *models.Account, hold *models.Hold) error {
+		if hold.Type == models.HoldTypeTransfer {
+			return fmt.Errorf("hold %d belongs to a pending transfer: %w", hold.ID, ErrInvalidHoldOperation)
+		}
+		return endHold(tx, s.accountRepo, s.holdRepo, hold, models.HoldReleased)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetHoldByID(id)
+}
+
+// ExpireHolds releases every active hold whose expiry has passed.
+func (s *holdServiceImpl) ExpireHolds(ctx context.Context) error {
+	ids, err := s.holdRepo.GetExpiredHoldIDs(time.Now(), expiryBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err := s.withLockedHold(id, func(tx *sql.Tx, account *models.Account, hold *models.Hold) error {
+			if hold.Status != models.HoldActive || hold.ExpiresAt == nil || !time.Now().After(*hold.ExpiresAt) {
+				return nil // Captured or released in the meantime
+			}
+			return endHold(tx, s.accountRepo, s.holdRepo, hold, models.HoldExpired)
+		})
+		if err != nil {
+			log.Printf("hold %d: failed to expire: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// withLockedHold runs fn in a transaction holding locks on the hold's account and then the hold itself.
+func (s *holdServiceImpl) withLockedHold(id int, fn func(tx *sql.Tx, account *models.Account, hold *models.Hold) error) error {
+	ref, err := s.holdRepo.GetHoldByID(id)
+	if err != nil {
+		return err
+	}
+	return runInTx(func(tx *sql.Tx) error {
+		account, err := s.accountRepo.GetAccountByIDForUpdate(tx, ref.AccountID)
+		if err != nil {
+			return err
+		}
+		hold, err := s.holdRepo.GetHoldByIDForUpdate(tx, id)
+		if err != nil {
+			return err
+		}
+		return fn(tx, account, hold)
+	})
+}
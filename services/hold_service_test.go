@@ -0,0 +1,53 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/services"
+)
+
+func TestCaptureHoldOnFrozenAccount(t *testing.T) {
+	s := newTestServices(t)
+	userID := s.createUser(t, "holder@example.test")
+	account := s.createAccount(t, userID, models.AccountTypeChecking, money.MustParse("500000", money.IDR))
+	hold, err := s.holdService.CreateHold(account.ID, &models.CreateHoldRequest{
+		Type:   models.HoldTypeAuthorization,
+		Amount: money.MustParse("200000", money.IDR),
+		Reason: "card authorization",
+	}, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.accountService.ChangeAccountStatus(account.ID, models.AccountFrozen, "fraud review", userID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.holdService.CaptureHold(hold.ID, &models.CaptureHoldRequest{}); !errors.Is(err, services.ErrAccountNotDebitable) {
+		t.Fatalf("capture on a frozen account: got %v, want ErrAccountNotDebitable", err)
+	}
+	if got, err := s.holdService.GetHoldByID(hold.ID); err != nil || got.Status != models.HoldActive {
+		t.Fatalf("hold after rejected capture: %+v, %v; want it still active", got, err)
+	}
+
+	// Once the account is active again the authorization settles normally
+	if _, err := s.accountService.ChangeAccountStatus(account.ID, models.AccountActive, "review cleared", userID); err != nil {
+		t.Fatal(err)
+	}
+	captured, err := s.holdService.CaptureHold(hold.ID, &models.CaptureHoldRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if captured.Status != models.HoldCaptured {
+		t.Errorf("hold status %s, want %s", captured.Status, models.HoldCaptured)
+	}
+	after, err := s.accountRepo.GetAccountByID(account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := money.MustParse("300000", money.IDR); after.Balance.Cmp(want) != 0 || !after.HeldAmount.IsZero() {
+		t.Errorf("balance %s held %s, want %s and nothing held", after.Balance, after.HeldAmount, want)
+	}
+}
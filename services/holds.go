@@ -0,0 +1,45 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-bank-app/models"
+	"go-bank-app/repositories"
+)
+
+// Hold errors.
+var (
+	ErrFundsOnHold          = errors.New("account has funds on hold")
+	ErrHoldNotActive        = errors.New("hold is not active")
+	ErrInvalidHoldOperation = errors.New("operation not allowed for this hold")
+)
+
+// placeHold inserts an active hold on a locked account and adds it to the account's held amount.
+// The caller decides whether the available balance must cover it.
+func placeHold(tx *sql.Tx, accountRepo repositories.AccountRepository, holdRepo repositories.HoldRepository, hold *models.Hold) (int, error) {
+	hold.Status = models.HoldActive
+	id, err := holdRepo.CreateHold(tx, hold)
+	if err != nil {
+		return 0, fmt.Errorf("failed to place hold: %w", err)
+	}
+	if err := accountRepo.UpdateAccountHeldAmount(tx, hold.AccountID, hold.Amount); err != nil {
+		return 0, err
+	}
+	hold.ID = int(id)
+	return hold.ID, nil
+}
+
+// endHold moves an active hold to status (captured, released or expired) and removes its full
+// amount from the account's held amount. The account row must be locked by the caller.
+func endHold(tx *sql.Tx, accountRepo repositories.AccountRepository, holdRepo repositories.HoldRepository, hold *models.Hold, status string) error {
+	if hold.Status != models.HoldActive {
+		return fmt.Errorf("hold %d is %s: %w", hold.ID, hold.Status, ErrHoldNotActive)
+	}
+	hold.Status = status
+	if err := holdRepo.UpdateHold(tx, hold); err != nil {
+		return err
+	}
+	return accountRepo.UpdateAccountHeldAmount(tx, hold.AccountID, hold.Amount.Neg())
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// errAlreadyCapitalized rolls back a capitalization that lost the race for its period.
+var errAlreadyCapitalized = errors.New("interest period already capitalized")
+
+// interestBookings pays out accrued interest. It is shared by the monthly run of InterestService
+// and AccountService.CloseAccount, which settles the interest of the open month before closing, so
+// both produce the same journal entry, transactions and capitalization record.
+type interestBookings struct {
+	interestRepo    repositories.InterestRepository
+	transactionRepo repositories.TransactionRepository
+	ledger          *ledger
+}
+
+func newInterestBookings(interestRepo repositories.InterestRepository, accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository, ledgerRepo repositories.LedgerRepository) *interestBookings {
+	return &interestBookings{
+		interestRepo:    interestRepo,
+		transactionRepo: transactionRepo,
+		ledger:          newLedger(ledgerRepo, accountRepo),
+	}
+}
+
+// capitalize credits the uncapitalized accruals of a locked account for the period to the account
+// as an interest transaction, paid out of interest expense and dated the day after periodEnd so it
+// counts towards that day's end-of-day balance. Debit interest accrued while overdrawn is charged in
+// the same journal entry as an overdraft_interest transaction, into interest income. It reports
+// false if the period has no uncapitalized accruals, and returns errAlreadyCapitalized if another
+// run stored a capitalization for the period first.
+func (b *interestBookings) capitalize(tx *sql.Tx, account *models.Account, periodStart, periodEnd time.Time) (bool, error) {
+	accrued, debitAccrued, count, err := b.interestRepo.SumUncapitalizedAccruals(tx, account.ID, periodStart, periodEnd)
+	if err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, nil
+	}
+	amount, err := money.FromRat(accrued, account.Currency, money.RoundHalfEven)
+	if err != nil {
+		return false, fmt.Errorf("invalid interest amount: %w", err)
+	}
+	debitAmount, err := money.FromRat(debitAccrued, account.Currency, money.RoundHalfEven)
+	if err != nil {
+		return false, fmt.Errorf("invalid overdraft interest amount: %w", err)
+	}
+
+	capitalization := &models.InterestCapitalization{
+		AccountID:    account.ID,
+		PeriodStart:  periodStart,
+		PeriodEnd:    periodEnd,
+		Accrued:      accrued.FloatString(models.InterestAccrualScale),
+		Amount:       amount,
+		DebitAccrued: debitAccrued.FloatString(models.InterestAccrualScale),
+		DebitAmount:  debitAmount,
+	}
+	// A balance too small to earn a whole minor unit still closes the period, with nothing to post
+	if !amount.IsPositive() && !debitAmount.IsPositive() {
+		return true, b.recordCapitalization(tx, capitalization)
+	}
+
+	customerLedger, err := b.ledger.customerAccount(tx, account)
+	if err != nil {
+		return false, err
+	}
+	var postings []models.Posting
+	if amount.IsPositive() {
+		if err := checkCredit(account); err != nil {
+			return false, err
+		}
+		expense, err := b.ledger.systemAccount(tx, models.LedgerInterestExpense, amount.Currency())
+		if err != nil {
+			return false, err
+		}
+		postings = append(postings, models.Debit(expense, amount), models.Credit(customerLedger, amount))
+	}
+	// Debit interest is owed whatever the available balance, even if it takes the account past its limit
+	if debitAmount.IsPositive() {
+		income, err := b.ledger.systemAccount(tx, models.LedgerInterestIncome, debitAmount.Currency())
+		if err != nil {
+			return false, err
+		}
+		postings = append(postings, models.Debit(customerLedger, debitAmount), models.Credit(income, debitAmount))
+	}
+	month := periodStart.Format("January 2006")
+	entryID, err := b.ledger.post(tx, &models.JournalEntry{
+		Reference:   fmt.Sprintf("interest:%d:%s", account.ID, periodStart.Format("2006-01")),
+		Description: fmt.Sprintf("Interest %s", month),
+		Postings:    postings,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to post interest: %w", err)
+	}
+	capitalization.JournalEntryID = &entryID
+
+	if amount.IsPositive() {
+		txID, err := b.recordInterestTransaction(tx, account.ID, models.TransactionInterest, amount,
+			fmt.Sprintf("Interest %s", month), periodEnd, entryID)
+		if err != nil {
+			return false, err
+		}
+		capitalization.TransactionID = &txID
+	}
+	if debitAmount.IsPositive() {
+		txID, err := b.recordInterestTransaction(tx, account.ID, models.TransactionOverdraftInterest, debitAmount,
+			fmt.Sprintf("Overdraft interest %s", month), periodEnd, entryID)
+		if err != nil {
+			return false, err
+		}
+		capitalization.DebitTransactionID = &txID
+	}
+	return true, b.recordCapitalization(tx, capitalization)
+}
+
+// capitalizeOutstanding capitalizes every uncapitalized accrual of a locked account up to
+// yesterday, month by month, the current month included. It is used when the account is closed,
+// so interest earned since the last month end is paid rather than lost; days the interest job has
+// not accrued yet earn nothing. It reports whether anything was capitalized.
+func (b *interestBookings) capitalizeOutstanding(tx *sql.Tx, account *models.Account) (bool, error) {
+	oldest, err := b.interestRepo.GetOldestUncapitalizedAccrualDate(tx, account.ID)
+	if err != nil || oldest == nil {
+		return false, err
+	}
+	yesterday := startOfDay(time.Now()).AddDate(0, 0, -1)
+	capitalized := false
+	for start := time.Date(oldest.Year(), oldest.Month(), 1, 0, 0, 0, 0, time.Local); !start.After(yesterday); start = start.AddDate(0, 1, 0) {
+		end := start.AddDate(0, 1, -1)
+		if end.After(yesterday) {
+			end = yesterday
+		}
+		ok, err := b.capitalize(tx, account, start, end)
+		if err != nil {
+			return false, fmt.Errorf("failed to capitalize interest for %s: %w", start.Format("January 2006"), err)
+		}
+		capitalized = capitalized || ok
+	}
+	return capitalized, nil
+}
+
+// recordInterestTransaction records one side of a capitalization on the account's transaction
+// history, dated the day after periodEnd.
+func (b *interestBookings) recordInterestTransaction(tx *sql.Tx, accountID int, transactionType string, amount money.Money, description string, periodEnd time.Time, entryID int) (int, error) {
+	transactionID, err := b.transactionRepo.CreateTransaction(tx, &models.Transaction{
+		AccountID:       accountID,
+		TransactionType: transactionType,
+		Amount:          amount,
+		Description:     description,
+		TransactionDate: periodEnd.AddDate(0, 0, 1),
+		JournalEntryID:  entryID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record %s transaction: %w", transactionType, err)
+	}
+	return int(transactionID), nil
+}
+
+// recordCapitalization stores the capitalization and links the period's accruals to it. It returns
+// errAlreadyCapitalized, rolling back the postings, if another run stored one first.
+func (b *interestBookings) recordCapitalization(tx *sql.Tx, capitalization *models.InterestCapitalization) error {
+	created, err := b.interestRepo.CreateCapitalization(tx, capitalization)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errAlreadyCapitalized
+	}
+	return b.interestRepo.MarkAccrualsCapitalized(tx, capitalization.AccountID, capitalization.PeriodStart,
+		capitalization.PeriodEnd, capitalization.ID)
+}
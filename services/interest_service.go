@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"go-bank-app/interest"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+var (
+	// ErrInvalidInterestPlan is returned when an interest rate or day-count convention cannot be used.
+	ErrInvalidInterestPlan = errors.New("invalid interest plan")
+	// ErrInvalidInterestDate is returned when interest is requested for a day that has not ended yet.
+	ErrInvalidInterestDate = errors.New("interest can only be accrued for days that have ended")
+)
+
+// InterestService accrues daily interest on interest-bearing accounts and capitalizes it monthly.
+// Overdrawn accounts accrue debit interest at their overdraft rate instead, charged the same way.
+//
+// Every step is idempotent per date: an accrual is unique per account and day, a capitalization
+// per account and month, so a run that crashed half-way can simply be run again.
+type InterestService interface {
+	GetAccountInterest(accountID int) (*models.AccountInterest, error)
+	UpdateInterestPlan(accountID int, req *models.UpdateInterestPlanRequest) (*models.Account, error)
+	RunDue(ctx context.Context) error                                                     // Scheduler job: runs every ended day since the last completed one
+	RunForDate(ctx context.Context, day time.Time) (*models.InterestRunResult, error)     // Accrues one day, and capitalizes the month if day is its last
+	Backfill(ctx context.Context, from, to time.Time) ([]models.InterestRunResult, error) // Runs every day from from to to inclusive
+}
+
+// interestServiceImpl is the concrete implementation of InterestService.
+type interestServiceImpl struct {
+	interestRepo    repositories.InterestRepository
+	accountRepo     repositories.AccountRepository
+	transactionRepo repositories.TransactionRepository
+	bookings        *interestBookings
+}
+
+// NewInterestService creates a new instance of InterestService.
+func NewInterestService(interestRepo repositories.InterestRepository, accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository, ledgerRepo repositories.LedgerRepository) InterestService {
+	return &interestServiceImpl{
+		interestRepo:    interestRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		bookings:        newInterestBookings(interestRepo, accountRepo, transactionRepo, ledgerRepo),
+	}
+}
+
+// startOfDay returns midnight server-local time of t's calendar date.
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// GetAccountInterest returns the interest plan of an account, the current month's accruals and
+// every capitalization so far.
+func (s *interestServiceImpl) GetAccountInterest(accountID int) (*models.AccountInterest, error) {
+	account, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account: %w", err)
+	}
+
+	today := startOfDay(time.Now())
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.Local)
+	accruals, err := s.interestRepo.GetAccruals(account.ID, monthStart, today)
+	if err != nil {
+		return nil, err
+	}
+	capitalizations, err := s.interestRepo.GetCapitalizations(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	accrued := new(big.Rat)
+	for _, a := range accruals {
+		if a.CapitalizationID != nil {
+			continue
+		}
+		amount, ok := new(big.Rat).SetString(a.Amount)
+		if !ok {
+			return nil, fmt.Errorf("invalid interest accrual %q for account %d", a.Amount, account.ID)
+		}
+		accrued.Add(accrued, amount)
+	}
+	accruedToDate, err := money.FromRat(accrued, account.Currency, money.RoundHalfEven)
+	if err != nil {
+		return nil, fmt.Errorf("invalid accrued interest for account %d: %w", account.ID, err)
+	}
+
+	view := &models.AccountInterest{
+		AccountID:       account.ID,
+		AccountType:     account.Type,
+		InterestRate:    account.InterestRate,
+		DayCount:        account.DayCount,
+		AccruedToDate:   accruedToDate,
+		Accruals:        accruals,
+		Capitalizations: capitalizations,
+	}
+	if view.Accruals == nil {
+		view.Accruals = []models.InterestAccrual{}
+	}
+	if view.Capitalizations == nil {
+		view.Capitalizations = []models.InterestCapitalization{}
+	}
+	return view, nil
+}
+
+// UpdateInterestPlan changes the rate and day-count convention of an account. Accruals already
+// recorded keep the plan they were computed with.
+func (s *interestServiceImpl) UpdateInterestPlan(accountID int, req *models.UpdateInterestPlanRequest) (*models.Account, error) {
+	plan, err := interest.ParsePlan(req.InterestRate, req.DayCount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInterestPlan, err)
+	}
+	account, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account: %w", err)
+	}
+	if account.Status == models.AccountClosed {
+		return nil, fmt.Errorf("account %s is closed: %w", account.AccountNumber, ErrInvalidInterestPlan)
+	}
+	if err := s.accountRepo.UpdateInterestPlan(account.ID, plan); err != nil {
+		return nil, err
+	}
+	return s.accountRepo.GetAccountByID(account.ID)
+}
+
+// RunDue runs every day that has ended since the last completed run, oldest first, and stops at
+// the first day that does not complete so the next tick retries it. On the very first run only
+// yesterday is processed; earlier days are filled in with the backfill command.
+func (s *interestServiceImpl) RunDue(ctx context.Context) error {
+	yesterday := startOfDay(time.Now()).AddDate(0, 0, -1)
+	day := yesterday
+	last, err := s.interestRepo.GetLastCompletedRunDate()
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		day = last.AddDate(0, 0, 1)
+	}
+	for ; !day.After(yesterday); day = day.AddDate(0, 0, 1) {
+		if _, err := s.RunForDate(ctx, day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backfill runs every day from from to to inclusive, e.g. after the job was down for a while.
+// Days that were already processed are skipped account by account, so overlapping a completed
+// range is harmless.
+func (s *interestServiceImpl) Backfill(ctx context.Context, from, to time.Time) ([]models.InterestRunResult, error) {
+	from, to = startOfDay(from), startOfDay(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("backfill range ends (%s) before it starts (%s)", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+	var results []models.InterestRunResult
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		result, err := s.RunForDate(ctx, day)
+		if result != nil {
+			results = append(results, *result)
+		}
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// RunForDate accrues one day of interest on every interest-bearing or overdrawn account and, if day is the last
+// day of its month, capitalizes the month. A failure on one account does not stop the others, but
+// the date is only recorded as completed once every account succeeded.
+func (s *interestServiceImpl) RunForDate(ctx context.Context, day time.Time) (*models.InterestRunResult, error) {
+	day = startOfDay(day)
+	if !day.Before(startOfDay(time.Now())) {
+		return nil, fmt.Errorf("%s: %w", day.Format("2006-01-02"), ErrInvalidInterestDate)
+	}
+	result := &models.InterestRunResult{Date: day}
+
+	ids, err := s.interestRepo.GetAccruingAccountIDs(day)
+	if err != nil {
+		return result, err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		created, err := s.accrue(id, day)
+		switch {
+		case err != nil:
+			log.Printf("interest %s: account %d: %v", day.Format("2006-01-02"), id, err)
+			result.Failed++
+		case created:
+			result.Accrued++
+		default:
+			result.Skipped++
+		}
+	}
+
+	if next := day.AddDate(0, 0, 1); next.Day() == 1 {
+		periodStart := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.Local)
+		ids, err := s.interestRepo.GetUncapitalizedAccountIDs(periodStart, day)
+		if err != nil {
+			return result, err
+		}
+		for _, id := range ids {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			capitalized, err := s.capitalize(id, periodStart, day)
+			if err != nil {
+				log.Printf("interest %s: capitalizing account %d: %v", day.Format("2006-01-02"), id, err)
+				result.Failed++
+			} else if capitalized {
+				result.Capitalized++
+			}
+		}
+	}
+
+	if result.Failed > 0 {
+		return result, fmt.Errorf("interest run for %s failed on %d account(s)", day.Format("2006-01-02"), result.Failed)
+	}
+	if err := s.interestRepo.MarkRunCompleted(result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// accrue records the interest one account earned on day from its end-of-day balance, or the debit
+// interest it owes if that balance was negative, stored as a negative amount. It reports false if
+// the day was already accrued by an earlier run.
+func (s *interestServiceImpl) accrue(accountID int, day time.Time) (bool, error) {
+	account, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return false, err
+	}
+
+	// Transactions dated before the next midnight make up the end-of-day balance
+	balance, err := s.transactionRepo.GetBalanceBefore(account.ID, day.AddDate(0, 0, 1), account.Currency)
+	if err != nil {
+		return false, err
+	}
+	plan, err := account.InterestPlan()
+	if balance.IsNegative() {
+		plan, err = account.OverdraftInterestPlan()
+	}
+	if err != nil {
+		return false, err
+	}
+	if !plan.Earns() {
+		return false, nil // No rate applies to this balance
+	}
+
+	amount := plan.Daily(balance, day)
+	if balance.IsNegative() {
+		amount.Neg(plan.Daily(balance.Neg(), day))
+	}
+	return s.interestRepo.CreateAccrual(&models.InterestAccrual{
+		AccountID:       account.ID,
+		AccrualDate:     day,
+		EndOfDayBalance: balance,
+		InterestRate:    plan.RatePercent(),
+		DayCount:        plan.DayCount,
+		Amount:          amount.FloatString(models.InterestAccrualScale),
+	})
+}
+
+// capitalize pays one account's interest for the month in its own DB transaction. It reports
+// false if there was nothing to book or another run booked it first.
+func (s *interestServiceImpl) capitalize(accountID int, periodStart, periodEnd time.Time) (bool, error) {
+	var capitalized bool
+	err := runInTx(func(tx *sql.Tx) error {
+		account, err := s.accountRepo.GetAccountByIDForUpdate(tx, accountID)
+		if err != nil {
+			return err
+		}
+		capitalized, err = s.bookings.capitalize(tx, account, periodStart, periodEnd)
+		return err
+	})
+	if errors.Is(err, errAlreadyCapitalized) {
+		return false, nil
+	}
+	return capitalized && err == nil, err
+}
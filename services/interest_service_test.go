@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
+
+// TestInterestMonth backfills a whole past month for one account: every day accrues from the
+// end-of-day balance, the month is capitalized once on its last day, and running the month again
+// changes nothing.
+func TestInterestMonth(t *testing.T) {
+	s := newTestServices(t)
+	userID := s.createUser(t, "saver@example.test")
+	account := s.createAccount(t, userID, models.AccountTypeSavings, money.Zero(money.IDR))
+	if _, err := s.interestService.UpdateInterestPlan(account.ID, &models.UpdateInterestPlanRequest{InterestRate: "2.5", DayCount: "ACT/365"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.accountService.Deposit(account.ID, money.MustParse("10000000", money.IDR)); err != nil {
+		t.Fatal(err)
+	}
+	// Pretend the account was opened and funded before the month being run
+	opened := time.Date(2024, 12, 31, 10, 0, 0, 0, time.Local)
+	for _, query := range []string{
+		"UPDATE accounts SET created_at = ? WHERE id = ?",
+		"UPDATE transactions SET transaction_date = ? WHERE account_id = ?",
+	} {
+		if _, err := s.db.Exec(config.Dialect().Rebind(query), opened, account.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2025, 1, 31, 0, 0, 0, 0, time.Local)
+	results, err := s.interestService.Backfill(context.Background(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accrued, capitalized := 0, 0
+	for _, r := range results {
+		accrued += r.Accrued
+		capitalized += r.Capitalized
+	}
+	if len(results) != 31 || accrued != 31 || capitalized != 1 {
+		t.Fatalf("backfill ran %d days with %d accruals and %d capitalizations, want 31, 31 and 1", len(results), accrued, capitalized)
+	}
+
+	// 10,000,000 * 2.5% * 31/365 = 21,232.8767..., rounded once for the month
+	want := money.MustParse("21232.88", money.IDR)
+	capitalizations, err := s.interestRepo.GetCapitalizations(account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(capitalizations) != 1 || capitalizations[0].Amount.Cmp(want) != 0 || !capitalizations[0].DebitAmount.IsZero() {
+		t.Fatalf("capitalizations %+v, want one of %s", capitalizations, want)
+	}
+	// The month sums the stored daily accruals, each kept to models.InterestAccrualScale decimals
+	if got := capitalizations[0].Accrued; got != "21232.8767123283" {
+		t.Errorf("unrounded accrual %s, want 31 * 684.9315068493 = 21232.8767123283", got)
+	}
+	balanceAfter := money.MustParse("10000000", money.IDR).Add(want)
+	assertBalance := func() {
+		t.Helper()
+		a, err := s.accountRepo.GetAccountByID(account.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Balance.Cmp(balanceAfter) != 0 {
+			t.Errorf("balance %s, want %s", a.Balance, balanceAfter)
+		}
+	}
+	assertBalance()
+
+	// Running the month again finds every day and the capitalization already done
+	results, err = s.interestService.Backfill(context.Background(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Accrued != 0 || r.Capitalized != 0 || r.Skipped != 1 {
+			t.Errorf("rerun of %s: %+v, want the account skipped", r.Date.Format("2006-01-02"), r)
+		}
+	}
+	assertBalance()
+
+	tb, err := s.ledgerService.GetTrialBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tb.Balanced {
+		t.Errorf("trial balance does not balance: %+v", tb.Totals)
+	}
+}
+
+// TestCloseAccountCapitalizesInterest closes an account whose interest for the month has accrued
+// but not been capitalized: the interest is paid before the balance is swept, not lost.
+func TestCloseAccountCapitalizesInterest(t *testing.T) {
+	s := newTestServices(t)
+	userID := s.createUser(t, "closer@example.test")
+	account := s.createAccount(t, userID, models.AccountTypeSavings, money.Zero(money.IDR))
+	target := s.createAccount(t, userID, models.AccountTypeChecking, money.Zero(money.IDR))
+	s.liftLimits(t, account.ID, 10)
+	if _, err := s.interestService.UpdateInterestPlan(account.ID, &models.UpdateInterestPlanRequest{InterestRate: "2.5", DayCount: "ACT/365"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.accountService.Deposit(account.ID, money.MustParse("10000000", money.IDR)); err != nil {
+		t.Fatal(err)
+	}
+	opened := time.Date(2024, 12, 31, 10, 0, 0, 0, time.Local)
+	for _, query := range []string{
+		"UPDATE accounts SET created_at = ? WHERE id = ?",
+		"UPDATE transactions SET transaction_date = ? WHERE account_id = ?",
+	} {
+		if _, err := s.db.Exec(config.Dialect().Rebind(query), opened, account.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Ten days accrue; the month end that would capitalize them never runs before the close
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	if _, err := s.interestService.Backfill(context.Background(), from, from.AddDate(0, 0, 9)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.accountService.CloseAccount(account.ID, &models.CloseAccountRequest{
+		Reason:               "customer request",
+		SweepToAccountNumber: target.AccountNumber,
+	}, userID); err != nil {
+		t.Fatal(err)
+	}
+
+	// 10,000,000 * 2.5% * 10/365 = 6,849.3150...
+	interest := money.MustParse("6849.32", money.IDR)
+	capitalizations, err := s.interestRepo.GetCapitalizations(account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(capitalizations) != 1 || capitalizations[0].Amount.Cmp(interest) != 0 {
+		t.Fatalf("capitalizations %+v, want one of %s", capitalizations, interest)
+	}
+	swept, err := s.accountRepo.GetAccountByID(target.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := money.MustParse("10000000", money.IDR).Add(interest); swept.Balance.Cmp(want) != 0 {
+		t.Errorf("swept balance %s, want %s", swept.Balance, want)
+	}
+	closed, err := s.accountRepo.GetAccountByID(account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if closed.Status != models.AccountClosed || !closed.Balance.IsZero() {
+		t.Errorf("closed account is %s with balance %s, want closed with zero", closed.Status, closed.Balance)
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// ErrUnbalancedEntry is returned when a journal entry's postings do not sum to zero.
+var ErrUnbalancedEntry = errors.New("journal entry does not balance")
+
+// LedgerService defines the interface for read-side general-ledger reporting.
+type LedgerService interface {
+	GetTrialBalance() (*models.TrialBalance, error)
+}
+
+// ledgerServiceImpl is the concrete implementation of LedgerService.
+type ledgerServiceImpl struct {
+	ledgerRepo repositories.LedgerRepository
+}
+
+// NewLedgerService creates a new instance of LedgerService.
+func NewLedgerService(ledgerRepo repositories.LedgerRepository) LedgerService {
+	return &ledgerServiceImpl{ledgerRepo: ledgerRepo}
+}
+
+// GetTrialBalance totals every ledger account, checks that debits equal credits per currency,
+// and lists customer accounts whose stored balance disagrees with their postings.
+func (s *ledgerServiceImpl) GetTrialBalance() (*models.TrialBalance, error) {
+	lines, err := s.ledgerRepo.GetTrialBalance()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute trial balance: %w", err)
+	}
+	discrepancies, err := s.ledgerRepo.GetBalanceDiscrepancies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify account balances: %w", err)
+	}
+
+	tb := &models.TrialBalance{
+		Lines:         lines,
+		Totals:        []models.TrialBalanceTotal{},
+		Discrepancies: discrepancies,
+		GeneratedAt:   time.Now(),
+	}
+	if tb.Lines == nil {
+		tb.Lines = []models.TrialBalanceLine{}
+	}
+	if tb.Discrepancies == nil {
+		tb.Discrepancies = []models.BalanceDiscrepancy{}
+	}
+
+	totals := map[money.Currency]int{}
+	for _, line := range lines {
+		idx, ok := totals[line.Currency]
+		if !ok {
+			idx = len(tb.Totals)
+			totals[line.Currency] = idx
+			tb.Totals = append(tb.Totals, models.TrialBalanceTotal{
+				Currency: line.Currency,
+				Debit:    money.Zero(line.Currency),
+				Credit:   money.Zero(line.Currency),
+			})
+		}
+		tb.Totals[idx].Debit = tb.Totals[idx].Debit.Add(line.Debit)
+		tb.Totals[idx].Credit = tb.Totals[idx].Credit.Add(line.Credit)
+	}
+
+	tb.Balanced = len(discrepancies) == 0
+	for i := range tb.Totals {
+		tb.Totals[i].Balanced = tb.Totals[i].Debit.Cmp(tb.Totals[i].Credit) == 0
+		tb.Balanced = tb.Balanced && tb.Totals[i].Balanced
+	}
+	return tb, nil
+}
+
+// ledger is the write side of the general ledger shared by every service that moves money.
+// Customer balances are derived from the postings: post applies each customer posting to the
+// cached accounts.balance column in the same transaction, so the two can never drift apart.
+type ledger struct {
+	ledgerRepo  repositories.LedgerRepository
+	accountRepo repositories.AccountRepository
+}
+
+func newLedger(ledgerRepo repositories.LedgerRepository, accountRepo repositories.AccountRepository) *ledger {
+	return &ledger{ledgerRepo: ledgerRepo, accountRepo: accountRepo}
+}
+
+// customerAccount returns the ledger account mirroring a customer account.
+func (l *ledger) customerAccount(tx *sql.Tx, account *models.Account) (*models.LedgerAccount, error) {
+	la, err := l.ledgerRepo.GetOrCreateCustomerAccount(tx, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve ledger account for %s: %w", account.AccountNumber, err)
+	}
+	return la, nil
+}
+
+// systemAccount returns one of the bank's own ledger accounts (see models.SystemLedgerAccounts).
+func (l *ledger) systemAccount(tx *sql.Tx, code string, currency money.Currency) (*models.LedgerAccount, error) {
+	la, err := l.ledgerRepo.GetOrCreateSystemAccount(tx, code, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve system ledger account %s: %w", code, err)
+	}
+	return la, nil
+}
+
+// post validates that entry balances, persists it, and applies its customer postings to the
+// account balances. It returns the new journal entry ID.
+func (l *ledger) post(tx *sql.Tx, entry *models.JournalEntry) (int, error) {
+	if len(entry.Postings) < 2 {
+		return 0, fmt.Errorf("%w: at least two postings are required", ErrUnbalancedEntry)
+	}
+
+	sums := map[money.Currency]money.Money{}
+	for _, p := range entry.Postings {
+		if p.Amount.IsZero() {
+			return 0, fmt.Errorf("%w: zero-amount posting on ledger account %d", ErrUnbalancedEntry, p.LedgerAccountID)
+		}
+		cur := p.Amount.Currency()
+		sum, ok := sums[cur]
+		if !ok {
+			sum = money.Zero(cur)
+		}
+		sums[cur] = sum.Add(p.Amount)
+	}
+	for cur, sum := range sums {
+		if !sum.IsZero() {
+			return 0, fmt.Errorf("%w: %s postings sum to %s", ErrUnbalancedEntry, cur, sum)
+		}
+	}
+
+	id, err := l.ledgerRepo.CreateJournalEntry(tx, entry)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post journal entry: %w", err)
+	}
+	entry.ID = int(id)
+
+	for _, p := range entry.Postings {
+		if p.AccountID == 0 {
+			continue
+		}
+		// A credit (negative posting) increases what the bank owes the customer
+		if err := l.accountRepo.UpdateAccountBalance(tx, p.AccountID, p.Amount.Neg()); err != nil {
+			return 0, fmt.Errorf("failed to apply posting to account %d: %w", p.AccountID, err)
+		}
+	}
+	return entry.ID, nil
+}
+
+// reverse posts a compensating entry for journal entry entryID: every posting with its sign
+// flipped, so the ledger and the customer balances return to where they were before the original.
+func (l *ledger) reverse(tx *sql.Tx, entryID int, reference, description string) (int, error) {
+	postings, err := l.ledgerRepo.GetPostings(tx, entryID)
+	if err != nil {
+		return 0, err
+	}
+	reversal := &models.JournalEntry{Reference: reference, Description: description}
+	for _, p := range postings {
+		reversal.Postings = append(reversal.Postings, models.Posting{
+			LedgerAccountID: p.LedgerAccountID,
+			Amount:          p.Amount.Neg(),
+			AccountID:       p.AccountID,
+		})
+	}
+	return l.post(tx, reversal)
+}
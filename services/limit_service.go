@@ -0,0 +1,86 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// LimitService exposes the debit limits of an account and lets back-office staff override them.
+type LimitService interface {
+	GetAccountLimits(accountID int) (*models.AccountLimitsView, error)
+	UpdateAccountLimits(accountID int, req *models.UpdateAccountLimitsRequest) (*models.AccountLimitsView, error)
+}
+
+// limitServiceImpl is the concrete implementation of LimitService.
+type limitServiceImpl struct {
+	accountRepo repositories.AccountRepository
+	limitRepo   repositories.LimitRepository
+	limits      *limitChecker
+}
+
+// NewLimitService creates a new instance of LimitService.
+func NewLimitService(accountRepo repositories.AccountRepository, limitRepo repositories.LimitRepository) LimitService {
+	return &limitServiceImpl{
+		accountRepo: accountRepo,
+		limitRepo:   limitRepo,
+		limits:      newLimitChecker(limitRepo),
+	}
+}
+
+// GetAccountLimits returns the limits in force on an account, its own overrides and its current usage.
+func (s *limitServiceImpl) GetAccountLimits(accountID int) (*models.AccountLimitsView, error) {
+	account, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account: %w", err)
+	}
+
+	view := &models.AccountLimitsView{AccountID: account.ID, AccountType: account.Type}
+	// Read limits and usage in one transaction so they describe the same moment
+	err = runInTx(func(tx *sql.Tx) error {
+		limits, overrides, err := s.limits.effective(tx, account)
+		if err != nil {
+			return err
+		}
+		usage, err := s.limits.usage(tx, account, time.Now())
+		if err != nil {
+			return err
+		}
+		view.Limits, view.Overrides, view.Usage = *limits, *overrides, *usage
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute account limits: %w", err)
+	}
+	return view, nil
+}
+
+// UpdateAccountLimits replaces the overrides of an account. Amounts must be in the account's
+// currency; omitted limits fall back to the account type default.
+func (s *limitServiceImpl) UpdateAccountLimits(accountID int, req *models.UpdateAccountLimitsRequest) (*models.AccountLimitsView, error) {
+	account, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account: %w", err)
+	}
+
+	overrides := req.AccountLimits
+	for _, limit := range []**money.Money{&overrides.PerTransaction, &overrides.DailyDebit, &overrides.MonthlyDebit} {
+		if *limit == nil {
+			continue
+		}
+		amount, err := (*limit).In(account.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit amount: %w", err)
+		}
+		*limit = &amount
+	}
+
+	if err := s.limitRepo.SetOverrides(account.ID, &overrides); err != nil {
+		return nil, err
+	}
+	return s.GetAccountLimits(account.ID)
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// ErrLimitExceeded is matched by every *LimitExceededError (errors.Is).
+var ErrLimitExceeded = errors.New("account limit exceeded")
+
+// LimitExceededError reports which limit a debit would breach and the headroom left on it.
+type LimitExceededError struct {
+	Limit          string       // models.LimitPerTransaction, models.LimitDailyDebit, ...
+	Remaining      *money.Money // Headroom of an amount limit; for per_transaction, the maximum itself
+	RemainingCount *int         // Headroom of models.LimitDailyTransfers
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.RemainingCount != nil {
+		return fmt.Sprintf("%s limit exceeded: %d remaining", e.Limit, *e.RemainingCount)
+	}
+	return fmt.Sprintf("%s limit exceeded: %s %s remaining", e.Limit, e.Remaining, e.Remaining.Currency())
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// limitChecker enforces the debit limits of an account: the defaults of its account type (see
+// config.AccountLimitDefaults) unless the account overrides them. Checks run inside the debit's
+// transaction on the locked account row, so concurrent debits are counted one after the other.
+type limitChecker struct {
+	limitRepo repositories.LimitRepository
+}
+
+func newLimitChecker(limitRepo repositories.LimitRepository) *limitChecker {
+	return &limitChecker{limitRepo: limitRepo}
+}
+
+// effective returns the limits in force on an account together with the account's own overrides.
+func (l *limitChecker) effective(tx *sql.Tx, account *models.Account) (limits, overrides *models.AccountLimits, err error) {
+	overrides, err = l.limitRepo.GetOverrides(tx, account.ID, account.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defaults := config.AccountLimitDefaults(account.Type)
+	limits = &models.AccountLimits{
+		PerTransaction: overrides.PerTransaction,
+		DailyDebit:     overrides.DailyDebit,
+		MonthlyDebit:   overrides.MonthlyDebit,
+		DailyTransfers: overrides.DailyTransfers,
+	}
+	if limits.PerTransaction == nil {
+		limits.PerTransaction = defaultLimit(defaults.PerTransaction, account.Currency)
+	}
+	if limits.DailyDebit == nil {
+		limits.DailyDebit = defaultLimit(defaults.DailyDebit, account.Currency)
+	}
+	if limits.MonthlyDebit == nil {
+		limits.MonthlyDebit = defaultLimit(defaults.MonthlyDebit, account.Currency)
+	}
+	if limits.DailyTransfers == nil && defaults.DailyTransfers > 0 {
+		n := defaults.DailyTransfers
+		limits.DailyTransfers = &n
+	}
+	return limits, overrides, nil
+}
+
+// defaultLimit returns the configured default for currency, or nil if that currency is unlimited.
+func defaultLimit(defaults map[money.Currency]money.Money, currency money.Currency) *money.Money {
+	if m, ok := defaults[currency]; ok {
+		return &m
+	}
+	return nil
+}
+
+// usage returns what the account has used of its limits since the start of the current calendar
+// day and month (server time).
+func (l *limitChecker) usage(tx *sql.Tx, account *models.Account, now time.Time) (*models.LimitUsage, error) {
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return l.limitRepo.GetDebitUsage(tx, account.ID, dayStart, monthStart, account.Currency)
+}
+
+// check rejects a debit of amount from a locked account if it would breach one of its limits.
+// A transfer also counts against the daily number of transfers.
+func (l *limitChecker) check(tx *sql.Tx, account *models.Account, amount money.Money, transfer bool) error {
+	limits, _, err := l.effective(tx, account)
+	if err != nil {
+		return err
+	}
+	if limits.PerTransaction != nil && amount.GreaterThan(*limits.PerTransaction) {
+		return &LimitExceededError{Limit: models.LimitPerTransaction, Remaining: limits.PerTransaction}
+	}
+	if limits.DailyDebit == nil && limits.MonthlyDebit == nil && (!transfer || limits.DailyTransfers == nil) {
+		return nil
+	}
+
+	usage, err := l.usage(tx, account, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := checkAmountLimit(models.LimitDailyDebit, limits.DailyDebit, usage.DailyDebit, amount); err != nil {
+		return err
+	}
+	if err := checkAmountLimit(models.LimitMonthlyDebit, limits.MonthlyDebit, usage.MonthlyDebit, amount); err != nil {
+		return err
+	}
+	if transfer && limits.DailyTransfers != nil && usage.DailyTransfers >= *limits.DailyTransfers {
+		remaining := 0
+		return &LimitExceededError{Limit: models.LimitDailyTransfers, RemainingCount: &remaining}
+	}
+	return nil
+}
+
+// checkAmountLimit rejects amount if it does not fit in what is left of max after used.
+func checkAmountLimit(limit string, max *money.Money, used, amount money.Money) error {
+	if max == nil {
+		return nil
+	}
+	remaining := max.Sub(used)
+	if !remaining.LessThan(amount) {
+		return nil
+	}
+	if remaining.IsNegative() { // The limit was lowered below what is already used
+		remaining = money.Zero(remaining.Currency())
+	}
+	return &LimitExceededError{Limit: limit, Remaining: &remaining}
+}
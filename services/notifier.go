@@ -0,0 +1,22 @@
+package services
+
+import "log"
+
+// Notifier delivers messages to customers. The default implementation only writes to the log;
+// swap in an e-mail, SMS or push implementation in main.go.
+type Notifier interface {
+	Notify(userID int, subject, message string) error
+}
+
+// logNotifier is a Notifier that logs every message.
+type logNotifier struct{}
+
+// NewLogNotifier creates a Notifier that logs messages instead of sending them.
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(userID int, subject, message string) error {
+	log.Printf("notify user %d: %s: %s", userID, subject, message)
+	return nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-bank-app/config"
+	"go-bank-app/interest"
+	"go-bank-app/models"
+	"go-bank-app/repositories"
+)
+
+// ErrInvalidOverdraft is returned when an overdraft facility cannot be granted as requested.
+var ErrInvalidOverdraft = errors.New("invalid overdraft")
+
+// OverdraftService lets back-office staff grant, change and withdraw the overdraft facility of a
+// checking account. The facility itself is enforced wherever the available balance is checked,
+// since it counts towards it.
+type OverdraftService interface {
+	UpdateOverdraft(accountID int, req *models.UpdateOverdraftRequest) (*models.Account, error)
+}
+
+// overdraftServiceImpl is the concrete implementation of OverdraftService.
+type overdraftServiceImpl struct {
+	accountRepo repositories.AccountRepository
+}
+
+// NewOverdraftService creates a new instance of OverdraftService.
+func NewOverdraftService(accountRepo repositories.AccountRepository) OverdraftService {
+	return &overdraftServiceImpl{accountRepo: accountRepo}
+}
+
+// UpdateOverdraft sets the overdraft limit and debit interest rate of a checking account. A zero
+// limit withdraws the facility: an account that is already overdrawn stays so, but cannot be
+// debited further until it is back in credit.
+func (s *overdraftServiceImpl) UpdateOverdraft(accountID int, req *models.UpdateOverdraftRequest) (*models.Account, error) {
+	// The account row stays locked while the facility changes, so a concurrent debit is checked
+	// against either the old limit or the new one, never a mix
+	err := runInTx(func(tx *sql.Tx) error {
+		account, err := s.accountRepo.GetAccountByIDForUpdate(tx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve account: %w", err)
+		}
+		if account.Type != models.AccountTypeChecking {
+			return fmt.Errorf("%w: only checking accounts can have an overdraft", ErrInvalidOverdraft)
+		}
+		if account.Status == models.AccountClosed {
+			return fmt.Errorf("account %s is closed: %w", account.AccountNumber, ErrInvalidOverdraft)
+		}
+
+		limit, err := req.Limit.In(account.Currency)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidOverdraft, err)
+		}
+		if limit.IsNegative() {
+			return fmt.Errorf("%w: limit must not be negative", ErrInvalidOverdraft)
+		}
+		rate := req.InterestRate
+		if rate == "" {
+			rate = config.AppCfg.Overdraft.InterestRate
+		}
+		plan, err := interest.ParsePlan(rate, string(account.DayCount))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidOverdraft, err)
+		}
+		return s.accountRepo.UpdateOverdraft(tx, account.ID, limit, plan.RatePercent())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.accountRepo.GetAccountByID(accountID)
+}
@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/fees"
+	"go-bank-app/fx"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// ErrInvalidStandingOrder is returned when a standing order request breaks a scheduling rule.
+var ErrInvalidStandingOrder = errors.New("invalid standing order")
+
+// dueBatchSize caps how many due orders one scheduler run picks up.
+const dueBatchSize = 100
+
+// StandingOrderService manages scheduled and recurring transfers and executes them when due.
+type StandingOrderService interface {
+	CreateStandingOrder(account *models.Account, req *models.CreateStandingOrderRequest, actorUserID int) (*models.StandingOrder, error)
+	GetStandingOrderByID(id int) (*models.StandingOrder, error)
+	GetStandingOrdersByAccountID(accountID int) ([]models.StandingOrder, error)
+	UpdateStandingOrder(id int, req *models.UpdateStandingOrderRequest) (*models.StandingOrder, error)
+	CancelStandingOrder(id int) (*models.StandingOrder, error)
+	GetExecutions(orderID int) ([]models.StandingOrderExecution, error)
+	RunDue(ctx context.Context) error // Scheduler job: executes every order whose next run has arrived
+}
+
+// standingOrderServiceImpl is the concrete implementation of StandingOrderService.
+type standingOrderServiceImpl struct {
+	standingOrderRepo repositories.StandingOrderRepository
+	accountRepo       repositories.AccountRepository
+	bookings          *transferBookings
+	notifier          Notifier
+}
+
+// NewStandingOrderService creates a new instance of StandingOrderService.
+func NewStandingOrderService(standingOrderRepo repositories.StandingOrderRepository, accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository, transferRepo repositories.TransferRepository, ledgerRepo repositories.LedgerRepository, holdRepo repositories.HoldRepository, limitRepo repositories.LimitRepository, feeSchedule *fees.Schedule, rates fx.RateProvider, notifier Notifier) StandingOrderService {
+	return &standingOrderServiceImpl{
+		standingOrderRepo: standingOrderRepo,
+		accountRepo:       accountRepo,
+		bookings:          newTransferBookings(accountRepo, transactionRepo, transferRepo, ledgerRepo, holdRepo, limitRepo, feeSchedule, rates),
+		notifier:          notifier,
+	}
+}
+
+// parseDate parses a YYYY-MM-DD request date as midnight server-local time.
+func parseDate(s string) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: %w", s, ErrInvalidStandingOrder)
+	}
+	return t, nil
+}
+
+func (s *standingOrderServiceImpl) CreateStandingOrder(account *models.Account, req *models.CreateStandingOrderRequest, actorUserID int) (*models.StandingOrder, error) {
+	amount, err := req.Amount.In(account.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid standing order amount: %w", err)
+	}
+	target, err := s.accountRepo.GetAccountByNumber(req.ToAccountNumber)
+	if err != nil {
+		return nil, fmt.Errorf("receiver account not found: %w", err)
+	}
+	if target.ID == account.ID {
+		return nil, fmt.Errorf("cannot transfer to the same account: %w", ErrInvalidStandingOrder)
+	}
+
+	order := &models.StandingOrder{
+		AccountID:               account.ID,
+		ToAccountNumber:         target.AccountNumber,
+		Amount:                  amount,
+		Description:             req.Description,
+		Frequency:               req.Frequency,
+		DayOfMonth:              req.DayOfMonth,
+		MaxRuns:                 req.MaxRuns,
+		InsufficientFundsPolicy: req.InsufficientFundsPolicy,
+		MaxRetries:              req.MaxRetries,
+		Status:                  models.StandingOrderActive,
+		CreatedBy:               actorUserID,
+	}
+	if order.StartDate, err = parseDate(req.StartDate); err != nil {
+		return nil, err
+	}
+	if req.EndDate != "" {
+		endDate, err := parseDate(req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		order.EndDate = &endDate
+	}
+
+	today := time.Now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+	switch {
+	case order.StartDate.Before(today):
+		return nil, fmt.Errorf("start_date may not be in the past: %w", ErrInvalidStandingOrder)
+	case order.EndDate != nil && order.EndDate.Before(order.StartDate):
+		return nil, fmt.Errorf("end_date may not be before start_date: %w", ErrInvalidStandingOrder)
+	case order.Frequency.IsRecurring() && order.EndDate == nil && order.MaxRuns == nil:
+		return nil, fmt.Errorf("recurring orders need an end condition (end_date or max_runs): %w", ErrInvalidStandingOrder)
+	case order.Frequency != models.FrequencyMonthly && order.DayOfMonth != nil:
+		return nil, fmt.Errorf("day_of_month is only valid for monthly orders: %w", ErrInvalidStandingOrder)
+	}
+	if order.Frequency == models.FrequencyMonthly && order.DayOfMonth == nil {
+		day := order.StartDate.Day()
+		order.DayOfMonth = &day
+	}
+
+	switch order.InsufficientFundsPolicy {
+	case "":
+		order.InsufficientFundsPolicy = models.PolicySkip
+		order.MaxRetries = 0
+	case models.PolicyRetry:
+		if order.MaxRetries == 0 {
+			order.MaxRetries = 3
+		}
+	default:
+		order.MaxRetries = 0
+	}
+
+	first := order.FirstRun()
+	if order.EndDate != nil && first.After(*order.EndDate) {
+		return nil, fmt.Errorf("no run falls between start_date and end_date: %w", ErrInvalidStandingOrder)
+	}
+	order.ScheduledFor, order.NextRunAt = &first, &first
+
+	id, err := s.standingOrderRepo.CreateStandingOrder(order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create standing order: %w", err)
+	}
+	newOrder, err := s.standingOrderRepo.GetStandingOrderByID(int(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch newly created standing order: %w", err)
+	}
+	return newOrder, nil
+}
+
+func (s *standingOrderServiceImpl) GetStandingOrderByID(id int) (*models.StandingOrder, error) {
+	order, err := s.standingOrderRepo.GetStandingOrderByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve standing order: %w", err)
+	}
+	return order, nil
+}
+
+func (s *standingOrderServiceImpl) GetStandingOrdersByAccountID(accountID int) ([]models.StandingOrder, error) {
+	orders, err := s.standingOrderRepo.GetStandingOrdersByAccountID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch standing orders: %w", err)
+	}
+	return orders, nil
+}
+
+// UpdateStandingOrder changes the amount, description or end condition of an order, or pauses and
+// resumes it. Resuming also reactivates an order suspended after a failed run.
+func (s *standingOrderServiceImpl) UpdateStandingOrder(id int, req *models.UpdateStandingOrderRequest) (*models.StandingOrder, error) {
+	err := runInTx(func(tx *sql.Tx) error {
+		order, err := s.standingOrderRepo.GetStandingOrderByIDForUpdate(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status == models.StandingOrderCompleted || order.Status == models.StandingOrderCancelled {
+			return fmt.Errorf("standing order is %s: %w", order.Status, ErrInvalidStandingOrder)
+		}
+
+		if req.Amount != nil {
+			if order.Amount, err = req.Amount.In(order.Amount.Currency()); err != nil {
+				return fmt.Errorf("invalid standing order amount: %w", err)
+			}
+		}
+		if req.Description != nil {
+			order.Description = *req.Description
+		}
+		if req.EndDate != nil {
+			endDate, err := parseDate(*req.EndDate)
+			if err != nil {
+				return err
+			}
+			if endDate.Before(order.StartDate) {
+				return fmt.Errorf("end_date may not be before start_date: %w", ErrInvalidStandingOrder)
+			}
+			order.EndDate = &endDate
+		}
+		if req.MaxRuns != nil {
+			order.MaxRuns = req.MaxRuns
+		}
+		if req.Status != nil {
+			order.Status = *req.Status
+		}
+
+		// A tighter end condition may leave nothing to run
+		if order.ScheduledFor != nil && (order.EndDate != nil && order.ScheduledFor.After(*order.EndDate) ||
+			order.MaxRuns != nil && order.RunsCompleted >= *order.MaxRuns) {
+			order.Status = models.StandingOrderCompleted
+			order.ScheduledFor, order.NextRunAt = nil, nil
+		}
+		return s.standingOrderRepo.UpdateStandingOrder(tx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStandingOrderByID(id)
+}
+
+// CancelStandingOrder stops an order permanently. Its execution history is kept.
+func (s *standingOrderServiceImpl) CancelStandingOrder(id int) (*models.StandingOrder, error) {
+	err := runInTx(func(tx *sql.Tx) error {
+		order, err := s.standingOrderRepo.GetStandingOrderByIDForUpdate(tx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status == models.StandingOrderCompleted || order.Status == models.StandingOrderCancelled {
+			return fmt.Errorf("standing order is already %s: %w", order.Status, ErrInvalidStandingOrder)
+		}
+		order.Status = models.StandingOrderCancelled
+		order.ScheduledFor, order.NextRunAt = nil, nil
+		return s.standingOrderRepo.UpdateStandingOrder(tx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStandingOrderByID(id)
+}
+
+func (s *standingOrderServiceImpl) GetExecutions(orderID int) ([]models.StandingOrderExecution, error) {
+	executions, err := s.standingOrderRepo.GetExecutionsByStandingOrderID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch standing order executions: %w", err)
+	}
+	return executions, nil
+}
+
+// RunDue executes every due order once. An order that missed several runs (e.g. while the app
+// was down) catches up one run per scheduler tick. A failure on one order does not stop the others.
+func (s *standingOrderServiceImpl) RunDue(ctx context.Context) error {
+	ids, err := s.standingOrderRepo.GetDueStandingOrderIDs(time.Now(), dueBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.execute(id, time.Now()); err != nil {
+			log.Printf("standing order %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// execute runs one due order. The order row is locked for the whole transaction and the due check
+// is repeated under the lock, so a run is never executed twice even if two schedulers overlap.
+// The transfer, the execution record and the schedule update commit or roll back together.
+func (s *standingOrderServiceImpl) execute(orderID int, now time.Time) error {
+	var notice string
+	var ownerID int
+
+	err := runInTx(func(tx *sql.Tx) error {
+		notice = ""
+		order, err := s.standingOrderRepo.GetStandingOrderByIDForUpdate(tx, orderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.StandingOrderActive || order.NextRunAt == nil || order.NextRunAt.After(now) {
+			return nil // Already handled by another run
+		}
+		from, err := s.accountRepo.GetAccountByID(order.AccountID)
+		if err != nil {
+			return err
+		}
+		ownerID = from.UserID
+
+		execution := &models.StandingOrderExecution{
+			StandingOrderID: order.ID,
+			ScheduledFor:    *order.ScheduledFor,
+			Attempt:         order.RetryCount + 1,
+		}
+
+		transferID, pending, err := s.transferFor(tx, order)
+		switch {
+		case err == nil:
+			id := int(transferID)
+			execution.Status, execution.TransferID = models.ExecutionSucceeded, &id
+			if pending {
+				execution.Status = models.ExecutionPendingApproval
+				notice = fmt.Sprintf("Standing order %d to %s for %s %s is waiting for approval as transfer %d.",
+					order.ID, order.ToAccountNumber, order.Amount, order.Amount.Currency(), id)
+			}
+			order.RunsCompleted++
+			advanceStandingOrder(order)
+
+		case errors.Is(err, ErrInsufficientFunds):
+			execution.Error = err.Error()
+			if order.InsufficientFundsPolicy == models.PolicyRetry && order.RetryCount < order.MaxRetries {
+				execution.Status = models.ExecutionRetrying
+				order.RetryCount++
+				retryAt := now.Add(config.AppCfg.StandingOrder.RetryInterval)
+				order.NextRunAt = &retryAt
+			} else {
+				execution.Status = models.ExecutionSkipped
+				if order.InsufficientFundsPolicy == models.PolicyNotify {
+					notice = fmt.Sprintf("Standing order %d to %s for %s %s was skipped: insufficient funds.",
+						order.ID, order.ToAccountNumber, order.Amount, order.Amount.Currency())
+				}
+				advanceStandingOrder(order)
+			}
+
+		case errors.Is(err, ErrLimitExceeded):
+			// Daily and monthly limits reset on their own: skip this run, keep the order
+			execution.Status, execution.Error = models.ExecutionSkipped, err.Error()
+			notice = fmt.Sprintf("Standing order %d to %s was skipped: %v", order.ID, order.ToAccountNumber, err)
+			advanceStandingOrder(order)
+
+		case isStandingOrderFailure(err):
+			// Retrying cannot help (frozen/closed account, missing rate, ...): suspend until the owner acts
+			execution.Status, execution.Error = models.ExecutionFailed, err.Error()
+			order.Status = models.StandingOrderSuspended
+			notice = fmt.Sprintf("Standing order %d to %s was suspended: %v", order.ID, order.ToAccountNumber, err)
+
+		default:
+			return err // Infrastructure error: roll back and let the next tick try again
+		}
+
+		if _, err := s.standingOrderRepo.CreateExecution(tx, execution); err != nil {
+			return err
+		}
+		return s.standingOrderRepo.UpdateStandingOrder(tx, order)
+	})
+	if err != nil {
+		return err
+	}
+
+	if notice != "" {
+		if err := s.notifier.Notify(ownerID, "Standing order", notice); err != nil {
+			log.Printf("standing order %d: failed to notify user %d: %v", orderID, ownerID, err)
+		}
+	}
+	return nil
+}
+
+// transferFor books the transfer for one run of order inside tx. As in Transfer, an amount above the
+// approval threshold from an account with approvers is only reserved: the transfer waits for an
+// approver other than the order's creator, and pending is true.
+func (s *standingOrderServiceImpl) transferFor(tx *sql.Tx, order *models.StandingOrder) (transferID int64, pending bool, err error) {
+	to, err := s.accountRepo.GetAccountByNumber(order.ToAccountNumber)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to resolve receiver account: %w", err)
+	}
+	approvers, err := s.accountRepo.GetApprovers(order.AccountID)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to check transfer approvers: %w", err)
+	}
+	description := order.Description
+	if description == "" {
+		description = fmt.Sprintf("Standing order %d", order.ID)
+	}
+
+	plan, err := s.bookings.plan(tx, order.AccountID, to.ID, order.Amount, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(approvers) > 0 && exceedsApprovalThreshold(plan.amount) {
+		transferID, err = s.bookings.reserve(tx, plan, description, order.CreatedBy, time.Now().Add(config.AppCfg.Approval.TTL))
+		return transferID, true, err
+	}
+	transferID, err = s.bookings.book(tx, plan, description)
+	return transferID, false, err
+}
+
+// advanceStandingOrder moves order to its next scheduled run, or completes it if none is left.
+func advanceStandingOrder(order *models.StandingOrder) {
+	order.RetryCount = 0
+	next, ok := order.NextRunAfter(*order.ScheduledFor)
+	if !ok {
+		order.Status = models.StandingOrderCompleted
+		order.ScheduledFor, order.NextRunAt = nil, nil
+		return
+	}
+	order.ScheduledFor, order.NextRunAt = &next, &next
+}
+
+// isStandingOrderFailure reports whether err is a business rule failure detected before the
+// transfer wrote anything, as opposed to an infrastructure error.
+func isStandingOrderFailure(err error) bool {
+	return errors.Is(err, ErrAccountNotDebitable) ||
+		errors.Is(err, ErrAccountNotCreditable) ||
+		errors.Is(err, fx.ErrRateNotFound) ||
+		errors.Is(err, money.ErrCurrencyMismatch) ||
+		errors.Is(err, money.ErrTooPrecise) ||
+		errors.Is(err, ErrAccountNotFound)
+}
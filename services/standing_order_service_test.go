@@ -0,0 +1,89 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
+
+// TestStandingOrderAboveApprovalThreshold checks that a scheduled run goes through maker-checker
+// like a manual transfer: above the threshold it only reserves the funds and waits for an approver.
+func TestStandingOrderAboveApprovalThreshold(t *testing.T) {
+	s := newTestServices(t)
+	owner, approver := s.createUser(t, "owner@example.test"), s.createUser(t, "approver@example.test")
+
+	threshold, ok := config.TransferApprovalThreshold(money.DefaultCurrency)
+	if !ok {
+		t.Fatalf("no approval threshold configured for %s", money.DefaultCurrency)
+	}
+	above := threshold.Add(money.MustParse("1", money.DefaultCurrency))
+	below := threshold.Sub(money.MustParse("1", money.DefaultCurrency))
+	from := s.createAccount(t, owner, models.AccountTypeChecking, threshold.Add(threshold))
+	to := s.createAccount(t, owner, models.AccountTypeChecking, money.Zero(money.DefaultCurrency))
+	s.liftLimits(t, from.ID, 10)
+	if _, err := s.accountService.AddApprover(from.ID, approver, owner); err != nil {
+		t.Fatal(err)
+	}
+
+	maxRuns := 1
+	create := func(amount money.Money) *models.StandingOrder {
+		order, err := s.standingOrderService.CreateStandingOrder(from, &models.CreateStandingOrderRequest{
+			ToAccountNumber: to.AccountNumber,
+			Amount:          amount,
+			Frequency:       models.FrequencyOnce,
+			StartDate:       time.Now().Format("2006-01-02"),
+			MaxRuns:         &maxRuns,
+		}, owner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return order
+	}
+	large, small := create(above), create(below)
+	if err := s.standingOrderService.RunDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		order         *models.StandingOrder
+		wantExecution string
+		wantTransfer  string
+	}{
+		{large, models.ExecutionPendingApproval, models.TransferPendingApproval},
+		{small, models.ExecutionSucceeded, models.TransferCompleted},
+	} {
+		executions, err := s.standingOrderService.GetExecutions(tt.order.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(executions) != 1 || executions[0].TransferID == nil {
+			t.Fatalf("order %d: got executions %+v, want one with a transfer", tt.order.ID, executions)
+		}
+		if executions[0].Status != tt.wantExecution {
+			t.Errorf("order %d: execution status %s, want %s", tt.order.ID, executions[0].Status, tt.wantExecution)
+		}
+		transfer, err := s.transferRepo.GetTransferByID(*executions[0].TransferID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if transfer.Status != tt.wantTransfer {
+			t.Errorf("order %d: transfer status %s, want %s", tt.order.ID, transfer.Status, tt.wantTransfer)
+		}
+	}
+
+	// Only the small run was booked; the large one is held until the approver decides
+	account, err := s.accountRepo.GetAccountByID(from.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := threshold.Add(threshold).Sub(below); account.Balance.Cmp(want) != 0 {
+		t.Errorf("balance %s, want %s", account.Balance, want)
+	}
+	if account.HeldAmount.Cmp(above) != 0 {
+		t.Errorf("held amount %s, want %s", account.HeldAmount, above)
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// ErrInvalidStatementPeriod is returned when a statement period is empty, reversed or too long.
+var ErrInvalidStatementPeriod = errors.New("invalid statement period")
+
+// StatementService builds account statements from the transaction history.
+type StatementService interface {
+	GenerateStatement(accountID int, from, to time.Time) (*models.Statement, error)
+}
+
+// statementServiceImpl is the concrete implementation of StatementService.
+type statementServiceImpl struct {
+	accountRepo     repositories.AccountRepository
+	transactionRepo repositories.TransactionRepository
+}
+
+// NewStatementService creates a new instance of StatementService.
+func NewStatementService(accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository) StatementService {
+	return &statementServiceImpl{accountRepo: accountRepo, transactionRepo: transactionRepo}
+}
+
+// GenerateStatement returns the statement for [from, to): the balance carried in from before the
+// period, every transaction in it with the running balance after each line, and the closing balance.
+func (s *statementServiceImpl) GenerateStatement(accountID int, from, to time.Time) (*models.Statement, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("period start must be before its end: %w", ErrInvalidStatementPeriod)
+	}
+	if to.Sub(from) > models.MaxStatementPeriod {
+		return nil, fmt.Errorf("period may not exceed %d days: %w", int(models.MaxStatementPeriod.Hours()/24), ErrInvalidStatementPeriod)
+	}
+
+	account, err := s.accountRepo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account: %w", err)
+	}
+
+	opening, err := s.transactionRepo.GetBalanceBefore(accountID, from, account.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute opening balance: %w", err)
+	}
+	transactions, err := s.transactionRepo.GetTransactionsInPeriod(accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch statement transactions: %w", err)
+	}
+
+	zero := money.Zero(account.Currency)
+	statement := &models.Statement{
+		AccountID:      account.ID,
+		AccountNumber:  account.AccountNumber,
+		Currency:       account.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		TotalCredits:   zero,
+		TotalDebits:    zero,
+		Lines:          make([]models.StatementLine, 0, len(transactions)),
+		GeneratedAt:    time.Now(),
+		OverdraftLimit: account.OverdraftLimit,
+	}
+
+	// A negative balance is overdraft in use; the peak is the lowest balance of the period
+	lowest := opening
+	balance := opening
+	for _, t := range transactions {
+		line := models.StatementLine{
+			TransactionID:   t.ID,
+			TransactionDate: t.TransactionDate,
+			TransactionType: t.TransactionType,
+			Description:     t.Description,
+			Debit:           zero,
+			Credit:          zero,
+		}
+		if t.IsCredit() {
+			line.Credit = t.Amount
+			statement.TotalCredits = statement.TotalCredits.Add(t.Amount)
+		} else {
+			line.Debit = t.Amount
+			statement.TotalDebits = statement.TotalDebits.Add(t.Amount)
+		}
+		balance = balance.Add(t.SignedAmount())
+		line.Balance = balance
+		statement.Lines = append(statement.Lines, line)
+		if balance.LessThan(lowest) {
+			lowest = balance
+		}
+	}
+	statement.ClosingBalance = balance
+	statement.OverdraftUsed, statement.PeakOverdraftUsed = zero, zero
+	if balance.IsNegative() {
+		statement.OverdraftUsed = balance.Neg()
+	}
+	if lowest.IsNegative() {
+		statement.PeakOverdraftUsed = lowest.Neg()
+	}
+	return statement, nil
+}
@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-bank-app/config"
+	"go-bank-app/dialect"
+	"go-bank-app/migrations"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+	"go-bank-app/services"
+)
+
+// openTestDB migrates a fresh SQLite database in a temporary directory and makes it the configured
+// database (config.AppCfg and config.DB, which runInTx uses). Set TEST_DB_DRIVER and TEST_DB_DSN
+// to run against a MySQL or PostgreSQL server instead; that database is migrated up but not
+// cleaned afterwards.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Database.Driver = dialect.SQLite
+	cfg.Database.DSN = "file:" + filepath.Join(t.TempDir(), "bank.db") +
+		"?_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)&_txlock=immediate"
+	if driver := os.Getenv("TEST_DB_DRIVER"); driver != "" {
+		cfg.Database.Driver, cfg.Database.DSN = driver, os.Getenv("TEST_DB_DSN")
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	previousCfg, previousDB := config.AppCfg, config.DB
+	config.AppCfg = cfg
+	db, err := sql.Open(config.Dialect().DriverName(), cfg.Database.DSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.DB = db
+	t.Cleanup(func() {
+		db.Close()
+		config.AppCfg, config.DB = previousCfg, previousDB
+	})
+	migrator, err := migrations.New(db, config.Dialect())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// testServices is the application wired the way main.go wires it, on a test database, but
+// without a fee schedule or exchange rates so every amount moves one to one.
+type testServices struct {
+	db *sql.DB
+
+	userRepo          repositories.UserRepository
+	accountRepo       repositories.AccountRepository
+	transactionRepo   repositories.TransactionRepository
+	ledgerRepo        repositories.LedgerRepository
+	transferRepo      repositories.TransferRepository
+	holdRepo          repositories.HoldRepository
+	limitRepo         repositories.LimitRepository
+	standingOrderRepo repositories.StandingOrderRepository
+	interestRepo      repositories.InterestRepository
+
+	accountService       services.AccountService
+	transactionService   services.TransactionService
+	holdService          services.HoldService
+	overdraftService     services.OverdraftService
+	standingOrderService services.StandingOrderService
+	interestService      services.InterestService
+	ledgerService        services.LedgerService
+}
+
+// newTestServices opens a test database with openTestDB and builds every repository and service
+// on it.
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+	db := openTestDB(t)
+	d := config.Dialect()
+	s := &testServices{
+		db:                db,
+		userRepo:          repositories.NewUserRepository(db, d),
+		accountRepo:       repositories.NewAccountRepository(db, d),
+		transactionRepo:   repositories.NewTransactionRepository(db, d),
+		ledgerRepo:        repositories.NewLedgerRepository(db, d),
+		transferRepo:      repositories.NewTransferRepository(db, d),
+		holdRepo:          repositories.NewHoldRepository(db, d),
+		limitRepo:         repositories.NewLimitRepository(db, d),
+		standingOrderRepo: repositories.NewStandingOrderRepository(db, d),
+		interestRepo:      repositories.NewInterestRepository(db, d),
+	}
+	s.accountService = services.NewAccountService(s.accountRepo, s.transactionRepo, s.transferRepo, s.ledgerRepo, s.holdRepo, s.limitRepo, s.interestRepo, nil)
+	s.transactionService = services.NewTransactionService(s.accountRepo, s.transactionRepo, s.transferRepo, s.ledgerRepo, s.holdRepo, s.limitRepo, nil, nil)
+	s.holdService = services.NewHoldService(s.accountRepo, s.transactionRepo, s.ledgerRepo, s.holdRepo)
+	s.overdraftService = services.NewOverdraftService(s.accountRepo)
+	s.standingOrderService = services.NewStandingOrderService(s.standingOrderRepo, s.accountRepo, s.transactionRepo, s.transferRepo,
+		s.ledgerRepo, s.holdRepo, s.limitRepo, nil, nil, services.NewLogNotifier())
+	s.interestService = services.NewInterestService(s.interestRepo, s.accountRepo, s.transactionRepo, s.ledgerRepo)
+	s.ledgerService = services.NewLedgerService(s.ledgerRepo)
+	return s
+}
+
+// createUser registers a customer with the given email and returns its ID.
+func (s *testServices) createUser(t *testing.T, email string) int {
+	t.Helper()
+	id, err := s.userRepo.CreateUser(&models.User{Name: email, Email: email, PasswordHash: "-"})
+	if err != nil {
+		t.Fatalf("create user %s: %v", email, err)
+	}
+	return int(id)
+}
+
+// createAccount opens an account of the given type for userID and deposits initial into it,
+// unless initial is zero.
+func (s *testServices) createAccount(t *testing.T, userID int, accountType models.AccountType, initial money.Money) *models.Account {
+	t.Helper()
+	account, err := s.accountService.CreateAccount(&models.CreateAccountRequest{UserID: userID, Currency: string(initial.Currency()), Type: accountType})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if !initial.IsZero() {
+		if account, err = s.accountService.Deposit(account.ID, initial); err != nil {
+			t.Fatalf("deposit: %v", err)
+		}
+	}
+	return account
+}
+
+// liftLimits overrides the debit limits of an account so a test measures something else.
+func (s *testServices) liftLimits(t *testing.T, accountID, dailyTransfers int) {
+	t.Helper()
+	unlimited := money.MustParse("1000000000000", money.DefaultCurrency)
+	limits := &models.AccountLimits{PerTransaction: &unlimited, DailyDebit: &unlimited, MonthlyDebit: &unlimited, DailyTransfers: &dailyTransfers}
+	if err := s.limitRepo.SetOverrides(accountID, limits); err != nil {
+		t.Fatalf("lift limits: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-bank-app/auth"
+	"go-bank-app/config"
+	"go-bank-app/models"
+	"go-bank-app/repositories"
+)
+
+var (
+	// ErrInvalidRefreshToken is returned for unknown, expired or logged-out refresh tokens.
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused is returned when a refresh token that was already rotated is presented
+	// again. This means the token leaked, so the whole token family is revoked.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected, session revoked")
+)
+
+// TokenService defines the interface for issuing, rotating and revoking tokens.
+type TokenService interface {
+	IssueTokens(userID int) (*models.TokenPair, error)
+	Refresh(refreshToken string) (*models.TokenPair, error)
+	Logout(userID int, accessTokenID string, accessTokenExpiresAt time.Time, refreshToken string) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+}
+
+// tokenServiceImpl is the concrete implementation of TokenService.
+type tokenServiceImpl struct {
+	tokenRepo repositories.TokenRepository
+	userRepo  repositories.UserRepository // Role and language are re-read on every refresh so changes take effect
+}
+
+// NewTokenService creates a new instance of TokenService.
+func NewTokenService(tokenRepo repositories.TokenRepository, userRepo repositories.UserRepository) TokenService {
+	return &tokenServiceImpl{tokenRepo: tokenRepo, userRepo: userRepo}
+}
+
+// IssueTokens starts a new session (token family) for the user, e.g. after login.
+func (s *tokenServiceImpl) IssueTokens(userID int) (*models.TokenPair, error) {
+	familyID, err := auth.NewTokenFamilyID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token family: %w", err)
+	}
+
+	var refreshToken string
+	err = runInTx(func(tx *sql.Tx) error {
+		refreshToken, _, err = s.createRefreshToken(tx, userID, familyID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.tokenPair(userID, refreshToken)
+}
+
+// Refresh exchanges a valid refresh token for a new access token and a new refresh token.
+// The presented token is revoked in the same transaction (rotation).
+func (s *tokenServiceImpl) Refresh(refreshToken string) (*models.TokenPair, error) {
+	var (
+		userID       int
+		newToken     string
+		reusedFamily string
+	)
+
+	err := runInTx(func(tx *sql.Tx) error {
+		stored, err := s.tokenRepo.GetRefreshTokenByHashForUpdate(tx, auth.HashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		if stored.RevokedAt != nil {
+			if stored.ReplacedByID != nil {
+				reusedFamily = stored.FamilyID
+				return ErrRefreshTokenReused
+			}
+			return ErrInvalidRefreshToken
+		}
+		if time.Now().After(stored.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		var newID int64
+		newToken, newID, err = s.createRefreshToken(tx, stored.UserID, stored.FamilyID)
+		if err != nil {
+			return err
+		}
+		if err := s.tokenRepo.MarkRefreshTokenRotated(tx, stored.ID, int(newID)); err != nil {
+			return err
+		}
+		userID = stored.UserID
+		return nil
+	})
+
+	if errors.Is(err, ErrRefreshTokenReused) {
+		// Revoked outside the transaction above, which still held a lock on one of the family's rows
+		log.Printf("Refresh token reuse detected for token family %s, revoking family", reusedFamily)
+		if revokeErr := s.tokenRepo.RevokeRefreshTokenFamily(reusedFamily); revokeErr != nil {
+			return nil, revokeErr
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.tokenPair(userID, newToken)
+}
+
+// Logout revokes the current access token and, if given, the refresh token's whole family.
+func (s *tokenServiceImpl) Logout(userID int, accessTokenID string, accessTokenExpiresAt time.Time, refreshToken string) error {
+	if err := s.tokenRepo.RevokeAccessToken(accessTokenID, userID, accessTokenExpiresAt); err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	var familyID string
+	err := runInTx(func(tx *sql.Tx) error {
+		stored, err := s.tokenRepo.GetRefreshTokenByHashForUpdate(tx, auth.HashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		// Users may only end their own sessions
+		if stored.UserID != userID {
+			return ErrInvalidRefreshToken
+		}
+		familyID = stored.FamilyID
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.tokenRepo.RevokeRefreshTokenFamily(familyID)
+}
+
+// IsAccessTokenRevoked reports whether an access token was revoked by logout.
+func (s *tokenServiceImpl) IsAccessTokenRevoked(jti string) (bool, error) {
+	return s.tokenRepo.IsAccessTokenRevoked(jti)
+}
+
+func (s *tokenServiceImpl) createRefreshToken(tx *sql.Tx, userID int, familyID string) (string, int64, error) {
+	token, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	id, err := s.tokenRepo.CreateRefreshToken(tx, &models.RefreshToken{
+		UserID:    userID,
+		TokenHash: auth.HashToken(token),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(config.AppCfg.Auth.RefreshTokenTTL),
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return token, id, nil
+}
+
+func (s *tokenServiceImpl) tokenPair(userID int, refreshToken string) (*models.TokenPair, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user for access token: %w", err)
+	}
+	accessToken, _, err := auth.GenerateJWTToken(user.ID, user.Role, user.PreferredLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(config.AppCfg.Auth.AccessTokenTTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go-bank-app/models"
+)
+
+// Reversal errors.
+var (
+	ErrAlreadyReversed = errors.New("transaction has already been reversed")
+	ErrNotReversible   = errors.New("transaction cannot be reversed")
+)
+
+// ReverseTransaction corrects a mistaken transaction by posting a compensating journal entry that
+// undoes the original one. Every leg recorded by the original entry is reversed together (both
+// legs of a transfer, including any FX conversion), each with its own reversal transaction that
+// points back at it. A transaction can be reversed once; reversals themselves cannot be reversed.
+//
+// Money that has to be taken back (the reversal of a deposit or of a transfer_in) must still be on
+// the account and not held; the reversal neither pushes balances negative nor uses the overdraft.
+func (s *transactionServiceImpl) ReverseTransaction(transactionID int, reason string, actorUserID int) (*models.TransactionReversal, error) {
+	original, err := s.transactionRepo.GetTransactionByID(transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transaction: %w", err)
+	}
+	if original.ReversalOf != nil {
+		return nil, fmt.Errorf("transaction %d is itself a reversal: %w", original.ID, ErrNotReversible)
+	}
+	if original.JournalEntryID == 0 {
+		return nil, fmt.Errorf("transaction %d was not posted to the ledger: %w", original.ID, ErrNotReversible)
+	}
+
+	// Every leg of the same business event shares the journal entry. Legs never change apart from
+	// being marked reversed, so the accounts to lock can be read up front.
+	legs, err := s.transactionRepo.GetTransactionsByJournalEntryID(original.JournalEntryID)
+	if err != nil {
+		return nil, err
+	}
+	transferID, err := s.transferRepo.GetTransferIDByJournalEntryID(original.JournalEntryID)
+	if err != nil {
+		return nil, err
+	}
+	accountIDs := make([]int, 0, len(legs))
+	for _, leg := range legs {
+		accountIDs = append(accountIDs, leg.AccountID)
+	}
+
+	result := &models.TransactionReversal{OriginalTransactionID: original.ID, Reason: reason, ActorUserID: actorUserID}
+	var reversalIDs []int
+	// The transaction is retried automatically on deadlock / lock wait timeout
+	err = runInTx(func(tx *sql.Tx) error {
+		reversalIDs = reversalIDs[:0]
+
+		// Lock order: transfer row, then accounts, then the transaction rows
+		var transfer *models.Transfer
+		if transferID != 0 {
+			locked, err := s.transferRepo.GetTransferByIDForUpdate(tx, transferID)
+			if err != nil {
+				return err
+			}
+			if locked.Status == models.TransferReversed {
+				return fmt.Errorf("transfer %d: %w", locked.ID, ErrAlreadyReversed)
+			}
+			transfer = locked
+		}
+		locked, err := lockAccountsInOrder(tx, s.accountRepo, accountIDs...)
+		if err != nil {
+			return err
+		}
+
+		// Check every leg before writing anything
+		for i := range legs {
+			leg, err := s.transactionRepo.GetTransactionByIDForUpdate(tx, legs[i].ID)
+			if err != nil {
+				return err
+			}
+			if leg.Reversed {
+				return fmt.Errorf("transaction %d: %w", leg.ID, ErrAlreadyReversed)
+			}
+			account := locked[leg.AccountID]
+			if err := checkCredit(account); err != nil { // Closed accounts cannot be corrected
+				return err
+			}
+			if leg.IsCredit() {
+				// Only the account's own unheld funds count: taking money back must not draw on its overdraft
+				if account.Balance.Sub(account.HeldAmount).LessThan(leg.Amount) {
+					return fmt.Errorf("account %s no longer holds the %s to take back: %w", account.AccountNumber, leg.Amount, ErrInsufficientFunds)
+				}
+				account.Balance = account.Balance.Sub(leg.Amount)
+			}
+			legs[i] = *leg
+		}
+
+		entryID, err := s.bookings.ledger.reverse(tx, original.JournalEntryID,
+			"REVERSAL-"+strconv.Itoa(original.JournalEntryID), "Reversal: "+reason)
+		if err != nil {
+			return fmt.Errorf("failed to post reversal: %w", err)
+		}
+		result.JournalEntryID = entryID
+
+		for _, leg := range legs {
+			reversalType := models.TransactionReversalIn
+			if leg.IsCredit() {
+				reversalType = models.TransactionReversalOut
+			}
+			legID := leg.ID
+			id, err := s.transactionRepo.CreateTransaction(tx, &models.Transaction{
+				AccountID:       leg.AccountID,
+				TransactionType: reversalType,
+				Amount:          leg.Amount,
+				Description:     fmt.Sprintf("Reversal of #%d: %s", leg.ID, reason),
+				JournalEntryID:  entryID,
+				ReversalOf:      &legID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record reversal transaction: %w", err)
+			}
+			if err := s.transactionRepo.MarkTransactionReversed(tx, leg.ID, int(id)); err != nil {
+				return err
+			}
+			reversalIDs = append(reversalIDs, int(id))
+		}
+
+		if transfer != nil {
+			transfer.Status = models.TransferReversed
+			if err := s.transferRepo.UpdateTransfer(tx, transfer); err != nil {
+				return err
+			}
+			result.TransferID = &transfer.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range reversalIDs {
+		t, err := s.transactionRepo.GetTransactionByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("reversal succeeded, but failed to retrieve it: %w", err)
+		}
+		result.Transactions = append(result.Transactions, *t)
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"go-bank-app/config"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/services"
+)
+
+// TestReversalDoesNotUseOverdraft reverses a deposit that has partly been spent on an account with
+// an overdraft facility. The facility must not pay for taking the money back.
+func TestReversalDoesNotUseOverdraft(t *testing.T) {
+	s := newTestServices(t)
+	userID := s.createUser(t, "spender@example.test")
+	account := s.createAccount(t, userID, models.AccountTypeChecking, money.Zero(money.IDR))
+	withOverdraft, err := s.overdraftService.UpdateOverdraft(account.ID, &models.UpdateOverdraftRequest{Limit: money.MustParse("5000000", money.IDR)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withOverdraft.OverdraftLimit.Cmp(money.MustParse("5000000", money.IDR)) != 0 || withOverdraft.OverdraftInterestRate != config.AppCfg.Overdraft.InterestRate {
+		t.Fatalf("overdraft limit %s at %s%%, want 5000000.00 at the configured rate", withOverdraft.OverdraftLimit, withOverdraft.OverdraftInterestRate)
+	}
+	if _, err := s.accountService.Deposit(account.ID, money.MustParse("1000000", money.IDR)); err != nil {
+		t.Fatal(err)
+	}
+	transactions, err := s.transactionRepo.GetTransactionsByAccountID(account.ID, models.TransactionFilter{Limit: 10})
+	if err != nil || len(transactions) != 1 {
+		t.Fatalf("transactions %+v, %v; want the deposit", transactions, err)
+	}
+	deposit := transactions[0]
+	if _, err := s.accountService.Withdraw(account.ID, money.MustParse("800000", money.IDR)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 200,000 left of the 1,000,000 deposit; the 5,000,000 facility must not cover the rest
+	if _, err := s.transactionService.ReverseTransaction(deposit.ID, "deposited to the wrong account", userID); !errors.Is(err, services.ErrInsufficientFunds) {
+		t.Fatalf("reversal of a spent deposit: got %v, want ErrInsufficientFunds", err)
+	}
+
+	// Held money is not the account's to give back either
+	if _, err := s.accountService.Deposit(account.ID, money.MustParse("900000", money.IDR)); err != nil {
+		t.Fatal(err)
+	}
+	hold, err := s.holdService.CreateHold(account.ID, &models.CreateHoldRequest{
+		Type:   models.HoldTypeLegal,
+		Amount: money.MustParse("200000", money.IDR),
+		Reason: "court order",
+	}, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.transactionService.ReverseTransaction(deposit.ID, "deposited to the wrong account", userID); !errors.Is(err, services.ErrInsufficientFunds) {
+		t.Fatalf("reversal into held funds: got %v, want ErrInsufficientFunds", err)
+	}
+
+	// Once the hold is gone the 1,100,000 on the account covers the reversal
+	if _, err := s.holdService.ReleaseHold(hold.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.transactionService.ReverseTransaction(deposit.ID, "deposited to the wrong account", userID); err != nil {
+		t.Fatal(err)
+	}
+	after, err := s.accountRepo.GetAccountByID(account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := money.MustParse("100000", money.IDR); after.Balance.Cmp(want) != 0 {
+		t.Errorf("balance %s, want %s", after.Balance, want)
+	}
+}
@@ -1,99 +1,140 @@
 package services
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"go-bank-app/config" // For accessing config.DB.Begin()
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/fees"
+	"go-bank-app/fx"
 	"go-bank-app/models"
 	"go-bank-app/repositories"
 )
 
 // TransactionService defines the interface for transaction-related business logic.
 type TransactionService interface {
-	Transfer(req *models.TransferRequest) error
-	GetAccountTransactions(accountID int) ([]models.Transaction, error)
+	Transfer(req *models.TransferRequest, requestedBy int) (*models.Transfer, error) // Large transfers wait for approval
+	ApproveTransfer(transferID, approverID int) (*models.Transfer, error)
+	RejectTransfer(transferID, approverID int, reason string) (*models.Transfer, error)
+	GetPendingApprovals(approverID int) ([]models.Transfer, error)
+	ExpirePendingTransfers(ctx context.Context) error // Scheduler job: releases the holds of expired approvals
+	GetTransferByID(id int) (*models.Transfer, error)
+	GetTransfersByUserID(userID int) ([]models.Transfer, error)
+	GetAccountTransactions(accountID int, filter models.TransactionFilter) (*models.TransactionPage, error)
+	ReverseTransaction(transactionID int, reason string, actorUserID int) (*models.TransactionReversal, error) // Compensating entry for every leg
 }
 
 // transactionServiceImpl is the concrete implementation of TransactionService.
 type transactionServiceImpl struct {
 	accountRepo     repositories.AccountRepository
 	transactionRepo repositories.TransactionRepository
+	transferRepo    repositories.TransferRepository
+	holdRepo        repositories.HoldRepository
+	bookings        *transferBookings
 }
 
 // NewTransactionService creates a new instance of TransactionService.
-func NewTransactionService(accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository) TransactionService {
-	return &transactionServiceImpl{accountRepo: accountRepo, transactionRepo: transactionRepo}
-}
-
-func (s *transactionServiceImpl) Transfer(req *models.TransferRequest) error {
-	tx, err := config.DB.Begin() // Start transaction at the service layer
-	if err != nil {
-		return fmt.Errorf("failed to begin transfer transaction: %w", err)
+func NewTransactionService(accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository, transferRepo repositories.TransferRepository, ledgerRepo repositories.LedgerRepository, holdRepo repositories.HoldRepository, limitRepo repositories.LimitRepository, feeSchedule *fees.Schedule, rates fx.RateProvider) TransactionService {
+	return &transactionServiceImpl{
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		transferRepo:    transferRepo,
+		holdRepo:        holdRepo,
+		bookings:        newTransferBookings(accountRepo, transactionRepo, transferRepo, ledgerRepo, holdRepo, limitRepo, feeSchedule, rates),
 	}
-	defer tx.Rollback() // Ensure rollback in case of error
+}
 
-	// Get sender and receiver accounts (use tx to ensure row-level locking if needed)
-	fromAccount, err := s.accountRepo.GetAccountByNumber(req.FromAccountID)
+// Transfer moves money between two accounts. If the sender has approvers and the amount is above
+// the approval threshold, the funds are only held and the transfer waits for a second user
+// (see ApproveTransfer); the returned transfer then has status pending_approval.
+func (s *transactionServiceImpl) Transfer(req *models.TransferRequest, requestedBy int) (*models.Transfer, error) {
+	// Resolve both account numbers first (without locks) so the rows can be locked by ID below
+	fromRef, err := s.accountRepo.GetAccountByNumber(req.FromAccountID)
 	if err != nil {
-		return fmt.Errorf("sender account not found: %w", err)
+		return nil, fmt.Errorf("sender account not found: %w", err)
 	}
-	toAccount, err := s.accountRepo.GetAccountByNumber(req.ToAccountID)
+	toRef, err := s.accountRepo.GetAccountByNumber(req.ToAccountID)
 	if err != nil {
-		return fmt.Errorf("receiver account not found: %w", err)
+		return nil, fmt.Errorf("receiver account not found: %w", err)
 	}
-
-	// Check sufficient balance
-	if fromAccount.Balance < req.Amount {
-		return fmt.Errorf("insufficient balance in sender's account")
+	if fromRef.ID == toRef.ID {
+		return nil, fmt.Errorf("cannot transfer to the same account: %w", ErrSameAccount)
 	}
 
-	// Debit sender's account
-	err = s.accountRepo.UpdateAccountBalance(tx, fromAccount.ID, -req.Amount)
+	approvers, err := s.accountRepo.GetApprovers(fromRef.ID)
 	if err != nil {
-		return fmt.Errorf("failed to update sender's account balance: %w", err)
+		return nil, fmt.Errorf("failed to check transfer approvers: %w", err)
 	}
 
-	// Credit receiver's account
-	err = s.accountRepo.UpdateAccountBalance(tx, toAccount.ID, req.Amount)
+	var transferID int64
+	// The transaction is retried automatically on deadlock / lock wait timeout
+	err = runInTx(func(tx *sql.Tx) error {
+		plan, err := s.bookings.plan(tx, fromRef.ID, toRef.ID, req.Amount, nil)
+		if err != nil {
+			return err
+		}
+		if len(approvers) > 0 && exceedsApprovalThreshold(plan.amount) {
+			transferID, err = s.bookings.reserve(tx, plan, req.Description, requestedBy, time.Now().Add(config.AppCfg.Approval.TTL))
+		} else {
+			transferID, err = s.bookings.book(tx, plan, req.Description)
+		}
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update receiver's account balance: %w", err)
+		return nil, err
 	}
 
-	// Record outbound transaction for sender
-	outboundTransaction := &models.Transaction{
-		AccountID:       fromAccount.ID,
-		TransactionType: "transfer_out",
-		Amount:          req.Amount,
-		Description:     fmt.Sprintf("Transfer to %s: %s", toAccount.AccountNumber, req.Description),
-	}
-	_, err = s.transactionRepo.CreateTransaction(tx, outboundTransaction)
+	transfer, err := s.transferRepo.GetTransferByID(int(transferID))
 	if err != nil {
-		return fmt.Errorf("failed to record outbound transaction: %w", err)
+		return nil, fmt.Errorf("transfer succeeded, but failed to retrieve it: %w", err)
 	}
+	return transfer, nil
+}
 
-	// Record inbound transaction for receiver
-	inboundTransaction := &models.Transaction{
-		AccountID:       toAccount.ID,
-		TransactionType: "transfer_in",
-		Amount:          req.Amount,
-		Description:     fmt.Sprintf("Transfer from %s: %s", fromAccount.AccountNumber, req.Description),
-	}
-	_, err = s.transactionRepo.CreateTransaction(tx, inboundTransaction)
+func (s *transactionServiceImpl) GetTransferByID(id int) (*models.Transfer, error) {
+	transfer, err := s.transferRepo.GetTransferByID(id)
 	if err != nil {
-		return fmt.Errorf("failed to record inbound transaction: %w", err)
+		return nil, fmt.Errorf("failed to retrieve transfer: %w", err)
 	}
+	return transfer, nil
+}
 
-	err = tx.Commit() // Commit transaction
+func (s *transactionServiceImpl) GetTransfersByUserID(userID int) ([]models.Transfer, error) {
+	transfers, err := s.transferRepo.GetTransfersByUserID(userID)
 	if err != nil {
-		return fmt.Errorf("failed to commit transfer transaction: %w", err)
+		return nil, fmt.Errorf("failed to fetch transfers: %w", err)
 	}
-
-	return nil
+	return transfers, nil
 }
 
-func (s *transactionServiceImpl) GetAccountTransactions(accountID int) ([]models.Transaction, error) {
-	transactions, err := s.transactionRepo.GetTransactionsByAccountID(accountID)
+// GetAccountTransactions returns one page of an account's history. One extra row is fetched to
+// learn whether another page exists; if so, NextCursor points at the last row returned. A limit
+// above MaxTransactionPageSize is clamped to it; a missing one means DefaultTransactionPageSize.
+func (s *transactionServiceImpl) GetAccountTransactions(accountID int, filter models.TransactionFilter) (*models.TransactionPage, error) {
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = models.DefaultTransactionPageSize
+	case filter.Limit > models.MaxTransactionPageSize:
+		filter.Limit = models.MaxTransactionPageSize
+	}
+	pageSize := filter.Limit
+	filter.Limit++
+
+	transactions, err := s.transactionRepo.GetTransactionsByAccountID(accountID, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch account transactions: %w", err)
 	}
-	return transactions, nil
+
+	page := &models.TransactionPage{Transactions: transactions}
+	if len(transactions) > pageSize {
+		page.Transactions = transactions[:pageSize]
+		last := page.Transactions[pageSize-1]
+		page.NextCursor = models.Cursor{Time: last.TransactionDate, ID: last.ID}.Encode()
+	}
+	if page.Transactions == nil {
+		page.Transactions = []models.Transaction{}
+	}
+	return page, nil
 }
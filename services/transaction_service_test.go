@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go-bank-app/models"
+	"go-bank-app/repositories"
+)
+
+// pageRepo returns rows rows newest first and records the filter it was asked with.
+type pageRepo struct {
+	repositories.TransactionRepository
+	rows   int
+	filter models.TransactionFilter
+}
+
+func (r *pageRepo) GetTransactionsByAccountID(accountID int, filter models.TransactionFilter) ([]models.Transaction, error) {
+	r.filter = filter
+	n := r.rows
+	if n > filter.Limit {
+		n = filter.Limit
+	}
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	transactions := make([]models.Transaction, n)
+	for i := range transactions {
+		transactions[i] = models.Transaction{ID: r.rows - i, TransactionDate: start.Add(-time.Duration(i) * time.Minute)}
+	}
+	return transactions, nil
+}
+
+func TestGetAccountTransactionsPageSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantQuery int // Rows requested from the repository: the page size plus one
+	}{
+		{"default", 0, models.DefaultTransactionPageSize + 1},
+		{"negative", -3, models.DefaultTransactionPageSize + 1},
+		{"within range", 20, 21},
+		{"maximum", models.MaxTransactionPageSize, models.MaxTransactionPageSize + 1},
+		{"clamped", models.MaxTransactionPageSize + 500, models.MaxTransactionPageSize + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &pageRepo{rows: 1000}
+			s := &transactionServiceImpl{transactionRepo: repo}
+			page, err := s.GetAccountTransactions(1, models.TransactionFilter{Limit: tt.limit})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.filter.Limit != tt.wantQuery {
+				t.Errorf("repository asked for %d rows, want %d", repo.filter.Limit, tt.wantQuery)
+			}
+			if len(page.Transactions) != tt.wantQuery-1 {
+				t.Errorf("page has %d rows, want %d", len(page.Transactions), tt.wantQuery-1)
+			}
+			if page.NextCursor == "" {
+				t.Error("NextCursor is empty although more rows exist")
+			}
+		})
+	}
+}
+
+func TestGetAccountTransactionsLastPage(t *testing.T) {
+	repo := &pageRepo{rows: 3}
+	s := &transactionServiceImpl{transactionRepo: repo}
+	page, err := s.GetAccountTransactions(1, models.TransactionFilter{Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Transactions) != 3 || page.NextCursor != "" {
+		t.Errorf("got %d rows and cursor %q, want 3 rows and no cursor", len(page.Transactions), page.NextCursor)
+	}
+
+	// A full page that is not the last one points at its final row
+	repo.rows = 10
+	page, _ = s.GetAccountTransactions(1, models.TransactionFilter{Limit: 3})
+	cursor, err := models.DecodeCursor(page.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := page.Transactions[2]
+	if cursor.ID != last.ID || !cursor.Time.Equal(last.TransactionDate) {
+		t.Errorf("cursor %+v does not point at the last row %d", *cursor, last.ID)
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
+
+// Approval workflow errors.
+var (
+	ErrTransferNotPending = errors.New("transfer is not awaiting approval")
+	ErrNotApprover        = errors.New("user is not an approver for this transfer")
+	ErrApprovalExpired    = errors.New("transfer approval has expired")
+)
+
+// expiryBatchSize caps how many expired approvals one scheduler run handles.
+const expiryBatchSize = 100
+
+// exceedsApprovalThreshold reports whether amount needs a second user's approval.
+func exceedsApprovalThreshold(amount money.Money) bool {
+	threshold, ok := config.TransferApprovalThreshold(amount.Currency())
+	return ok && amount.GreaterThan(threshold)
+}
+
+// ApproveTransfer books a pending transfer. The approver must be on the sender's approver list and
+// may not be the user who created the transfer. Account status and the exchange rate are checked
+// again at this point; the held amount is captured as part of the booking.
+func (s *transactionServiceImpl) ApproveTransfer(transferID, approverID int) (*models.Transfer, error) {
+	expired := false
+	err := runInTx(func(tx *sql.Tx) error {
+		expired = false
+		transfer, err := s.lockPendingTransfer(tx, transferID, approverID)
+		if err != nil {
+			return err
+		}
+		if time.Now().After(transfer.Approval.ExpiresAt) {
+			expired = true
+			return s.closePending(tx, transfer, models.TransferExpired, nil, "")
+		}
+
+		// The held amount counts as available for this transfer. The hold is locked only after the
+		// accounts, the same order every other hold change uses.
+		hold, err := s.holdRepo.GetHoldByID(*transfer.Approval.HoldID)
+		if err != nil {
+			return err
+		}
+		plan, err := s.bookings.plan(tx, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount, hold)
+		if err != nil {
+			return err
+		}
+		if hold, err = s.holdRepo.GetHoldByIDForUpdate(tx, hold.ID); err != nil {
+			return err
+		}
+		posted, err := s.bookings.post(tx, plan, transfer.Description)
+		if err != nil {
+			return err
+		}
+		captured := plan.total()
+		hold.CapturedAmount, hold.TransactionID = &captured, posted.OutboundTransactionID
+		if err := endHold(tx, s.accountRepo, s.holdRepo, hold, models.HoldCaptured); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		posted.ID = transfer.ID
+		posted.Approval = transfer.Approval
+		posted.Approval.DecidedBy, posted.Approval.DecidedAt = &approverID, &now
+		return s.transferRepo.UpdateTransfer(tx, posted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, ErrApprovalExpired
+	}
+	return s.GetTransferByID(transferID)
+}
+
+// RejectTransfer cancels a pending transfer and releases its hold. Nothing is posted.
+func (s *transactionServiceImpl) RejectTransfer(transferID, approverID int, reason string) (*models.Transfer, error) {
+	err := runInTx(func(tx *sql.Tx) error {
+		transfer, err := s.lockPendingTransfer(tx, transferID, approverID)
+		if err != nil {
+			return err
+		}
+		return s.closePending(tx, transfer, models.TransferRejected, &approverID, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTransferByID(transferID)
+}
+
+func (s *transactionServiceImpl) GetPendingApprovals(approverID int) ([]models.Transfer, error) {
+	transfers, err := s.transferRepo.GetPendingApprovalsForApprover(approverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pending approvals: %w", err)
+	}
+	if transfers == nil {
+		transfers = []models.Transfer{}
+	}
+	return transfers, nil
+}
+
+// ExpirePendingTransfers marks every pending transfer past its approval window as expired and
+// releases its hold. An approval arriving later fails with ErrApprovalExpired.
+func (s *transactionServiceImpl) ExpirePendingTransfers(ctx context.Context) error {
+	ids, err := s.transferRepo.GetExpiredPendingTransferIDs(time.Now(), expiryBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err := runInTx(func(tx *sql.Tx) error {
+			transfer, err := s.transferRepo.GetTransferByIDForUpdate(tx, id)
+			if err != nil {
+				return err
+			}
+			if transfer.Status != models.TransferPendingApproval || !time.Now().After(transfer.Approval.ExpiresAt) {
+				return nil // Decided in the meantime
+			}
+			return s.closePending(tx, transfer, models.TransferExpired, nil, "")
+		})
+		if err != nil {
+			log.Printf("transfer %d: failed to expire approval: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// lockPendingTransfer locks a transfer and checks that it awaits approval and that approverID may decide it.
+func (s *transactionServiceImpl) lockPendingTransfer(tx *sql.Tx, transferID, approverID int) (*models.Transfer, error) {
+	transfer, err := s.transferRepo.GetTransferByIDForUpdate(tx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status != models.TransferPendingApproval {
+		return nil, fmt.Errorf("transfer %d is %s: %w", transfer.ID, transfer.Status, ErrTransferNotPending)
+	}
+	if transfer.Approval.RequestedBy == approverID {
+		return nil, fmt.Errorf("the maker of a transfer cannot approve it: %w", ErrNotApprover)
+	}
+	ok, err := s.accountRepo.IsApprover(transfer.FromAccountID, approverID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotApprover
+	}
+	return transfer, nil
+}
+
+// closePending ends a pending transfer without booking it and releases its hold. The sender's
+// account is locked first because every hold change happens under that lock.
+func (s *transactionServiceImpl) closePending(tx *sql.Tx, transfer *models.Transfer, status string, decidedBy *int, reason string) error {
+	if _, err := s.accountRepo.GetAccountByIDForUpdate(tx, transfer.FromAccountID); err != nil {
+		return err
+	}
+	hold, err := s.holdRepo.GetHoldByIDForUpdate(tx, *transfer.Approval.HoldID)
+	if err != nil {
+		return err
+	}
+	if err := endHold(tx, s.accountRepo, s.holdRepo, hold, models.HoldReleased); err != nil {
+		return err
+	}
+	now := time.Now()
+	transfer.Status = status
+	transfer.Approval.DecidedBy, transfer.Approval.DecidedAt, transfer.Approval.Reason = decidedBy, &now, reason
+	return s.transferRepo.UpdateTransfer(tx, transfer)
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/fees"
+	"go-bank-app/fx"
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// ErrInsufficientFunds is returned when the sender cannot cover a withdrawal or transfer.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// transferBookings posts internal transfers between two customer accounts. It is shared by
+// TransactionService.Transfer, transfer approvals, standing orders and the balance sweep of
+// AccountService.CloseAccount so all of them produce the same journal entry, transaction legs
+// and transfer record.
+type transferBookings struct {
+	accountRepo     repositories.AccountRepository
+	transactionRepo repositories.TransactionRepository
+	transferRepo    repositories.TransferRepository
+	holdRepo        repositories.HoldRepository
+	ledger          *ledger
+	limits          *limitChecker
+	fees            *fees.Schedule  // nil charges no fees
+	rates           fx.RateProvider // Used only for cross-currency transfers; may be nil
+}
+
+func newTransferBookings(accountRepo repositories.AccountRepository, transactionRepo repositories.TransactionRepository, transferRepo repositories.TransferRepository, ledgerRepo repositories.LedgerRepository, holdRepo repositories.HoldRepository, limitRepo repositories.LimitRepository, feeSchedule *fees.Schedule, rates fx.RateProvider) *transferBookings {
+	return &transferBookings{
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		transferRepo:    transferRepo,
+		holdRepo:        holdRepo,
+		ledger:          newLedger(ledgerRepo, accountRepo),
+		limits:          newLimitChecker(limitRepo),
+		fees:            feeSchedule,
+		rates:           rates,
+	}
+}
+
+// transferPlan is a transfer that passed every business check and is ready to be booked.
+type transferPlan struct {
+	from, to     *models.Account // Locked until the transaction ends
+	amount       money.Money     // In the sender's currency
+	fee          money.Money     // Charged to the sender on top of amount; zero if none
+	overdraftFee money.Money     // Charged if the transfer takes the sender into overdraft; zero if not
+	conversion   *fx.Conversion  // nil for same-currency transfers
+}
+
+// total is everything the transfer takes from the sender: the amount and both fees.
+func (p *transferPlan) total() money.Money {
+	return p.amount.Add(p.fee).Add(p.overdraftFee)
+}
+
+// plan locks both accounts, binds the amount to the sender's currency, converts it if needed and
+// checks status, available funds (for the amount plus the transfer and overdraft fees) and the
+// sender's limits. reserved is the hold already set aside for this transfer (when an approved
+// transfer is booked); its amount counts as available. Limits are checked again on approval, since
+// only booked transfers count towards them. plan writes nothing, so a caller may keep using tx
+// after a business error such as ErrInsufficientFunds.
+func (b *transferBookings) plan(tx *sql.Tx, fromAccountID, toAccountID int, requested money.Money, reserved *models.Hold) (*transferPlan, error) {
+	locked, err := lockAccountsInOrder(tx, b.accountRepo, fromAccountID, toAccountID)
+	if err != nil {
+		return nil, err
+	}
+	plan := &transferPlan{from: locked[fromAccountID], to: locked[toAccountID]}
+
+	// The amount is always in the sender's currency; the receiver may hold another currency
+	plan.amount, err = requested.In(plan.from.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer amount: %w", err)
+	}
+	if plan.to.Currency != plan.from.Currency {
+		if b.rates == nil {
+			return nil, fmt.Errorf("no exchange rate provider configured: %w", fx.ErrRateNotFound)
+		}
+		plan.conversion, err = fx.Convert(b.rates, plan.amount, plan.to.Currency, config.FXSpread())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert transfer amount: %w", err)
+		}
+		if !plan.conversion.Destination.IsPositive() {
+			return nil, fmt.Errorf("converted amount rounds to zero: %w", money.ErrTooPrecise)
+		}
+	}
+
+	// Frozen, dormant and closed accounts cannot send; closed accounts cannot receive
+	if err := checkDebit(plan.from); err != nil {
+		return nil, err
+	}
+	if err := checkCredit(plan.to); err != nil {
+		return nil, err
+	}
+
+	// Check the available balance (ledger balance minus holds) on the locked row
+	plan.fee = b.fees.Fee(fees.OpTransfer, string(plan.from.Type), plan.amount)
+	plan.overdraftFee = overdraftFee(plan.from, plan.amount.Add(plan.fee))
+	available := plan.from.AvailableBalance
+	if reserved != nil {
+		available = available.Add(reserved.Amount)
+	}
+	if available.LessThan(plan.total()) {
+		return nil, fmt.Errorf("insufficient balance in sender's account: %w", ErrInsufficientFunds)
+	}
+	if err := b.limits.check(tx, plan.from, plan.amount, true); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// reserve records plan as a transfer awaiting approval: the amount and the fees are held on the
+// sender's account and nothing is posted until an approver decides.
+func (b *transferBookings) reserve(tx *sql.Tx, plan *transferPlan, description string, requestedBy int, expiresAt time.Time) (int64, error) {
+	holdID, err := placeHold(tx, b.accountRepo, b.holdRepo, &models.Hold{
+		AccountID: plan.from.ID,
+		Type:      models.HoldTypeTransfer,
+		Amount:    plan.total(),
+		Reason:    "Transfer awaiting approval to " + plan.to.AccountNumber,
+		CreatedBy: requestedBy,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// The destination amount, FX and fee are an estimate until approval, when they are computed again
+	destination := plan.amount
+	if plan.conversion != nil {
+		destination = plan.conversion.Destination
+	}
+	transferID, err := b.transferRepo.CreateTransfer(tx, &models.Transfer{
+		FromAccountID:     plan.from.ID,
+		ToAccountID:       plan.to.ID,
+		Amount:            plan.amount,
+		DestinationAmount: destination,
+		Fee:               plan.fee,
+		FX:                transferFX(plan.conversion),
+		Description:       description,
+		Status:            models.TransferPendingApproval,
+		Approval:          &models.TransferApproval{RequestedBy: requestedBy, ExpiresAt: expiresAt, HoldID: &holdID},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record pending transfer: %w", err)
+	}
+	return transferID, nil
+}
+
+// book moves plan.amount from one locked account to another inside tx and returns the new transfer
+// ID. For a cross-currency plan the receiver is credited plan.conversion.Destination in its own
+// currency. Callers must have locked both rows and checked status, currency and funds beforehand.
+func (b *transferBookings) book(tx *sql.Tx, plan *transferPlan, description string) (int64, error) {
+	transfer, err := b.post(tx, plan, description)
+	if err != nil {
+		return 0, err
+	}
+	transferID, err := b.transferRepo.CreateTransfer(tx, transfer)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record transfer: %w", err)
+	}
+	return transferID, nil
+}
+
+// post writes the journal entry and the transaction legs of a transfer (plus the fees, if any) and
+// returns the completed transfer record, which the caller stores.
+func (b *transferBookings) post(tx *sql.Tx, plan *transferPlan, description string) (*models.Transfer, error) {
+	fromAccount, toAccount, amount, conversion := plan.from, plan.to, plan.amount, plan.conversion
+
+	// Every leg goes into a single journal entry: debit the sender, credit the receiver, and
+	// move the fees from the sender to fee income. Posting also updates both account balances.
+	fromLedger, err := b.ledger.customerAccount(tx, fromAccount)
+	if err != nil {
+		return nil, err
+	}
+	toLedger, err := b.ledger.customerAccount(tx, toAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	credited := amount
+	postings := []models.Posting{models.Debit(fromLedger, amount)}
+	if conversion == nil {
+		postings = append(postings, models.Credit(toLedger, amount))
+	} else {
+		credited = conversion.Destination
+		fxPostings, err := b.fxPostings(tx, conversion, toLedger)
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, fxPostings...)
+	}
+	for _, fee := range []money.Money{plan.fee, plan.overdraftFee} {
+		if fee.IsZero() {
+			continue
+		}
+		charged, err := feePostings(tx, b.ledger, fromLedger, fee)
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, charged...)
+	}
+
+	entryID, err := b.ledger.post(tx, &models.JournalEntry{
+		Description: fmt.Sprintf("Transfer %s -> %s: %s", fromAccount.AccountNumber, toAccount.AccountNumber, description),
+		Postings:    postings,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to post transfer: %w", err)
+	}
+
+	// Record outbound transaction for sender
+	outboundTransaction := &models.Transaction{
+		AccountID:       fromAccount.ID,
+		TransactionType: models.TransactionTransferOut,
+		Amount:          amount,
+		Description:     fmt.Sprintf("Transfer to %s: %s", toAccount.AccountNumber, description),
+		JournalEntryID:  entryID,
+	}
+	outboundID, err := b.transactionRepo.CreateTransaction(tx, outboundTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record outbound transaction: %w", err)
+	}
+
+	// Record inbound transaction for receiver
+	inboundTransaction := &models.Transaction{
+		AccountID:       toAccount.ID,
+		TransactionType: models.TransactionTransferIn,
+		Amount:          credited,
+		Description:     fmt.Sprintf("Transfer from %s: %s", fromAccount.AccountNumber, description),
+		JournalEntryID:  entryID,
+	}
+	inboundID, err := b.transactionRepo.CreateTransaction(tx, inboundTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record inbound transaction: %w", err)
+	}
+
+	if !plan.fee.IsZero() {
+		err := recordFee(tx, b.transactionRepo, fromAccount, plan.fee, entryID, "Transfer fee: to "+toAccount.AccountNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !plan.overdraftFee.IsZero() {
+		if err := recordFee(tx, b.transactionRepo, fromAccount, plan.overdraftFee, entryID, "Overdraft fee"); err != nil {
+			return nil, err
+		}
+	}
+
+	// The transfer record ties both legs together
+	outID, inID := int(outboundID), int(inboundID)
+	return &models.Transfer{
+		FromAccountID:         fromAccount.ID,
+		ToAccountID:           toAccount.ID,
+		Amount:                amount,
+		DestinationAmount:     credited,
+		Fee:                   plan.fee,
+		FX:                    transferFX(conversion),
+		Description:           description,
+		Status:                models.TransferCompleted,
+		OutboundTransactionID: &outID,
+		InboundTransactionID:  &inID,
+		JournalEntryID:        &entryID,
+	}, nil
+}
+
+// fxPostings balances a cross-currency transfer through the bank's FX position in each currency:
+// the source amount is credited to the source-currency position, the mid-rate equivalent is
+// debited from the destination-currency position, and that is split between the receiver and
+// the spread kept as fee income. Each currency sums to zero on its own.
+func (b *transferBookings) fxPostings(tx *sql.Tx, conversion *fx.Conversion, toLedger *models.LedgerAccount) ([]models.Posting, error) {
+	sourcePosition, err := b.ledger.systemAccount(tx, models.LedgerFXPosition, conversion.Source.Currency())
+	if err != nil {
+		return nil, err
+	}
+	destinationPosition, err := b.ledger.systemAccount(tx, models.LedgerFXPosition, conversion.Gross.Currency())
+	if err != nil {
+		return nil, err
+	}
+	postings := []models.Posting{
+		models.Credit(sourcePosition, conversion.Source),
+		models.Debit(destinationPosition, conversion.Gross),
+		models.Credit(toLedger, conversion.Destination),
+	}
+	if !conversion.Spread.IsZero() {
+		feesIncome, err := b.ledger.systemAccount(tx, models.LedgerFeesIncome, conversion.Spread.Currency())
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, models.Credit(feesIncome, conversion.Spread))
+	}
+	return postings, nil
+}
+
+// transferFX describes conversion for the transfer record, or returns nil for same-currency transfers.
+func transferFX(conversion *fx.Conversion) *models.TransferFX {
+	if conversion == nil {
+		return nil
+	}
+	return &models.TransferFX{
+		MidRate:     conversion.Rate.String(),
+		AppliedRate: fx.FormatRate(conversion.AppliedRate),
+		RateSource:  conversion.Rate.Source,
+		RateAsOf:    conversion.Rate.AsOf,
+		Spread:      conversion.Spread,
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
+
+	"go-bank-app/config" // For accessing config.DB.Begin()
+	"go-bank-app/models"
+	"go-bank-app/repositories"
+)
+
+// maxTxAttempts bounds how many times a money-moving transaction is retried after the database
+// aborts it because of a deadlock, a lock wait timeout or a serialization failure.
+const maxTxAttempts = 3
+
+// runInTx executes fn inside a database transaction and commits it. If the database reports a
+// deadlock or lock wait timeout anywhere in fn or at commit, the whole transaction is
+// rolled back and fn is run again from the start, so fn must not keep state between calls.
+func runInTx(fn func(tx *sql.Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = runInTxOnce(fn)
+		if err == nil || !isRetryableTxError(err) {
+			return err
+		}
+		log.Printf("Retrying transaction after lock conflict (attempt %d/%d): %v", attempt, maxTxAttempts, err)
+		time.Sleep(time.Duration(attempt*20+rand.Intn(20)) * time.Millisecond)
+	}
+	return err
+}
+
+func runInTxOnce(fn func(tx *sql.Tx) error) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return &rolledBackError{fmt.Errorf("failed to begin transaction: %w", err)}
+	}
+	defer tx.Rollback() // No-op once committed
+
+	if err := fn(tx); err != nil {
+		return &rolledBackError{err}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// rolledBackError wraps an error after which runInTx discarded everything the transaction wrote.
+// It is transparent to errors.Is and errors.As. A failed commit is not wrapped: the database may
+// have committed before the connection broke.
+type rolledBackError struct{ err error }
+
+func (e *rolledBackError) Error() string { return e.err.Error() }
+func (e *rolledBackError) Unwrap() error { return e.err }
+
+// RolledBack reports whether err comes from a database transaction that was rolled back, so the
+// failed operation left the database as it found it. Errors from outside runInTx, such as a lookup
+// before the transaction or a re-read after it committed, report false.
+func RolledBack(err error) bool {
+	var rb *rolledBackError
+	return errors.As(err, &rb)
+}
+
+// isRetryableTxError reports whether err (or anything it wraps) is a lock conflict that the
+// configured database dialect considers safe to retry.
+func isRetryableTxError(err error) bool {
+	return config.Dialect().IsRetryable(err)
+}
+
+// lockAccountsInOrder takes FOR UPDATE locks on the given accounts in ascending ID order.
+// Every code path that locks several accounts goes through here, so two transfers running in
+// opposite directions always request the locks in the same order and cannot deadlock each other.
+func lockAccountsInOrder(tx *sql.Tx, accountRepo repositories.AccountRepository, accountIDs ...int) (map[int]*models.Account, error) {
+	ids := append([]int(nil), accountIDs...)
+	sort.Ints(ids)
+
+	locked := make(map[int]*models.Account, len(ids))
+	for _, id := range ids {
+		if _, ok := locked[id]; ok {
+			continue
+		}
+		account, err := accountRepo.GetAccountByIDForUpdate(tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock account %d: %w", id, err)
+		}
+		locked[id] = account
+	}
+	return locked, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"go-bank-app/models"
+	"go-bank-app/money"
+	"go-bank-app/repositories"
+)
+
+// lockRecorder records the order in which account rows are locked.
+type lockRecorder struct {
+	repositories.AccountRepository
+	mu     sync.Mutex
+	locked []int
+}
+
+func (r *lockRecorder) GetAccountByIDForUpdate(tx *sql.Tx, id int) (*models.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.locked = append(r.locked, id)
+	return &models.Account{ID: id, Currency: money.IDR, Status: models.AccountActive}, nil
+}
+
+func TestLockAccountsInOrder(t *testing.T) {
+	tests := []struct {
+		ids  []int
+		want []int
+	}{
+		{[]int{7, 3}, []int{3, 7}},
+		{[]int{3, 7}, []int{3, 7}},
+		{[]int{9, 2, 5}, []int{2, 5, 9}},
+		{[]int{4, 4}, []int{4}}, // A transfer to the same account locks it once
+	}
+	for _, tt := range tests {
+		repo := &lockRecorder{}
+		locked, err := lockAccountsInOrder(nil, repo, tt.ids...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(repo.locked, tt.want) {
+			t.Errorf("lockAccountsInOrder(%v) locked %v, want %v", tt.ids, repo.locked, tt.want)
+		}
+		for _, id := range tt.ids {
+			if locked[id] == nil || locked[id].ID != id {
+				t.Errorf("lockAccountsInOrder(%v) did not return account %d", tt.ids, id)
+			}
+		}
+	}
+}
+
+// TestTransferLocksInAccountOrder checks that a transfer locks both accounts through
+// lockAccountsInOrder whichever direction it goes, so opposite transfers cannot deadlock.
+func TestTransferLocksInAccountOrder(t *testing.T) {
+	for _, dir := range [][2]int{{3, 8}, {8, 3}} {
+		repo := &lockRecorder{}
+		b := &transferBookings{accountRepo: repo}
+		// Both accounts are empty, so planning stops after the locks are taken
+		if _, err := b.plan(nil, dir[0], dir[1], money.MustParse("100", money.IDR), nil); !errors.Is(err, ErrInsufficientFunds) {
+			t.Fatalf("plan %d -> %d: got %v, want ErrInsufficientFunds", dir[0], dir[1], err)
+		}
+		if !reflect.DeepEqual(repo.locked, []int{3, 8}) {
+			t.Errorf("transfer %d -> %d locked %v, want [3 8]", dir[0], dir[1], repo.locked)
+		}
+	}
+}
+
+// TestRolledBack checks that the marker runInTx puts on errors survives wrapping, and that it does
+// not hide the domain error the HTTP layer maps to a status code.
+func TestRolledBack(t *testing.T) {
+	inTx := fmt.Errorf("withdraw failed: %w", &rolledBackError{ErrInsufficientFunds})
+	if !RolledBack(inTx) {
+		t.Error("RolledBack = false for an error from a rolled back transaction")
+	}
+	if !errors.Is(inTx, ErrInsufficientFunds) || inTx.Error() != "withdraw failed: insufficient funds" {
+		t.Errorf("marker changed the error: %v", inTx)
+	}
+	if RolledBack(fmt.Errorf("deposit succeeded, but failed to retrieve updated account: %w", sql.ErrConnDone)) {
+		t.Error("RolledBack = true for an error raised after the transaction committed")
+	}
+}
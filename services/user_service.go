@@ -2,9 +2,10 @@
 package services
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
 	"go-bank-app/auth" // Untuk hashing password
+	"go-bank-app/i18n"
 	"go-bank-app/models"
 	"go-bank-app/repositories" // Untuk menggunakan repository
 )
@@ -12,28 +13,31 @@ import (
 // UserService adalah interface untuk logika bisnis User.
 type UserService interface {
 	RegisterUser(req *models.CreateUserRequest) (*models.User, error)
-	LoginUser(email, password string) (string, *models.User, error) // Mengembalikan token dan user
+	LoginUser(email, password string) (*models.TokenPair, *models.User, error) // Mengembalikan pasangan token dan user
 	GetUserByID(id int) (*models.User, error)
 	GetAllUsers() ([]models.User, error)
+	UpdateUserRole(id int, role string) (*models.User, error)
+	UpdatePreferredLanguage(id int, language string) (*models.User, error)
 }
 
 // userServiceImpl adalah implementasi konkrit dari UserService.
 type userServiceImpl struct {
-	userRepo repositories.UserRepository
+	userRepo     repositories.UserRepository
+	tokenService TokenService // Untuk menerbitkan access token dan refresh token saat login
 }
 
 // NewUserService membuat instance baru dari UserService.
-func NewUserService(userRepo repositories.UserRepository) UserService {
-	return &userServiceImpl{userRepo: userRepo}
+func NewUserService(userRepo repositories.UserRepository, tokenService TokenService) UserService {
+	return &userServiceImpl{userRepo: userRepo, tokenService: tokenService}
 }
 
 func (s *userServiceImpl) RegisterUser(req *models.CreateUserRequest) (*models.User, error) {
 	existingUser, err := s.userRepo.GetUserByEmail(req.Email)
-	if err != nil && err != sql.ErrNoRows {
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
 		return nil, fmt.Errorf("gagal memeriksa email user: %w", err)
 	}
 	if existingUser != nil {
-		return nil, fmt.Errorf("email sudah terdaftar")
+		return nil, ErrDuplicateEmail
 	}
 
 	hashedPassword, err := auth.HashPassword(req.Password)
@@ -42,13 +46,15 @@ func (s *userServiceImpl) RegisterUser(req *models.CreateUserRequest) (*models.U
 	}
 
 	user := &models.User{
-		Name:         req.Name,
-		Email:        req.Email,
-		PasswordHash: hashedPassword,
+		Name:              req.Name,
+		Email:             req.Email,
+		PasswordHash:      hashedPassword,
+		PreferredLanguage: req.PreferredLanguage,
 	}
 
 	id, err := s.userRepo.CreateUser(user)
 	if err != nil {
+		// Pendaftaran bersamaan dengan email yang sama baru ketahuan di unique key
 		return nil, fmt.Errorf("gagal membuat user di database: %w", err)
 	}
 	user.ID = int(id) // Konversi int64 ke int
@@ -62,25 +68,26 @@ func (s *userServiceImpl) RegisterUser(req *models.CreateUserRequest) (*models.U
 	return newUser, nil
 }
 
-func (s *userServiceImpl) LoginUser(email, password string) (string, *models.User, error) {
+func (s *userServiceImpl) LoginUser(email, password string) (*models.TokenPair, *models.User, error) {
 	user, err := s.userRepo.GetUserByEmail(email)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return "", nil, fmt.Errorf("kredensial tidak valid")
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, nil, ErrInvalidCredentials
 		}
-		return "", nil, fmt.Errorf("gagal mengambil user: %w", err)
+		return nil, nil, fmt.Errorf("gagal mengambil user: %w", err)
 	}
 
 	if !auth.CheckPasswordHash(password, user.PasswordHash) {
-		return "", nil, fmt.Errorf("kredensial tidak valid")
+		return nil, nil, ErrInvalidCredentials
 	}
 
-	token, err := auth.GenerateJWTToken(user.ID)
+	// Setiap login memulai sesi (keluarga token) baru
+	tokens, err := s.tokenService.IssueTokens(user.ID)
 	if err != nil {
-		return "", nil, fmt.Errorf("gagal menghasilkan token: %w", err)
+		return nil, nil, fmt.Errorf("gagal menghasilkan token: %w", err)
 	}
 
-	return token, user, nil
+	return tokens, user, nil
 }
 
 func (s *userServiceImpl) GetUserByID(id int) (*models.User, error) {
@@ -98,3 +105,36 @@ func (s *userServiceImpl) GetAllUsers() ([]models.User, error) {
 	}
 	return users, nil
 }
+
+// UpdateUserRole mengganti peran user. Izin baru berlaku saat user menerima access token berikutnya.
+func (s *userServiceImpl) UpdateUserRole(id int, role string) (*models.User, error) {
+	if !models.IsValidRole(role) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+	}
+	if _, err := s.userRepo.GetUserByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateUserRole(id, role); err != nil {
+		return nil, fmt.Errorf("gagal mengubah peran user: %w", err)
+	}
+	return s.userRepo.GetUserByID(id)
+}
+
+// UpdatePreferredLanguage mengganti bahasa pesan error user ("" menghapus preferensi). Seperti peran,
+// bahasa dibawa di access token sehingga berlaku saat token berikutnya diterbitkan.
+func (s *userServiceImpl) UpdatePreferredLanguage(id int, language string) (*models.User, error) {
+	if language != "" {
+		lang, err := i18n.Parse(language)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidLanguage, language)
+		}
+		language = string(lang)
+	}
+	if _, err := s.userRepo.GetUserByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdatePreferredLanguage(id, language); err != nil {
+		return nil, fmt.Errorf("gagal mengubah bahasa user: %w", err)
+	}
+	return s.userRepo.GetUserByID(id)
+}
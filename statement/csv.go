@@ -0,0 +1,56 @@
+// go-bank-app/statement/csv.go
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"go-bank-app/models"
+)
+
+// WriteCSV writes one row per transaction, framed by an opening and a closing balance row:
+//
+//	date,transaction_id,type,description,debit,credit,balance,currency
+//
+// Accounts with an overdraft get three more rows after the closing balance (overdraft_limit,
+// overdraft_used and peak_overdraft_used) carrying their amount in the balance column.
+// Amounts are plain decimals with a dot separator so spreadsheets and accounting tools parse them.
+func WriteCSV(w io.Writer, st *models.Statement) error {
+	cw := csv.NewWriter(w)
+	currency := st.Currency.String()
+
+	rows := [][]string{
+		{"date", "transaction_id", "type", "description", "debit", "credit", "balance", "currency"},
+		{st.From.Format(dateTimeLayout), "", "opening_balance", "Opening balance", "", "", st.OpeningBalance.String(), currency},
+	}
+	for _, line := range st.Lines {
+		rows = append(rows, []string{
+			line.TransactionDate.Format(dateTimeLayout),
+			strconv.Itoa(line.TransactionID),
+			line.TransactionType,
+			line.Description,
+			amountOrBlank(line.Debit),
+			amountOrBlank(line.Credit),
+			line.Balance.String(),
+			currency,
+		})
+	}
+	rows = append(rows, []string{
+		st.To.Format(dateTimeLayout), "", "closing_balance", "Closing balance",
+		st.TotalDebits.String(), st.TotalCredits.String(), st.ClosingBalance.String(), currency,
+	})
+	if st.HasOverdraft() {
+		date := st.To.Format(dateTimeLayout)
+		rows = append(rows,
+			[]string{date, "", "overdraft_limit", "Overdraft limit", "", "", st.OverdraftLimit.String(), currency},
+			[]string{date, "", "overdraft_used", "Overdraft used", "", "", st.OverdraftUsed.String(), currency},
+			[]string{date, "", "peak_overdraft_used", "Peak overdraft used", "", "", st.PeakOverdraftUsed.String(), currency},
+		)
+	}
+
+	if err := cw.WriteAll(rows); err != nil { // WriteAll flushes
+		return err
+	}
+	return cw.Error()
+}
@@ -0,0 +1,146 @@
+// go-bank-app/statement/ofx.go
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"go-bank-app/config"
+	"go-bank-app/models"
+)
+
+// OFX 2.2 (XML) bank statement response. Only the elements accounting software needs to import
+// a statement are emitted.
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Response struct {
+			Status   ofxStatus `xml:"STATUS"`
+			DTServer string    `xml:"DTSERVER"`
+			Language string    `xml:"LANGUAGE"`
+		} `xml:"SONRS"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Bank struct {
+		Transaction struct {
+			TrnUID    string          `xml:"TRNUID"`
+			Status    ofxStatus       `xml:"STATUS"`
+			Statement ofxStmtResponse `xml:"STMTRS"`
+		} `xml:"STMTTRNRS"`
+	} `xml:"BANKMSGSRSV1"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxStmtResponse struct {
+	Currency string `xml:"CURDEF"`
+	Account  struct {
+		BankID   string `xml:"BANKID"`
+		AcctID   string `xml:"ACCTID"`
+		AcctType string `xml:"ACCTTYPE"`
+	} `xml:"BANKACCTFROM"`
+	TransactionList struct {
+		DTStart      string           `xml:"DTSTART"`
+		DTEnd        string           `xml:"DTEND"`
+		Transactions []ofxTransaction `xml:"STMTTRN"`
+	} `xml:"BANKTRANLIST"`
+	LedgerBalance ofxBalance `xml:"LEDGERBAL"`
+}
+
+type ofxTransaction struct {
+	Type     string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	Amount   string `xml:"TRNAMT"`
+	FitID    string `xml:"FITID"`
+	Name     string `xml:"NAME,omitempty"`
+	Memo     string `xml:"MEMO,omitempty"`
+}
+
+type ofxBalance struct {
+	Amount string `xml:"BALAMT"`
+	AsOf   string `xml:"DTASOF"`
+}
+
+// ofxTime formats t as an OFX datetime with explicit UTC offset, e.g. 20240131235959.000[+0:UTC].
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[+0:UTC]"
+}
+
+// ofxTransactionTypes maps our transaction types to OFX TRNTYPE values.
+var ofxTransactionTypes = map[string]string{
+	models.TransactionDeposit:           "DEP",
+	models.TransactionWithdraw:          "ATM",
+	models.TransactionTransferOut:       "XFER",
+	models.TransactionTransferIn:        "XFER",
+	models.TransactionCapture:           "POS",
+	models.TransactionReversalIn:        "CREDIT",
+	models.TransactionReversalOut:       "DEBIT",
+	models.TransactionFee:               "FEE",
+	models.TransactionInterest:          "INT",
+	models.TransactionOverdraftInterest: "INT",
+}
+
+// WriteOFX writes the statement as an OFX 2.2 bank statement download.
+func WriteOFX(w io.Writer, st *models.Statement) error {
+	var doc ofxDocument
+	ok := ofxStatus{Code: 0, Severity: "INFO"}
+	doc.SignOn.Response.Status = ok
+	doc.SignOn.Response.DTServer = ofxTime(st.GeneratedAt)
+	doc.SignOn.Response.Language = "ENG"
+
+	doc.Bank.Transaction.TrnUID = strconv.FormatInt(st.GeneratedAt.UnixNano(), 10)
+	doc.Bank.Transaction.Status = ok
+
+	rs := &doc.Bank.Transaction.Statement
+	rs.Currency = st.Currency.String()
+	rs.Account.BankID = config.AppCfg.AccountNumber.BranchPrefix
+	rs.Account.AcctID = st.AccountNumber
+	rs.Account.AcctType = "CHECKING"
+	rs.TransactionList.DTStart = ofxTime(st.From)
+	rs.TransactionList.DTEnd = ofxTime(st.To)
+	for _, line := range st.Lines {
+		trnType, ok := ofxTransactionTypes[line.TransactionType]
+		if !ok {
+			trnType = "OTHER"
+		}
+		amount := line.Credit
+		if !line.Debit.IsZero() {
+			amount = line.Debit.Neg()
+		}
+		rs.TransactionList.Transactions = append(rs.TransactionList.Transactions, ofxTransaction{
+			Type:     trnType,
+			DTPosted: ofxTime(line.TransactionDate),
+			Amount:   amount.String(),
+			FitID:    strconv.Itoa(line.TransactionID), // Transaction IDs are unique, so importers can de-duplicate
+			Name:     truncate(line.TransactionType, 32),
+			Memo:     truncate(line.Description, 255),
+		})
+	}
+	rs.LedgerBalance = ofxBalance{Amount: st.ClosingBalance.String(), AsOf: ofxTime(st.To)}
+
+	header := `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+		`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -0,0 +1,237 @@
+// go-bank-app/statement/pdf.go
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-bank-app/models"
+)
+
+// Page geometry in PDF points (1/72 inch): A4 portrait with 40pt margins.
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	pageMargin   = 40.0
+	tableFont    = 8.0  // Courier size for the transaction table
+	tableLeading = 11.0 // Vertical distance between table rows
+)
+
+// Table column widths in characters. Courier is monospaced (0.6em per glyph), so padding with
+// spaces is enough to align the amount columns to the right.
+const (
+	colDate        = 19
+	colDescription = 36
+	colAmount      = 15
+	colBalance     = 16
+)
+
+// WritePDF renders the statement as a PDF document. The document is written by hand using only
+// the standard 14 fonts, which every PDF reader provides, so no font files or external tools
+// are needed.
+func WritePDF(w io.Writer, st *models.Statement) error {
+	rows := make([]string, 0, len(st.Lines)+2)
+	rows = append(rows, tableRow(st.From.Format(dateTimeLayout), "Opening balance", "", "", st.OpeningBalance.String()))
+	for _, line := range st.Lines {
+		rows = append(rows, tableRow(line.TransactionDate.Format(dateTimeLayout), line.Description,
+			amountOrBlank(line.Debit), amountOrBlank(line.Credit), line.Balance.String()))
+	}
+	rows = append(rows, tableRow(st.To.Format(dateTimeLayout), "Closing balance", st.TotalDebits.String(), st.TotalCredits.String(), st.ClosingBalance.String()))
+
+	summary := [][2]string{
+		{"Opening balance", st.OpeningBalance.String()},
+		{"Total credits", st.TotalCredits.String()},
+		{"Total debits", st.TotalDebits.String()},
+		{"Closing balance", st.ClosingBalance.String()},
+	}
+	if st.HasOverdraft() {
+		summary = append(summary,
+			[2]string{"Overdraft limit", st.OverdraftLimit.String()},
+			[2]string{"Overdraft used", st.OverdraftUsed.String()},
+			[2]string{"Peak overdraft", st.PeakOverdraftUsed.String()},
+		)
+	}
+
+	// The first page also carries the summary block, so it holds fewer rows.
+	top := pageHeight - pageMargin - 90
+	firstTop := top - 18 - 13*float64(len(summary))
+	bottom := pageMargin + 30
+	perPage := int((top - bottom) / tableLeading)
+	perFirstPage := int((firstTop - bottom) / tableLeading)
+
+	var pages [][]string
+	pages = append(pages, rows[:min(perFirstPage, len(rows))])
+	for rest := rows[len(pages[0]):]; len(rest) > 0; {
+		n := min(perPage, len(rest))
+		pages = append(pages, rest[:n])
+		rest = rest[n:]
+	}
+
+	doc := &pdfDocument{}
+	for i, pageRows := range pages {
+		p := doc.newPage()
+		y := pageHeight - pageMargin - 14
+		p.text(fontBold, 14, pageMargin, y, "Account Statement")
+		p.text(fontRegular, 9, pageWidth-pageMargin-80, y, fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+		y -= 20
+		p.text(fontRegular, 10, pageMargin, y, "Account number: "+st.AccountNumber)
+		y -= 14
+		p.text(fontRegular, 10, pageMargin, y, "Currency: "+st.Currency.String())
+		y -= 14
+		p.text(fontRegular, 10, pageMargin, y, fmt.Sprintf("Period: %s to %s (exclusive)", st.From.Format(dateTimeLayout), st.To.Format(dateTimeLayout)))
+
+		rowTop := top
+		if i == 0 {
+			y = top - 8
+			for _, kv := range summary {
+				p.text(fontMono, 9, pageMargin, y, fmt.Sprintf("%-16s %20s", kv[0], kv[1]))
+				y -= 13
+			}
+			rowTop = firstTop
+		}
+
+		p.text(fontMonoBold, tableFont, pageMargin, rowTop, tableRow("Date", "Description", "Debit", "Credit", "Balance"))
+		p.line(pageMargin, rowTop-3, pageWidth-pageMargin, rowTop-3)
+		y = rowTop - tableLeading - 2
+		for _, row := range pageRows {
+			p.text(fontMono, tableFont, pageMargin, y, row)
+			y -= tableLeading
+		}
+
+		p.line(pageMargin, pageMargin+18, pageWidth-pageMargin, pageMargin+18)
+		p.text(fontRegular, 7, pageMargin, pageMargin+6, "Generated "+st.GeneratedAt.Format(dateTimeLayout+" MST")+". Balances are in "+st.Currency.String()+".")
+	}
+	return doc.writeTo(w)
+}
+
+// tableRow lays out one fixed-width table row.
+func tableRow(date, description, debit, credit, balance string) string {
+	return fmt.Sprintf("%-*s  %-*s %*s %*s %*s",
+		colDate, truncate(date, colDate),
+		colDescription, truncate(description, colDescription),
+		colAmount, debit, colAmount, credit, colBalance, balance)
+}
+
+// Fonts available to pages; the names match the resources written by pdfDocument.writeTo.
+const (
+	fontRegular  = "F1" // Helvetica
+	fontBold     = "F2" // Helvetica-Bold
+	fontMono     = "F3" // Courier
+	fontMonoBold = "F4" // Courier-Bold
+)
+
+var pdfFonts = []struct{ name, base string }{
+	{fontRegular, "Helvetica"},
+	{fontBold, "Helvetica-Bold"},
+	{fontMono, "Courier"},
+	{fontMonoBold, "Courier-Bold"},
+}
+
+// pdfDocument is a minimal PDF 1.4 writer: text and lines on A4 pages, uncompressed content streams.
+type pdfDocument struct {
+	pages []*pdfPage
+}
+
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (d *pdfDocument) newPage() *pdfPage {
+	p := &pdfPage{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// text draws s with its baseline starting at (x, y).
+func (p *pdfPage) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+// line draws a thin horizontal or vertical rule.
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// writeTo serializes the document. Object layout: 1 catalog, 2 page tree, 3.. fonts, then a
+// page object and a content stream per page.
+func (d *pdfDocument) writeTo(w io.Writer) error {
+	var buf bytes.Buffer
+	var offsets []int
+	beginObj := func() int {
+		offsets = append(offsets, buf.Len())
+		id := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n", id)
+		return id
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	firstFont := 3
+	firstPage := firstFont + len(pdfFonts)
+	pageID := func(i int) int { return firstPage + 2*i }
+
+	beginObj()
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+
+	beginObj()
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageID(i))
+	}
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(d.pages))
+
+	var fontRefs strings.Builder
+	for i, f := range pdfFonts {
+		beginObj()
+		fmt.Fprintf(&buf, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\nendobj\n", f.base)
+		fmt.Fprintf(&fontRefs, "/%s %d 0 R ", f.name, firstFont+i)
+	}
+
+	for i, p := range d.pages {
+		id := beginObj()
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s>> >> /Contents %d 0 R >>\nendobj\n",
+			pageWidth, pageHeight, fontRefs.String(), id+1)
+		if id != pageID(i) {
+			return fmt.Errorf("pdf: unexpected object id %d for page %d", id, i)
+		}
+
+		beginObj()
+		fmt.Fprintf(&buf, "<< /Length %d >>\nstream\n", p.content.Len())
+		buf.Write(p.content.Bytes())
+		buf.WriteString("endstream\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// pdfString escapes s for a PDF literal string in WinAnsiEncoding. Characters outside Latin-1
+// cannot be shown by the standard fonts and are replaced with '?'.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f:
+			b.WriteByte(byte(r))
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+// go-bank-app/statement/statement.go
+
+// Package statement renders account statements (models.Statement) as CSV, OFX or PDF.
+// Every renderer is plain Go with no external service or cgo dependency.
+package statement
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go-bank-app/models"
+	"go-bank-app/money"
+)
+
+// Format is an output format for a statement.
+type Format string
+
+const (
+	CSV Format = "csv"
+	OFX Format = "ofx"
+	PDF Format = "pdf"
+)
+
+// ParseFormat validates a format name such as "csv", "ofx" or "pdf".
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, OFX, PDF:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported statement format %q, want csv, ofx or pdf", s)
+	}
+}
+
+// ContentType is the MIME type to serve the format with.
+func (f Format) ContentType() string {
+	switch f {
+	case OFX:
+		return "application/x-ofx"
+	case PDF:
+		return "application/pdf"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Filename suggests a download name, e.g. "statement-00100000042xx-20240101-20240131.pdf".
+// The end date shown is the last day included in the statement.
+func (f Format) Filename(st *models.Statement) string {
+	last := st.To.AddDate(0, 0, -1)
+	if last.Before(st.From) {
+		last = st.From
+	}
+	return fmt.Sprintf("statement-%s-%s-%s.%s", st.AccountNumber, st.From.Format("20060102"), last.Format("20060102"), f)
+}
+
+// Write renders st in format f.
+func Write(w io.Writer, f Format, st *models.Statement) error {
+	switch f {
+	case CSV:
+		return WriteCSV(w, st)
+	case OFX:
+		return WriteOFX(w, st)
+	case PDF:
+		return WritePDF(w, st)
+	default:
+		return fmt.Errorf("unsupported statement format %q", f)
+	}
+}
+
+// dateTimeLayout is used for transaction timestamps in the CSV and PDF renderings.
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+// amountOrBlank leaves zero debit/credit cells empty so each line shows a single direction.
+func amountOrBlank(m money.Money) string {
+	if m.IsZero() {
+		return ""
+	}
+	return m.String()
+}